@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
 	"github.com/juangracia/gitrespect/internal/report"
 	"github.com/spf13/cobra"
 )
@@ -15,56 +16,127 @@ import (
 var (
 	beforePeriod string
 	afterPeriod  string
+	periodFlags  []string
 )
 
+// comparePeriod is one labeled date range given on the command line.
+type comparePeriod struct {
+	label string
+	start time.Time
+	end   time.Time
+}
+
 var compareCmd = &cobra.Command{
 	Use:   "compare [paths...]",
-	Short: "Compare productivity between two time periods",
-	Long: `Compare your productivity metrics between two time periods.
+	Short: "Compare productivity across time periods",
+	Long: `Compare your productivity metrics between two or more time periods.
 
 Useful for measuring the impact of tooling changes, AI adoption, or other
 workflow improvements.
 
+Use --before/--after for a simple two-period comparison, or repeat
+--period name=RANGE for phased rollouts (pilot, Copilot, a switch to another
+agent, ...). Every period is compared against the first one; each needs a
+name of its own.
+
+A RANGE is FROM:TO (each end anything --since accepts), a single unit such
+as 2025-03-12, 2025-Q3 or 2025-W07, an offset like 2025-08-15+6w, or a
+trailing window like "last 90 days".
+
+The change in lines/day is reported with a 95% bootstrap confidence interval
+and a Mann-Whitney significance test over weekly samples, so a single big
+week doesn't masquerade as a trend. Periods shorter than a few weeks are
+flagged as not having enough data.
+
+Add --metrics to compare commit size, cadence, lead time, churn and reverts
+side by side, --team to compare a group of authors (with a per-member
+breakdown), and --per-repo to see how each repository changed.
+
 Example:
-  gitrespect compare --before=2025-01:2025-07 --after=2025-08:2025-12`,
+  gitrespect compare --before=2025-01:2025-07 --after=2025-08:2025-12
+  gitrespect compare --period baseline=2025-01:2025-03 --period pilot=2025-04:2025-06 \
+    --period copilot=2025-07:2025-12
+  gitrespect compare --period before=2025-02-26+2w --period pilot=2025-03-12+2w
+  gitrespect compare ./api ./web --team=a@x.com,b@x.com --metrics=all --per-repo \
+    --before=2025-01:2025-07 --after=2025-08:2025-12`,
 	RunE: runCompare,
 }
 
 func init() {
-	compareCmd.Flags().StringVar(&beforePeriod, "before", "", "Before period (YYYY-MM:YYYY-MM)")
-	compareCmd.Flags().StringVar(&afterPeriod, "after", "", "After period (YYYY-MM:YYYY-MM)")
+	compareCmd.Flags().StringVar(&beforePeriod, "before", "", "Before period (e.g. 2025-01:2025-07, 2025-Q1, 2025-03-12+2w)")
+	compareCmd.Flags().StringVar(&afterPeriod, "after", "", "After period (e.g. 2025-08:2025-12, 2025-Q3, last 90 days)")
+	compareCmd.Flags().StringArrayVar(&periodFlags, "period", nil, "Labeled period name=RANGE (repeatable; the first is the reference)")
 	compareCmd.Flags().StringVarP(&author, "author", "a", "", "Filter by author email")
-	compareCmd.Flags().StringVarP(&output, "output", "o", "terminal", "Output format: terminal, json, or html")
+	compareCmd.Flags().StringVarP(&output, "output", "o", "terminal", "Output format: terminal, json, html, csv, or markdown")
 	compareCmd.Flags().StringVarP(&file, "file", "f", "", "Output file path")
 	compareCmd.Flags().StringVar(&theme, "theme", "dark", "HTML theme: dark or light")
+	compareCmd.Flags().StringVar(&themeFile, "theme-file", "", "CSS file overriding the HTML theme's colors (CSS variables)")
+	compareCmd.Flags().StringVar(&templateDir, "template", "", "Directory of HTML templates replacing the built-in ones (see gitrespect templates)")
 	compareCmd.Flags().StringSliceVarP(&exclude, "exclude", "e", nil, "Exclude files matching glob patterns")
-
-	compareCmd.MarkFlagRequired("before")
-	compareCmd.MarkFlagRequired("after")
+	compareCmd.Flags().StringSliceVarP(&team, "team", "t", nil, "Team mode: compare multiple authors (comma-separated emails)")
+	compareCmd.Flags().BoolVar(&perRepo, "per-repo", false, "Show breakdown by repository when comparing multiple repos")
+	compareCmd.Flags().StringSliceVar(&excludeCommits, "exclude-commit", nil, "Leave these commits (full or abbreviated SHAs) out of every total, on top of per-repo gitrespect.excludeCommit")
+	compareCmd.Flags().StringVar(&metricsFlag, "metrics", "", "Opt-in metrics: comma list of churn,lead-time,commit-size,cadence,reverts, or 'all'")
+	compareCmd.Flags().StringVar(&churnWindow, "churn-window", "30d", "Churn detection window (also how soon a fix counts against reverts)")
+	compareCmd.Flags().IntSliceVar(&sizeBuckets, "size-buckets", nil, "Commit size bucket edges in lines changed, e.g. 10,50,200,1000 (default: per-repo gitrespect.sizeBuckets, else 10,100,500)")
+	compareCmd.Flags().StringVar(&hotfixBranches, "hotfix-branches", "hotfix/*", "Branches whose work fixes production (glob; empty to ignore)")
+	compareCmd.Flags().StringVar(&deployTags, "deploy-tags", "", "Tags marking deploys (e.g. 'v*' or 'deploy-*') for lead time to deploy and deploy frequency")
+	compareCmd.Flags().StringVar(&deployLog, "deploy-log", "", "Deploy log file (text, .csv or .json), instead of --deploy-tags")
+	compareCmd.Flags().StringSliceVar(&mainBranch, "main-branch", nil, "Integration branches for cadence and lead time, e.g. main,'release/*' (default: per-repo gitrespect.mainBranch, else detected)")
+	compareCmd.Flags().BoolVar(&strict, "strict", false, "Fail when any metric errors instead of reporting it")
 
 	rootCmd.AddCommand(compareCmd)
 }
 
+// parsePeriod parses a compare period using the same grammar as
+// --since/--until (see git.ParseRange).
 func parsePeriod(period string) (time.Time, time.Time, error) {
-	parts := strings.Split(period, ":")
-	if len(parts) != 2 {
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid period format, expected YYYY-MM:YYYY-MM")
-	}
+	return git.ParseRange(period)
+}
 
-	start, err := time.Parse("2006-01", parts[0])
-	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+// parseComparePeriods collects --before/--after and every --period into an
+// ordered list. --before/--after come first when combined with --period.
+func parseComparePeriods() ([]comparePeriod, error) {
+	var periods []comparePeriod
+	if beforePeriod != "" || afterPeriod != "" {
+		if beforePeriod == "" || afterPeriod == "" {
+			return nil, fmt.Errorf("--before and --after must be used together")
+		}
+		for _, p := range []struct{ flag, raw string }{{"--before", beforePeriod}, {"--after", afterPeriod}} {
+			start, end, err := parsePeriod(p.raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", p.flag, err)
+			}
+			periods = append(periods, comparePeriod{label: p.raw, start: start, end: end})
+		}
 	}
-
-	end, err := time.Parse("2006-01", parts[1])
-	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+	for _, raw := range periodFlags {
+		label, rng, ok := strings.Cut(raw, "=")
+		if !ok {
+			label, rng = raw, raw
+		}
+		label = strings.TrimSpace(label)
+		if label == "" {
+			return nil, fmt.Errorf("invalid --period %q: want name=RANGE", raw)
+		}
+		start, end, err := parsePeriod(strings.TrimSpace(rng))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --period %q: %w", raw, err)
+		}
+		periods = append(periods, comparePeriod{label: label, start: start, end: end})
 	}
-
-	// End should be last day of the month
-	end = end.AddDate(0, 1, 0).Add(-time.Second)
-
-	return start, end, nil
+	// Reports and diagnostics tell periods apart by label.
+	seen := make(map[string]bool)
+	for _, p := range periods {
+		if seen[p.label] {
+			return nil, fmt.Errorf("period %q given twice: give each period its own name", p.label)
+		}
+		seen[p.label] = true
+	}
+	if len(periods) < 2 {
+		return nil, fmt.Errorf("need at least two periods: use --before/--after or repeat --period name=RANGE")
+	}
+	return periods, nil
 }
 
 func runCompare(cmd *cobra.Command, args []string) error {
@@ -85,59 +157,181 @@ func runCompare(cmd *cobra.Command, args []string) error {
 		paths[i] = abs
 	}
 
-	beforeStart, beforeEnd, err := parsePeriod(beforePeriod)
+	periods, err := parseComparePeriods()
 	if err != nil {
-		return fmt.Errorf("invalid --before: %w", err)
+		return err
+	}
+
+	authors := team
+	if len(authors) == 0 {
+		authorEmail := author
+		if authorEmail == "" {
+			authorEmail, _ = git.GetDefaultAuthor(paths[0])
+		}
+		authors = []string{authorEmail}
 	}
 
-	afterStart, afterEnd, err := parsePeriod(afterPeriod)
+	comparison, details, err := compareAuthors(paths, authors, len(team) > 0, periods)
 	if err != nil {
-		return fmt.Errorf("invalid --after: %w", err)
+		return err
 	}
 
-	authorEmail := author
-	if authorEmail == "" {
-		authorEmail, _ = git.GetDefaultAuthor(paths[0])
+	switch output {
+	case "json":
+		return report.CompareJSON(comparison, file, details)
+	case "html":
+		return report.CompareHTML(comparison, file, htmlStyle(), details)
+	case "csv":
+		return report.CompareCSV(comparison, file, details)
+	case "markdown":
+		return report.CompareMarkdown(comparison, file, details)
+	default:
+		return report.CompareTerminal(comparison, details)
 	}
+}
 
-	// Analyze both periods
-	var beforeStats, afterStats []git.RepoStats
+// compareAuthors analyzes the authors over paths in every period, as a team
+// (with a per-member breakdown) when isTeam. Authors with a period no
+// repository could be analyzed for are left out; it fails when none is
+// left.
+func compareAuthors(paths, authors []string, isTeam bool, periods []comparePeriod) (git.CompareStats, report.CompareDetails, error) {
+	selection, err := parseSelection()
+	if err != nil {
+		return git.CompareStats{}, report.CompareDetails{}, err
+	}
+	cWindow, err := parseWindow(churnWindow)
+	if err != nil {
+		return git.CompareStats{}, report.CompareDetails{}, fmt.Errorf("invalid --churn-window: %w", err)
+	}
 
-	for _, path := range paths {
-		bStats, err := git.Analyze(path, authorEmail, beforeStart, beforeEnd, exclude)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to analyze %s: %v\n", path, err)
+	// Analyze every period for every author; all[i] collects period i's
+	// per-repo stats across authors.
+	all := make([][]git.RepoStats, len(periods))
+	var members []report.MemberComparison
+	var diags []metrics.Diagnostic
+	for _, a := range authors {
+		perPeriod := make([][]git.RepoStats, len(periods))
+		complete := true
+		for i, p := range periods {
+			var failed []metrics.Diagnostic
+			perPeriod[i], failed = analyzePeriod(paths, a, p.start, p.end)
+			diags = append(diags, scoped(failed, p.label)...)
+			if len(perPeriod[i]) == 0 {
+				complete = false
+				break
+			}
+		}
+		if !complete {
+			if isTeam {
+				diags = append(diags, metrics.Diagnostic{
+					Metric: metrics.MetricAnalyze,
+					Author: a,
+					Status: metrics.StatusSkipped,
+					Reason: "not every period could be analyzed",
+				})
+			}
 			continue
 		}
-		beforeStats = append(beforeStats, bStats)
 
-		aStats, err := git.Analyze(path, authorEmail, afterStart, afterEnd, exclude)
-		if err != nil {
-			continue
+		m := report.MemberComparison{Email: a}
+		for i, p := range periods {
+			all[i] = append(all[i], perPeriod[i]...)
+			m.Periods = append(m.Periods, git.CombineStats(perPeriod[i]))
+			if selection.Any() {
+				b := computeOptInMetrics(repoPaths(perPeriod[i]), a, p.start, p.end, selection, cWindow, exclude)
+				for j := range b.Diagnostics {
+					b.Diagnostics[j].Author = a
+				}
+				diags = append(diags, scoped(b.Diagnostics, p.label)...)
+				m.Metrics = append(m.Metrics, b)
+			}
 		}
-		afterStats = append(afterStats, aStats)
+		members = append(members, m)
 	}
 
-	if len(beforeStats) == 0 || len(afterStats) == 0 {
-		return fmt.Errorf("could not analyze repositories for both periods")
+	if len(members) == 0 {
+		return git.CompareStats{}, report.CompareDetails{}, noneAnalyzed("could not analyze repositories for all periods", diags)
+	}
+	if strict {
+		if err := metrics.StrictError(diags); err != nil {
+			return git.CompareStats{}, report.CompareDetails{}, err
+		}
 	}
 
-	beforeCombined := git.CombineStats(beforeStats)
-	afterCombined := git.CombineStats(afterStats)
+	comparison := git.CompareStats{}
+	var samples [][]float64
+	for i, p := range periods {
+		combined := git.CombineStats(all[i])
+		if isTeam {
+			combined.Author = "team"
+		}
+		comparison.Periods = append(comparison.Periods, git.LabeledStats{Label: p.label, Stats: combined})
+		samples = append(samples, metrics.WeeklySamples(combined))
+	}
 
-	comparison := git.CompareStats{
-		Before:      beforeCombined,
-		After:       afterCombined,
-		BeforeLabel: beforePeriod,
-		AfterLabel:  afterPeriod,
+	// Weekly per-day samples let us report uncertainty around each multiplier.
+	details := report.CompareDetails{Significance: make([]metrics.Significance, len(periods))}
+	for i := 1; i < len(periods); i++ {
+		details.Significance[i] = metrics.CompareSamples(samples[0], samples[i])
 	}
+	if isTeam {
+		details.Members = members
+	} else {
+		details.Metrics = members[0].Metrics
+	}
+	if perRepo && len(paths) > 1 {
+		details.Repos = compareByRepo(paths, all)
+	}
+	details.Diagnostics = diags
+	return comparison, details, nil
+}
 
-	switch output {
-	case "json":
-		return report.CompareJSON(comparison, file)
-	case "html":
-		return report.CompareHTML(comparison, file, theme)
-	default:
-		return report.CompareTerminal(comparison)
+// analyzePeriod runs git.Analyze for one author and period on every path,
+// skipping repositories that fail and returning a diagnostic for each.
+func analyzePeriod(paths []string, author string, start, end time.Time) ([]git.RepoStats, []metrics.Diagnostic) {
+	var stats []git.RepoStats
+	var failed []metrics.Diagnostic
+	for _, path := range paths {
+		s, err := git.Analyze(path, author, start, end, exclude, excludeCommits)
+		if err != nil {
+			failed = append(failed, analyzeFailure(path, author, err))
+			continue
+		}
+		stats = append(stats, s)
+	}
+	return stats, failed
+}
+
+// scoped tags diagnostics with the compare period they came from.
+func scoped(diags []metrics.Diagnostic, label string) []metrics.Diagnostic {
+	for i := range diags {
+		diags[i].Scope = label
+	}
+	return diags
+}
+
+// compareByRepo groups each period's stats (possibly from several authors)
+// by repository path, preserving the order of paths. Repositories missing
+// from any period are left out.
+func compareByRepo(paths []string, all [][]git.RepoStats) []report.RepoComparison {
+	var repos []report.RepoComparison
+	for _, path := range paths {
+		rc := report.RepoComparison{Path: path}
+		for _, period := range all {
+			var matched []git.RepoStats
+			for _, s := range period {
+				if s.Path == path {
+					matched = append(matched, s)
+				}
+			}
+			if len(matched) == 0 {
+				break
+			}
+			rc.Periods = append(rc.Periods, git.CombineStats(matched))
+		}
+		if len(rc.Periods) == len(all) {
+			repos = append(repos, rc)
+		}
 	}
+	return repos
 }
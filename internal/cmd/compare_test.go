@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+	"github.com/juangracia/gitrespect/internal/report"
+)
+
+func lines(n int) string { return strings.Repeat("line\n", n) }
+
+// monthPeriod is the whole of month 2026-m.
+func monthPeriod(label string, m time.Month) comparePeriod {
+	start := time.Date(2026, m, 1, 0, 0, 0, 0, time.UTC)
+	return comparePeriod{label: label, start: start, end: start.AddDate(0, 1, 0).Add(-time.Second)}
+}
+
+// unreadableCommit commits work by email at ts whose diff can't be read:
+// the blob it replaced is gone.
+func unreadableCommit(t *testing.T, r *testRepo, email string, ts time.Time) {
+	t.Helper()
+	r.writeFile("lost.txt", lines(1))
+	r.commit("start", email, ts)
+	lost := r.revParse("HEAD:lost.txt")
+	r.writeFile("lost.txt", lines(2))
+	r.commit("edit", email, ts.Add(time.Hour))
+	if err := os.Remove(filepath.Join(r.path, ".git", "objects", lost[:2], lost[2:])); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// compareRepos returns two repositories where a@x.com and b@x.com work in
+// January and March, but b's January history can't be read.
+func compareRepos(t *testing.T) (api, web *testRepo) {
+	t.Helper()
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 12, 0, 0, 0, time.UTC) }
+
+	api = newTestRepo(t)
+	unreadableCommit(t, api, "b@x.com", day(1, 5))
+	api.writeFile("a.txt", lines(10))
+	api.commit("a in january", "a@x.com", day(1, 7))
+	api.writeFile("a.txt", lines(40))
+	api.commit("a in march", "a@x.com", day(3, 3))
+	api.writeFile("b.txt", lines(20))
+	api.commit("b in march", "b@x.com", day(3, 4))
+
+	web = newTestRepo(t)
+	unreadableCommit(t, web, "b@x.com", day(1, 6))
+	web.writeFile("a.txt", lines(5))
+	web.commit("a in january", "a@x.com", day(1, 8))
+	web.writeFile("a.txt", lines(20))
+	web.commit("a in march", "a@x.com", day(3, 5))
+	return api, web
+}
+
+func TestCompareTeam(t *testing.T) {
+	restoreFlags(t, &perRepo, &strict)
+	restoreFlags(t, &metricsFlag)
+	api, web := compareRepos(t)
+	paths := []string{api.path, web.path}
+	periods := []comparePeriod{monthPeriod("jan", time.January), monthPeriod("mar", time.March)}
+	perRepo, strict, metricsFlag = true, false, ""
+
+	comparison, details, err := compareAuthors(paths, []string{"a@x.com", "b@x.com"}, true, periods)
+	if err != nil {
+		t.Fatalf("compareAuthors: %v", err)
+	}
+
+	// b is missing from January, so the team is a alone.
+	if len(details.Members) != 1 || details.Members[0].Email != "a@x.com" {
+		t.Fatalf("members = %+v, want a@x.com only", details.Members)
+	}
+	if m := details.Members[0]; m.Periods[0].Net != 15 || m.Periods[1].Net != 45 {
+		t.Errorf("a's net = %d and %d, want 15 and 45", m.Periods[0].Net, m.Periods[1].Net)
+	}
+	for i, want := range []struct {
+		label string
+		net   int
+	}{{"jan", 15}, {"mar", 45}} {
+		p := comparison.Periods[i]
+		if p.Label != want.label || p.Stats.Author != "team" || p.Stats.Net != want.net {
+			t.Errorf("period %d = %s by %s with net %d, want %s by team with %d", i, p.Label, p.Stats.Author, p.Stats.Net, want.label, want.net)
+		}
+	}
+	if len(details.Significance) != 2 || details.Significance[1].AfterWeeks == 0 {
+		t.Errorf("significance = %+v, want march tested against january", details.Significance)
+	}
+
+	var skipped, failed bool
+	for _, d := range details.Diagnostics {
+		switch {
+		case d.Author == "b@x.com" && d.Metric == metrics.MetricAnalyze && d.Status == metrics.StatusSkipped:
+			skipped = true
+		case d.Author == "b@x.com" && d.Status == metrics.StatusError && d.Scope == "jan" && d.Repo == api.path:
+			failed = true
+		}
+	}
+	if !skipped || !failed {
+		t.Errorf("diagnostics = %+v, want b's january failure and b skipped", details.Diagnostics)
+	}
+
+	// Each repository's periods, in the order of paths.
+	if len(details.Repos) != 2 || details.Repos[0].Path != api.path || details.Repos[1].Path != web.path {
+		t.Fatalf("repos = %+v, want api then web", details.Repos)
+	}
+	for i, want := range [][2]int{{10, 30}, {5, 15}} {
+		r := details.Repos[i]
+		if r.Periods[0].Net != want[0] || r.Periods[1].Net != want[1] {
+			t.Errorf("%s: net %d and %d, want %d and %d", filepath.Base(r.Path), r.Periods[0].Net, r.Periods[1].Net, want[0], want[1])
+		}
+	}
+
+	// The report lists the member and both repositories, period by period.
+	out := filepath.Join(t.TempDir(), "compare.json")
+	if err := report.CompareJSON(comparison, out, details); err != nil {
+		t.Fatal(err)
+	}
+	var saved report.CompareJSONReport
+	if data, err := os.ReadFile(out); err != nil {
+		t.Fatal(err)
+	} else if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Members) != 1 || saved.Members[0].Name != "a@x.com" || len(saved.Members[0].Periods) != 2 {
+		t.Errorf("JSON members = %+v, want a@x.com over both periods", saved.Members)
+	}
+	if len(saved.Repos) != 2 || saved.Repos[1].Name != web.path || saved.Repos[1].Periods[1].Net != 15 {
+		t.Errorf("JSON repos = %+v, want api and web", saved.Repos)
+	}
+
+	strict = true
+	if _, _, err := compareAuthors(paths, []string{"a@x.com", "b@x.com"}, true, periods); err == nil {
+		t.Error("--strict compared a team with an unreadable period")
+	}
+	if _, _, err := compareAuthors(paths, []string{"b@x.com"}, true, periods); err == nil {
+		t.Error("compared a team nobody in which has every period")
+	}
+}
+
+func TestCompareAuthorMetrics(t *testing.T) {
+	restoreFlags(t, &perRepo, &strict)
+	restoreFlags(t, &metricsFlag)
+	_, web := compareRepos(t)
+	periods := []comparePeriod{monthPeriod("jan", time.January), monthPeriod("mar", time.March)}
+	perRepo, strict, metricsFlag = true, false, "commit-size"
+
+	comparison, details, err := compareAuthors([]string{web.path}, []string{"a@x.com"}, false, periods)
+	if err != nil {
+		t.Fatalf("compareAuthors: %v", err)
+	}
+	if comparison.Periods[1].Stats.Author != "a@x.com" || details.Members != nil {
+		t.Errorf("author %q with members %+v, want a@x.com alone", comparison.Periods[1].Stats.Author, details.Members)
+	}
+	// One repository has no per-repo breakdown.
+	if details.Repos != nil {
+		t.Errorf("repos = %+v, want none for one repository", details.Repos)
+	}
+	if len(details.Metrics) != 2 {
+		t.Fatalf("%d metric bundles, want one per period", len(details.Metrics))
+	}
+	for i, b := range details.Metrics {
+		if b.CommitSize == nil || b.CommitSize.Total != 1 {
+			t.Errorf("period %d commit size = %+v, want one commit", i, b.CommitSize)
+		}
+	}
+}
+
+func TestCompareByRepo(t *testing.T) {
+	all := [][]git.RepoStats{
+		{{Path: "/api", Added: 1, Net: 1}, {Path: "/web", Added: 2, Net: 2}, {Path: "/api", Added: 3, Net: 3}},
+		{{Path: "/api", Added: 4, Net: 4}, {Path: "/docs", Added: 5, Net: 5}},
+	}
+	repos := compareByRepo([]string{"/web", "/api", "/docs"}, all)
+	if len(repos) != 1 || repos[0].Path != "/api" {
+		t.Fatalf("repos = %+v, want /api only: /web and /docs each miss a period", repos)
+	}
+	if repos[0].Periods[0].Net != 4 || repos[0].Periods[1].Net != 4 {
+		t.Errorf("/api net = %d and %d, want both authors' 4 and 4", repos[0].Periods[0].Net, repos[0].Periods[1].Net)
+	}
+}
+
+func TestParseComparePeriods(t *testing.T) {
+	restoreFlags(t, &beforePeriod, &afterPeriod)
+	restoreFlags(t, &periodFlags)
+
+	for _, tc := range []struct {
+		name          string
+		before, after string
+		periods       []string
+		want          []string // label since..until
+		err           string
+	}{
+		{
+			name: "before and after", before: "2025-01:2025-03", after: "2025-Q3",
+			want: []string{"2025-01:2025-03 2025-01-01..2025-03-31", "2025-Q3 2025-07-01..2025-09-30"},
+		},
+		{
+			name:    "labeled",
+			periods: []string{"baseline=2025-01:2025-03", " pilot = 2025-04 ", "copilot=2025-07-01+2w"},
+			want:    []string{"baseline 2025-01-01..2025-03-31", "pilot 2025-04-01..2025-04-30", "copilot 2025-07-01..2025-07-14"},
+		},
+		{
+			name:    "unlabeled ranges name themselves",
+			periods: []string{"2025-W07", "2025-03-12"},
+			want:    []string{"2025-W07 2025-02-10..2025-02-16", "2025-03-12 2025-03-12..2025-03-12"},
+		},
+		{
+			name: "before and after come first", before: "2025-01", after: "2025-02", periods: []string{"later=2025-03"},
+			want: []string{"2025-01 2025-01-01..2025-01-31", "2025-02 2025-02-01..2025-02-28", "later 2025-03-01..2025-03-31"},
+		},
+		{name: "before without after", before: "2025-01", err: "--before and --after must be used together"},
+		{name: "one period", periods: []string{"only=2025-01"}, err: "need at least two periods"},
+		{name: "malformed range", periods: []string{"a=2025-01", "b=2025-13"}, err: `invalid --period "b=2025-13"`},
+		{name: "empty label", periods: []string{"a=2025-01", " =2025-02"}, err: `invalid --period " =2025-02"`},
+		{name: "duplicate label", periods: []string{"pilot=2025-01", "pilot=2025-02"}, err: `period "pilot" given twice`},
+		{name: "duplicate range", periods: []string{"2025-01", "2025-01"}, err: `period "2025-01" given twice`},
+		{name: "label reused by --before", before: "2025-01", after: "2025-02", periods: []string{"2025-01=2025-03"}, err: `period "2025-01" given twice`},
+	} {
+		beforePeriod, afterPeriod, periodFlags = tc.before, tc.after, tc.periods
+		periods, err := parseComparePeriods()
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: err = %v, want %q", tc.name, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		var got []string
+		for _, p := range periods {
+			got = append(got, p.label+" "+p.start.Format("2006-01-02")+".."+p.end.Format("2006-01-02"))
+		}
+		if strings.Join(got, ", ") != strings.Join(tc.want, ", ") {
+			t.Errorf("%s: periods = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCompareJSONPeriodOrder(t *testing.T) {
+	restoreFlags(t, &beforePeriod, &afterPeriod)
+	restoreFlags(t, &periodFlags)
+	restoreFlags(t, &perRepo, &strict)
+	restoreFlags(t, &metricsFlag)
+	_, web := compareRepos(t)
+	perRepo, strict, metricsFlag = false, false, ""
+
+	// Out of date order: March is the reference because it comes first.
+	beforePeriod, afterPeriod = "", ""
+	periodFlags = []string{"mar=2026-03", "jan=2026-01", "feb=2026-02"}
+	periods, err := parseComparePeriods()
+	if err != nil {
+		t.Fatal(err)
+	}
+	comparison, details, err := compareAuthors([]string{web.path}, []string{"a@x.com"}, false, periods)
+	if err != nil {
+		t.Fatalf("compareAuthors: %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "compare.json")
+	if err := report.CompareJSON(comparison, out, details); err != nil {
+		t.Fatal(err)
+	}
+	var saved report.CompareJSONReport
+	if data, err := os.ReadFile(out); err != nil {
+		t.Fatal(err)
+	} else if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(saved.Periods) != 3 {
+		t.Fatalf("%d periods, want 3", len(saved.Periods))
+	}
+	for i, label := range []string{"mar", "jan", "feb"} {
+		if saved.Periods[i].Label != label {
+			t.Errorf("period %d is %q, want %q", i, saved.Periods[i].Label, label)
+		}
+	}
+	if first := saved.Periods[0]; first.Multiplier != nil || first.Significance != nil || first.Change != "" {
+		t.Errorf("reference period compares against itself: %+v", first)
+	}
+	mar, jan, feb := saved.Periods[0], saved.Periods[1], saved.Periods[2]
+	if jan.Multiplier == nil || *jan.Multiplier != jan.PerDay/mar.PerDay || jan.Significance == nil || jan.Change == "" {
+		t.Errorf("jan vs mar: multiplier %v, want %.3f with a significance test", jan.Multiplier, jan.PerDay/mar.PerDay)
+	}
+	if feb.Net != 0 || feb.Multiplier == nil || *feb.Multiplier != 0 {
+		t.Errorf("feb = %+v, want no work and a 0x multiplier", feb)
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/juangracia/gitrespect/internal/metrics"
+	"github.com/juangracia/gitrespect/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var incidentFile string
+
+var doraCmd = &cobra.Command{
+	Use:   "dora [paths...]",
+	Short: "Report the four DORA metrics from local deploy and incident data",
+	Long: `Report deployment frequency, lead time for changes, change failure rate
+and time to restore, computed offline from the repositories' history.
+
+Deploys come from tags (--deploy-tags 'v*') or a deploy log (--deploy-log),
+either a text file with one "<time> <commit>" line per deploy, a CSV file
+with time and commit columns, or a JSON array of {"time", "commit"} objects.
+A deploy log may cover several repositories; commits a repository doesn't
+have are ignored.
+
+A deploy failed when:
+  - a commit it shipped was later reverted ("This reverts commit ...");
+    the deploy shipping the revert restored it
+  - a hotfix branch (--hotfix-branches, default hotfix/*) was started while
+    it was live; the deploy shipping the hotfix restored it
+  - an incident in --incidents names it, or started while it was live; the
+    incident's end is the restore. The file is CSV (start, end, deploy and
+    description columns) or a JSON array of such objects. An incident
+    without a deploy counts once, in the repository that deployed last
+    before it started.
+
+Lead time counts every author's commits unless --author or --team is given.
+
+Example:
+  gitrespect dora --deploy-tags 'v*' --since 2026-01-01
+  gitrespect dora ./api ./web --deploy-log deploys.csv --incidents incidents.json -o html`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runDORA,
+}
+
+func init() {
+	doraCmd.Flags().StringVarP(&author, "author", "a", "", "Count only this author's commits for lead time (default: everyone)")
+	doraCmd.Flags().StringSliceVarP(&team, "team", "t", nil, "Count only these authors' commits for lead time (comma-separated emails)")
+	doraCmd.Flags().StringVarP(&since, "since", "s", "30 days ago", "Start date (YYYY-MM-DD, YYYY-Qn, 'last monday', 'start of month', '2 weeks 3 days ago', ...)")
+	doraCmd.Flags().StringVarP(&until, "until", "u", "", "End date (default: now)")
+	doraCmd.Flags().IntVar(&year, "year", 0, "Filter by year (e.g., --year=2025)")
+	doraCmd.Flags().StringVarP(&output, "output", "o", "terminal", "Output format: terminal, json, or html")
+	doraCmd.Flags().StringVarP(&file, "file", "f", "", "Output file path (for html/json)")
+	doraCmd.Flags().StringVar(&theme, "theme", "dark", "HTML theme: dark or light")
+	doraCmd.Flags().StringVar(&themeFile, "theme-file", "", "CSS file overriding the HTML theme's colors (CSS variables)")
+	doraCmd.Flags().StringVar(&templateDir, "template", "", "Directory of HTML templates replacing the built-in ones (see gitrespect templates)")
+	doraCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Scan subdirectories for git repositories")
+	doraCmd.Flags().StringVar(&deployTags, "deploy-tags", "", "Tags marking deploys (e.g. 'v*' or 'deploy-*') for lead time to deploy and deploy frequency")
+	doraCmd.Flags().StringVar(&deployLog, "deploy-log", "", "Deploy log file (text, .csv or .json), instead of --deploy-tags")
+	doraCmd.Flags().StringVar(&incidentFile, "incidents", "", "Incident file (.csv or .json) with start, end, deploy and description")
+	doraCmd.Flags().StringVar(&hotfixBranches, "hotfix-branches", "hotfix/*", "Branches whose work fixes production (glob; empty to ignore)")
+	doraCmd.Flags().StringSliceVar(&mainBranch, "main-branch", nil, "Integration branches hotfix branches fork from, e.g. main,'release/*' (default: per-repo gitrespect.mainBranch, else detected)")
+	doraCmd.Flags().BoolVar(&strict, "strict", false, "Fail when any metric errors instead of reporting it")
+
+	rootCmd.AddCommand(doraCmd)
+}
+
+func runDORA(cmd *cobra.Command, args []string) error {
+	if deployTags != "" && deployLog != "" {
+		return fmt.Errorf("use either --deploy-tags or --deploy-log, not both")
+	}
+	src := deploySource()
+	if src.IsZero() {
+		return fmt.Errorf("dora needs deploys: pass --deploy-tags or --deploy-log")
+	}
+
+	resolvedPaths, err := resolvePaths(args)
+	if err != nil {
+		return err
+	}
+	sinceTime, untilTime, err := parseDates()
+	if err != nil {
+		return err
+	}
+
+	authors := team
+	if author != "" {
+		authors = append([]string{author}, authors...)
+	}
+
+	details := report.DORADetails{
+		Authors: authors,
+		Since:   sinceTime,
+		Until:   untilTime,
+		Repo:    make(map[string]metrics.DORA),
+	}
+	failures := metrics.FailureSource{HotfixPattern: hotfixBranches, IncidentFile: incidentFile, MainBranches: mainBranch}
+	var all []metrics.DORA
+	for _, path := range resolvedPaths {
+		diag := metrics.Diagnostic{Metric: metrics.MetricDORA, Repo: path, Status: metrics.StatusOK}
+		d, err := metrics.ComputeDORA(path, authors, sinceTime, untilTime, src, failures)
+		switch {
+		case err != nil:
+			diag.Status, diag.Reason = metrics.StatusError, err.Error()
+		case d.Deploys == 0:
+			diag.Status, diag.Reason = metrics.StatusInsufficient, "no deploys in period"
+		}
+		details.Diagnostics = append(details.Diagnostics, diag)
+		if err != nil {
+			continue
+		}
+		details.Repos = append(details.Repos, path)
+		all = append(all, d)
+	}
+	if len(all) == 0 {
+		return noneAnalyzed("no repositories could be analyzed", details.Diagnostics)
+	}
+	metrics.AttributeIncidents(all)
+	for i, path := range details.Repos {
+		details.Repo[path] = all[i]
+	}
+	details.DORA = metrics.MergeDORA(all)
+	if strict {
+		if err := metrics.StrictError(details.Diagnostics); err != nil {
+			return err
+		}
+	}
+
+	switch output {
+	case "json":
+		return report.DORAJSON(details, file)
+	case "html":
+		return report.DORAHTML(details, file, htmlStyle())
+	default:
+		return report.DORATerminal(details)
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+	"github.com/juangracia/gitrespect/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat string
+
+var exportCmd = &cobra.Command{
+	Use:   "export [paths...]",
+	Short: "Export one record per commit as CSV, JSON Lines or Parquet",
+	Long: `Export the commits behind the numbers, one record per commit, for your own
+analysis in a spreadsheet, DuckDB, pandas or a warehouse.
+
+Each record has the repository, SHA, parents, author, author and committer
+dates, subject, lines added and deleted, and the files changed with their
+own counts. Commits left out by --exclude-commit or gitrespect.excludeCommit
+are kept with excluded=true; files matching --exclude are listed but not
+counted. ai_assisted is true when a trailer such as "Co-authored-by:" or
+"Assisted-by:" credits an AI assistant.
+
+The format comes from --format, else the file's extension (.csv, .jsonl,
+.parquet), else JSON Lines. CSV and Parquet hold the files as a JSON array
+column. Parquet needs --file.
+
+Example:
+  gitrespect export --since 2026-01-01 > commits.jsonl
+  gitrespect export ./api ./web --team alice@x.com,bob@x.com -f commits.parquet`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&author, "author", "a", "", "Filter by author email (default: git config user.email)")
+	exportCmd.Flags().StringSliceVarP(&team, "team", "t", nil, "Export these authors' commits (comma-separated emails)")
+	exportCmd.Flags().StringVarP(&since, "since", "s", "30 days ago", "Start date (YYYY-MM-DD, YYYY-Qn, 'last monday', 'start of month', '2 weeks 3 days ago', ...)")
+	exportCmd.Flags().StringVarP(&until, "until", "u", "", "End date (default: now)")
+	exportCmd.Flags().IntVar(&year, "year", 0, "Filter by year (e.g., --year=2025)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "Export format: csv, jsonl or parquet (default: from --file's extension, else jsonl)")
+	exportCmd.Flags().StringVarP(&file, "file", "f", "", "Output file path (default: stdout)")
+	exportCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Scan subdirectories for git repositories")
+	exportCmd.Flags().StringSliceVarP(&exclude, "exclude", "e", nil, "Don't count files matching glob patterns (e.g., -e 'vendor/*' -e '*.generated.go')")
+	exportCmd.Flags().StringSliceVar(&excludeCommits, "exclude-commit", nil, "Mark these commits (full or abbreviated SHAs) excluded, on top of per-repo gitrespect.excludeCommit")
+	exportCmd.Flags().BoolVar(&strict, "strict", false, "Fail when any repository can't be read instead of exporting the rest")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	format, err := report.ExportFormat(exportFormat, file)
+	if err != nil {
+		return err
+	}
+	for _, sha := range excludeCommits {
+		if len(sha) < 4 {
+			return fmt.Errorf("invalid --exclude-commit %q: use at least 4 hex digits", sha)
+		}
+	}
+
+	resolvedPaths, err := resolvePaths(args)
+	if err != nil {
+		return err
+	}
+	sinceTime, untilTime, err := parseDates()
+	if err != nil {
+		return err
+	}
+
+	authors := team
+	if author != "" {
+		authors = append([]string{author}, authors...)
+	}
+	if len(authors) == 0 {
+		email, _ := git.GetDefaultAuthor(resolvedPaths[0])
+		authors = []string{email}
+	}
+
+	var records []git.CommitRecord
+	var diags []metrics.Diagnostic
+	read := 0
+	for _, path := range resolvedPaths {
+		// Authors are matched as patterns, so two of them may share a commit.
+		seen := make(map[string]bool)
+		var repo []git.CommitRecord
+		var failed bool
+		for _, a := range authors {
+			recs, err := git.ListCommits(path, a, sinceTime, untilTime, exclude, excludeCommits)
+			if err != nil {
+				diags = append(diags, analyzeFailure(path, a, err))
+				failed = true
+				break
+			}
+			for _, r := range recs {
+				if !seen[r.Hash] {
+					seen[r.Hash] = true
+					repo = append(repo, r)
+				}
+			}
+		}
+		if failed {
+			continue
+		}
+		read++
+		sort.SliceStable(repo, func(i, j int) bool { return repo[i].CommitDate.After(repo[j].CommitDate) })
+		records = append(records, repo...)
+	}
+	if read == 0 {
+		return noneAnalyzed("no repositories could be read", diags)
+	}
+	if strict {
+		if err := metrics.StrictError(diags); err != nil {
+			return err
+		}
+	}
+	// The export may be going to stdout, so the repositories that could not
+	// be read are listed on stderr.
+	for _, d := range diags {
+		fmt.Fprintln(os.Stderr, d)
+	}
+
+	return report.Export(records, format, file)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/juangracia/gitrespect/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var renderInputs []string
+
+var renderCmd = &cobra.Command{
+	Use:   "render --input report.json [--input ...]",
+	Short: "Render a saved JSON report as terminal, HTML, CSV or Markdown",
+	Long: `Render a report saved with --output json again, in any output format,
+without access to the repositories. Works with every JSON report: gitrespect,
+--team, compare and dora.
+
+Several inputs are merged, e.g. monthly archives into a quarter: they must
+be reports of the same kind (and author) over periods that neither overlap
+nor share a day. Totals, monthly breakdowns, excluded commits, outliers and
+diagnostics add up; baselines, opt-in metrics and --explain cover a single
+period and are left out, which the merged report's diagnostics list.
+
+Only what the JSON recorded can be shown: weekly breakdowns and per-repo
+totals are not saved in JSON reports.
+
+Example:
+  gitrespect render --input 2026-09.json --output html -f 2026-09.html
+  gitrespect render -i 2026-07.json -i 2026-08.json -i 2026-09.json`,
+	Args: cobra.NoArgs,
+	RunE: runRender,
+}
+
+func init() {
+	renderCmd.Flags().StringSliceVarP(&renderInputs, "input", "i", nil, "JSON report to render (repeat or comma-separate to merge several)")
+	renderCmd.Flags().StringVarP(&output, "output", "o", "terminal", "Output format: terminal, json, html, csv, or markdown")
+	renderCmd.Flags().StringVarP(&file, "file", "f", "", "Output file path (for html/json/csv/markdown)")
+	renderCmd.Flags().StringVar(&theme, "theme", "dark", "HTML theme: dark or light")
+	renderCmd.Flags().StringVar(&themeFile, "theme-file", "", "CSS file overriding the HTML theme's colors (CSS variables)")
+	renderCmd.Flags().StringVar(&templateDir, "template", "", "Directory of HTML templates replacing the built-in ones (see gitrespect templates)")
+	renderCmd.MarkFlagRequired("input")
+
+	rootCmd.AddCommand(renderCmd)
+}
+
+func runRender(cmd *cobra.Command, args []string) error {
+	var saved []report.Saved
+	for _, in := range renderInputs {
+		s, err := report.LoadFile(in)
+		if err != nil {
+			return fmt.Errorf("failed to load report: %w", err)
+		}
+		saved = append(saved, s)
+	}
+	s, err := report.Merge(saved)
+	if err != nil {
+		return err
+	}
+
+	switch s.Kind {
+	case "team":
+		switch output {
+		case "json":
+			return report.TeamJSON(s.Team, file, s.Breakdown, s.Bundle, s.Members)
+		case "html":
+			return report.TeamHTML(s.Team, file, htmlStyle(), s.Breakdown, s.Bundle, s.Members)
+		case "csv":
+			return report.TeamCSV(s.Team, file, s.Breakdown, s.Bundle, s.Members)
+		case "markdown":
+			return report.TeamMarkdown(s.Team, file, s.Breakdown, s.Bundle, s.Members)
+		default:
+			return report.TeamTerminal(s.Team, s.Breakdown, s.Bundle, s.Members)
+		}
+	case "compare":
+		switch output {
+		case "json":
+			return report.CompareJSON(s.Compare, file, s.Details)
+		case "html":
+			return report.CompareHTML(s.Compare, file, htmlStyle(), s.Details)
+		case "csv":
+			return report.CompareCSV(s.Compare, file, s.Details)
+		case "markdown":
+			return report.CompareMarkdown(s.Compare, file, s.Details)
+		default:
+			return report.CompareTerminal(s.Compare, s.Details)
+		}
+	case "dora":
+		switch output {
+		case "json":
+			return report.DORAJSON(s.DORA, file)
+		case "html":
+			return report.DORAHTML(s.DORA, file, htmlStyle())
+		case "csv", "markdown":
+			return fmt.Errorf("dora reports render as terminal, json or html")
+		default:
+			return report.DORATerminal(s.DORA)
+		}
+	default:
+		switch output {
+		case "json":
+			return report.JSON(s.Stats, file, s.Breakdown, s.Bundle)
+		case "html":
+			return report.HTML(s.Stats, file, s.Breakdown, htmlStyle(), s.Bundle)
+		case "csv":
+			return report.CSV(s.Stats, nil, file, s.Breakdown, s.Bundle)
+		case "markdown":
+			return report.Markdown(s.Stats, nil, file, s.Breakdown, s.Bundle)
+		default:
+			return report.Terminal(s.Stats, s.Breakdown, s.Bundle)
+		}
+	}
+}
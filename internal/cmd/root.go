@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 	"github.com/juangracia/gitrespect/internal/git"
 	"github.com/juangracia/gitrespect/internal/metrics"
 	"github.com/juangracia/gitrespect/internal/report"
+	"github.com/juangracia/gitrespect/internal/snapshot"
 	"github.com/spf13/cobra"
 )
 
@@ -24,12 +26,23 @@ var (
 	file            string
 	year            int
 	theme           string
+	themeFile       string
+	templateDir     string
 	recursive       bool
 	perRepo         bool
 	exclude         []string
 	metricsFlag     string
 	baselineWindow  string
+	seasonal        bool
+	strict          bool
+	mainBranch      []string
+	deployTags      string
+	deployLog       string
 	churnWindow     string
+	hotfixBranches  string
+	sizeBuckets     []int
+	excludeCommits  []string
+	explain         string
 	legacyBenchmark bool
 )
 
@@ -47,22 +60,37 @@ lines added, deleted, net changes, and comparisons to industry benchmarks.`,
 func init() {
 	rootCmd.Flags().StringVarP(&author, "author", "a", "", "Filter by author email (default: git config user.email)")
 	rootCmd.Flags().StringSliceVarP(&team, "team", "t", nil, "Team mode: analyze multiple authors (comma-separated emails)")
-	rootCmd.Flags().StringVarP(&since, "since", "s", "30 days ago", "Start date (YYYY-MM-DD or relative like '30 days ago')")
+	rootCmd.Flags().StringVarP(&since, "since", "s", "30 days ago", "Start date (YYYY-MM-DD, YYYY-Qn, 'last monday', 'start of month', '2 weeks 3 days ago', ...)")
 	rootCmd.Flags().StringVarP(&until, "until", "u", "", "End date (default: now)")
 	rootCmd.Flags().StringVarP(&breakdown, "breakdown", "b", "", "Show breakdown: monthly, weekly, or daily")
-	rootCmd.Flags().StringVarP(&output, "output", "o", "terminal", "Output format: terminal, json, or html")
-	rootCmd.Flags().StringVarP(&file, "file", "f", "", "Output file path (for html/json)")
+	rootCmd.Flags().StringVarP(&output, "output", "o", "terminal", "Output format: terminal, json, html, csv, or markdown")
+	rootCmd.Flags().StringVarP(&file, "file", "f", "", "Output file path (for html/json/csv/markdown)")
 	rootCmd.Flags().IntVar(&year, "year", 0, "Filter by year (e.g., --year=2025)")
 	rootCmd.Flags().StringVar(&theme, "theme", "dark", "HTML theme: dark or light")
+	rootCmd.Flags().StringVar(&themeFile, "theme-file", "", "CSS file overriding the HTML theme's colors (CSS variables)")
+	rootCmd.Flags().StringVar(&templateDir, "template", "", "Directory of HTML templates replacing the built-in ones (see gitrespect templates)")
 	rootCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Scan subdirectories for git repositories")
 	rootCmd.Flags().BoolVar(&perRepo, "per-repo", false, "Show breakdown by repository when analyzing multiple repos")
 	rootCmd.Flags().StringSliceVarP(&exclude, "exclude", "e", nil, "Exclude files matching glob patterns (e.g., -e 'vendor/*' -e '*.generated.go')")
-	rootCmd.Flags().StringVar(&metricsFlag, "metrics", "", "Opt-in metrics: comma list of churn,lead-time,commit-size,cadence, or 'all'")
+	rootCmd.Flags().StringSliceVar(&excludeCommits, "exclude-commit", nil, "Leave these commits (full or abbreviated SHAs) out of every total, on top of per-repo gitrespect.excludeCommit")
+	rootCmd.Flags().StringVar(&explain, "explain", "", "List the top commits and files behind the total, a month (YYYY-MM) or a week (YYYY-Www)")
+	rootCmd.Flags().Lookup("explain").NoOptDefVal = git.ExplainTotal
+	rootCmd.Flags().StringVar(&metricsFlag, "metrics", "", "Opt-in metrics: comma list of churn,lead-time,commit-size,cadence,reverts, or 'all'")
 	rootCmd.Flags().StringVar(&baselineWindow, "baseline-window", "90d", "Personal baseline window (e.g. 30d, 90d, 6m, 1y)")
-	rootCmd.Flags().StringVar(&churnWindow, "churn-window", "30d", "Churn detection window")
+	rootCmd.Flags().BoolVar(&seasonal, "seasonal", false, "Also compare against the same period last year")
+	rootCmd.Flags().StringVar(&churnWindow, "churn-window", "30d", "Churn detection window (also how soon a fix counts against reverts)")
+	rootCmd.Flags().IntSliceVar(&sizeBuckets, "size-buckets", nil, "Commit size bucket edges in lines changed, e.g. 10,50,200,1000 (default: per-repo gitrespect.sizeBuckets, else 10,100,500)")
+	rootCmd.Flags().StringVar(&hotfixBranches, "hotfix-branches", "hotfix/*", "Branches whose work fixes production (glob; empty to ignore)")
+	rootCmd.Flags().StringVar(&deployTags, "deploy-tags", "", "Tags marking deploys (e.g. 'v*' or 'deploy-*') for lead time to deploy and deploy frequency")
+	rootCmd.Flags().StringVar(&deployLog, "deploy-log", "", "Deploy log file (text, .csv or .json), instead of --deploy-tags")
+	rootCmd.Flags().StringSliceVar(&mainBranch, "main-branch", nil, "Integration branches for cadence and lead time, e.g. main,'release/*' (default: per-repo gitrespect.mainBranch, else detected)")
+	rootCmd.Flags().BoolVar(&strict, "strict", false, "Fail when any metric errors instead of reporting it")
 	rootCmd.Flags().BoolVar(&legacyBenchmark, "legacy-benchmark", false, "Show deprecated Senior/Avg/Junior comparison instead of personal baseline")
 }
 
+// explainTop is how many commits and files --explain lists.
+const explainTop = 10
+
 func parseWindow(raw string) (time.Duration, error) {
 	raw = strings.TrimSpace(raw)
 	if len(raw) < 2 {
@@ -91,12 +119,20 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
-func runAnalyze(cmd *cobra.Command, args []string) error {
+// htmlStyle is how --output html looks, from --theme, --theme-file and
+// --template.
+func htmlStyle() report.HTMLStyle {
+	return report.HTMLStyle{Theme: theme, ThemeFile: themeFile, TemplateDir: templateDir}
+}
+
+// resolvePaths turns the command's arguments (default: the current
+// directory) into absolute repository paths, scanning them with --recursive.
+func resolvePaths(args []string) ([]string, error) {
 	paths := args
 	if len(paths) == 0 {
 		cwd, err := os.Getwd()
 		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
 		}
 		paths = []string{cwd}
 	}
@@ -106,7 +142,7 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	for _, p := range paths {
 		abs, err := filepath.Abs(p)
 		if err != nil {
-			return fmt.Errorf("invalid path %s: %w", p, err)
+			return nil, fmt.Errorf("invalid path %s: %w", p, err)
 		}
 
 		if recursive {
@@ -121,40 +157,35 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 			resolvedPaths = append(resolvedPaths, abs)
 		}
 	}
-	paths = resolvedPaths
 
-	if len(paths) == 0 {
-		return fmt.Errorf("no git repositories found")
+	if len(resolvedPaths) == 0 {
+		return nil, fmt.Errorf("no git repositories found")
 	}
+	return resolvedPaths, nil
+}
 
-	// Parse dates
-	var sinceTime, untilTime time.Time
-	var err error
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	paths, err := resolvePaths(args)
+	if err != nil {
+		return err
+	}
 
-	if year > 0 {
-		sinceTime = time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
-		untilTime = time.Date(year, 12, 31, 23, 59, 59, 0, time.Local)
-		if untilTime.After(time.Now()) {
-			untilTime = time.Now()
-		}
-	} else {
-		sinceTime, err = git.ParseDate(since)
-		if err != nil {
-			return fmt.Errorf("invalid --since date: %w", err)
-		}
+	sinceTime, untilTime, err := parseDates()
+	if err != nil {
+		return err
+	}
 
-		if until == "" {
-			untilTime = time.Now()
-		} else {
-			untilTime, err = git.ParseDate(until)
-			if err != nil {
-				return fmt.Errorf("invalid --until date: %w", err)
-			}
+	if explain != "" {
+		if err := git.ValidExplainBucket(explain); err != nil {
+			return fmt.Errorf("invalid --explain: %w", err)
 		}
 	}
 
 	// Check if team mode is enabled
 	if len(team) > 0 {
+		if explain != "" {
+			return fmt.Errorf("--explain is not supported with --team")
+		}
 		return runTeamAnalysis(paths, team, sinceTime, untilTime)
 	}
 
@@ -166,24 +197,25 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 
 	// Analyze repositories
 	var allStats []git.RepoStats
+	var diags []metrics.Diagnostic
 	for _, path := range paths {
-		stats, err := git.Analyze(path, authorEmail, sinceTime, untilTime, exclude)
+		stats, err := git.Analyze(path, authorEmail, sinceTime, untilTime, exclude, excludeCommits)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to analyze %s: %v\n", path, err)
+			diags = append(diags, analyzeFailure(path, "", err))
 			continue
 		}
 		allStats = append(allStats, stats)
 	}
 
 	if len(allStats) == 0 {
-		return fmt.Errorf("no repositories could be analyzed")
+		return noneAnalyzed("no repositories could be analyzed", diags)
 	}
 
 	// Aggregate stats
 	combined := git.CombineStats(allStats)
 
 	// Parse metric options
-	selection, err := metrics.ParseSelection(metricsFlag)
+	selection, err := parseSelection()
 	if err != nil {
 		return err
 	}
@@ -196,30 +228,50 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid --churn-window: %w", err)
 	}
 
-	// Pick the repo with the most author commits as the primary for opt-in metrics.
-	primaryPath := primaryRepo(allStats, paths[0])
-	bundle := computeOptInMetrics(primaryPath, authorEmail, sinceTime, untilTime, selection, cWindow, exclude)
+	// Opt-in metrics cover every analyzed repo.
+	analyzed := repoPaths(allStats)
+	bundle := computeOptInMetrics(analyzed, authorEmail, sinceTime, untilTime, selection, cWindow, exclude)
 	bundle.LegacyBenchmark = legacyBenchmark
+	bundle.Outliers = git.FindOutliers(combined)
+	if explain != "" {
+		ex, err := git.Explain(combined, explain, explainTop)
+		if err != nil {
+			return err
+		}
+		bundle.Explain = &ex
+	}
 
 	if !legacyBenchmark {
-		baseline, err := metrics.ComputeBaseline(primaryPath, authorEmail, sinceTime, bWindow, exclude)
-		if err == nil {
-			wd := git.WorkingDays(sinceTime, untilTime)
-			var locPerDay float64
-			if wd > 0 {
-				locPerDay = float64(combined.Net) / float64(wd)
-			}
-			baseline.SetPeriod(locPerDay)
-			bundle.Baseline = &baseline
+		bundle.Normal = computeNormal(analyzed, authorEmail, sinceTime, selection, bWindow, cWindow, exclude)
+		var baselineDiags []metrics.Diagnostic
+		bundle.Baseline, baselineDiags = computeBaseline(paths, []string{authorEmail}, sinceTime, untilTime, combined.Net, bWindow)
+		diags = append(diags, baselineDiags...)
+		diags = append(diags, normalErrors(bundle.Normal)...)
+	}
+	bundle.Diagnostics = append(diags, bundle.Diagnostics...)
+	if strict {
+		if err := metrics.StrictError(bundle.Diagnostics); err != nil {
+			return err
 		}
 	}
 
 	// Generate output
 	switch output {
+	case outputSnapshot:
+		return saveSnapshot(snapshot.Subject([]string{authorEmail}, false, paths), sinceTime, untilTime, report.NewJSONReport(combined, breakdown, bundle))
 	case "json":
 		return report.JSON(combined, file, breakdown, bundle)
 	case "html":
-		return report.HTML(combined, file, breakdown, theme, bundle)
+		return report.HTML(combined, file, breakdown, htmlStyle(), bundle)
+	case "csv", "markdown":
+		var repos []git.RepoStats
+		if perRepo {
+			repos = allStats
+		}
+		if output == "csv" {
+			return report.CSV(combined, repos, file, breakdown, bundle)
+		}
+		return report.Markdown(combined, repos, file, breakdown, bundle)
 	default:
 		if perRepo && len(allStats) > 1 {
 			return report.TerminalWithRepos(combined, allStats, breakdown, bundle)
@@ -228,14 +280,78 @@ func runAnalyze(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// parseDates returns the period of --year, else --since and --until.
+func parseDates() (time.Time, time.Time, error) {
+	if year > 0 {
+		sinceTime := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+		untilTime := time.Date(year, 12, 31, 23, 59, 59, 0, time.Local)
+		if untilTime.After(time.Now()) {
+			untilTime = time.Now()
+		}
+		if !untilTime.After(sinceTime) {
+			return time.Time{}, time.Time{}, fmt.Errorf("--year %d is in the future", year)
+		}
+		return sinceTime, untilTime, nil
+	}
+
+	sinceTime, err := git.ParseDate(since)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --since date: %w", err)
+	}
+	if until == "" {
+		if !time.Now().After(sinceTime) {
+			return time.Time{}, time.Time{}, fmt.Errorf("--since %s is in the future", since)
+		}
+		return sinceTime, time.Now(), nil
+	}
+	untilTime, err := git.ParseUntil(until)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --until date: %w", err)
+	}
+	if !untilTime.After(sinceTime) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--until %s must be after --since %s", until, since)
+	}
+	return sinceTime, untilTime, nil
+}
+
 func runTeamAnalysis(paths []string, members []string, sinceTime, untilTime time.Time) error {
-	selection, err := metrics.ParseSelection(metricsFlag)
+	teamStats, teamBundle, bundles, err := analyzeTeam(paths, members, sinceTime, untilTime)
 	if err != nil {
 		return err
 	}
+
+	// Generate output
+	switch output {
+	case outputSnapshot:
+		return saveSnapshot(snapshot.Subject(members, true, paths), sinceTime, untilTime, report.NewTeamJSONReport(teamStats, breakdown, teamBundle, bundles))
+	case "json":
+		return report.TeamJSON(teamStats, file, breakdown, teamBundle, bundles)
+	case "html":
+		return report.TeamHTML(teamStats, file, htmlStyle(), breakdown, teamBundle, bundles)
+	case "csv":
+		return report.TeamCSV(teamStats, file, breakdown, teamBundle, bundles)
+	case "markdown":
+		return report.TeamMarkdown(teamStats, file, breakdown, teamBundle, bundles)
+	default:
+		return report.TeamTerminal(teamStats, breakdown, teamBundle, bundles)
+	}
+}
+
+// analyzeTeam analyzes each member over paths: their stats and metrics, and
+// the team's totals, baseline and deploys. Members without commits are left
+// out; it fails when none has any.
+func analyzeTeam(paths []string, members []string, sinceTime, untilTime time.Time) (git.TeamStats, metrics.Bundle, map[string]metrics.Bundle, error) {
+	selection, err := parseSelection()
+	if err != nil {
+		return git.TeamStats{}, metrics.Bundle{}, nil, err
+	}
+	bWindow, err := parseWindow(baselineWindow)
+	if err != nil {
+		return git.TeamStats{}, metrics.Bundle{}, nil, fmt.Errorf("invalid --baseline-window: %w", err)
+	}
 	cWindow, err := parseWindow(churnWindow)
 	if err != nil {
-		return fmt.Errorf("invalid --churn-window: %w", err)
+		return git.TeamStats{}, metrics.Bundle{}, nil, fmt.Errorf("invalid --churn-window: %w", err)
 	}
 
 	teamStats := git.TeamStats{
@@ -245,13 +361,16 @@ func runTeamAnalysis(paths []string, members []string, sinceTime, untilTime time
 	}
 	bundles := make(map[string]metrics.Bundle)
 	var memberCombined []git.RepoStats
+	var analyzed []string
+	var diags []metrics.Diagnostic
 
 	// Analyze each team member
 	for _, member := range members {
 		var memberStats []git.RepoStats
 		for _, path := range paths {
-			stats, err := git.Analyze(path, member, sinceTime, untilTime, exclude)
+			stats, err := git.Analyze(path, member, sinceTime, untilTime, exclude, excludeCommits)
 			if err != nil {
+				diags = append(diags, analyzeFailure(path, member, err))
 				continue
 			}
 			memberStats = append(memberStats, stats)
@@ -269,69 +388,256 @@ func runTeamAnalysis(paths []string, members []string, sinceTime, untilTime time
 		teamStats.TotalCommits += combined.Commits
 		memberCombined = append(memberCombined, combined)
 
-		// Per-member opt-in metrics, computed on the member's primary repo.
-		if selection.Any() {
-			primary := primaryRepo(memberStats, paths[0])
-			bundles[member] = computeOptInMetrics(primary, member, sinceTime, untilTime, selection, cWindow, exclude)
+		analyzed = append(analyzed, member)
+
+		// Per-member opt-in metrics and personal baseline, across all repos.
+		memberPaths := repoPaths(memberStats)
+		bundle := computeOptInMetrics(memberPaths, member, sinceTime, untilTime, selection, cWindow, exclude)
+		bundle.LegacyBenchmark = legacyBenchmark
+		var memberDiags []metrics.Diagnostic
+		if !legacyBenchmark {
+			bundle.Normal = computeNormal(memberPaths, member, sinceTime, selection, bWindow, cWindow, exclude)
+			var baselineDiags []metrics.Diagnostic
+			bundle.Baseline, baselineDiags = computeBaseline(paths, []string{member}, sinceTime, untilTime, combined.Net, bWindow)
+			memberDiags = append(memberDiags, baselineDiags...)
+			memberDiags = append(memberDiags, normalErrors(bundle.Normal)...)
 		}
+		bundle.Diagnostics = append(memberDiags, bundle.Diagnostics...)
+		for i := range bundle.Diagnostics {
+			bundle.Diagnostics[i].Author = member
+		}
+		bundles[member] = bundle
+		diags = append(diags, bundle.Diagnostics...)
 	}
 
 	if len(teamStats.Members) == 0 {
-		return fmt.Errorf("no team members could be analyzed")
+		return teamStats, metrics.Bundle{}, nil, noneAnalyzed("no team members could be analyzed", diags)
 	}
 
 	// Team-wide monthly breakdown aggregated across all members.
 	teamStats.Monthly = git.CombineStats(memberCombined).Monthly
 
-	// Generate output
-	switch output {
-	case "json":
-		return report.TeamJSON(teamStats, file, breakdown, bundles)
-	case "html":
-		return report.TeamHTML(teamStats, file, theme, breakdown, bundles)
-	default:
-		return report.TeamTerminal(teamStats, breakdown, bundles)
+	// Team-level aggregate: the team's combined output against its own history.
+	teamBundle := metrics.Bundle{LegacyBenchmark: legacyBenchmark}
+	if selection.Deploy {
+		var deployDiags []metrics.Diagnostic
+		teamBundle.Deploy, deployDiags = computeTeamDeploy(paths, analyzed, sinceTime, untilTime)
+		diags = append(diags, deployDiags...)
+	}
+	if !legacyBenchmark {
+		baseline, baselineDiags := computeBaseline(paths, analyzed, sinceTime, untilTime, teamStats.TotalNet, bWindow)
+		teamBundle.Baseline = baseline
+		for i := range baselineDiags {
+			baselineDiags[i].Author = "team"
+		}
+		diags = append(diags, baselineDiags...)
+	}
+	// Member diagnostics are reported once, at team level.
+	teamBundle.Diagnostics = diags
+	if strict {
+		if err := metrics.StrictError(diags); err != nil {
+			return teamStats, teamBundle, bundles, err
+		}
+	}
+	return teamStats, teamBundle, bundles, nil
+}
+
+// repoPaths returns the paths of the analyzed repos.
+func repoPaths(stats []git.RepoStats) []string {
+	paths := make([]string, len(stats))
+	for i, s := range stats {
+		paths[i] = s.Path
+	}
+	return paths
+}
+
+// noneAnalyzed is the error returned when every repository failed, listing
+// why each one did.
+func noneAnalyzed(msg string, diags []metrics.Diagnostic) error {
+	if err := metrics.StrictError(diags); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return errors.New(msg)
+}
+
+// computeTeamDeploy measures lead time to deploy over the commits of the whole
+// team, so a deploy shipping several members' work counts once. Nil when no
+// repo could be read; the diagnostics say why each failed.
+func computeTeamDeploy(paths, authors []string, since, until time.Time) (*metrics.DeployLeadTime, []metrics.Diagnostic) {
+	var all []metrics.DeployLeadTime
+	var diags []metrics.Diagnostic
+	for _, path := range paths {
+		d, err := metrics.ComputeDeployLeadTime(path, authors, since, until, deploySource())
+		if err != nil {
+			diags = append(diags, metrics.Diagnostic{Metric: metrics.MetricDeploy, Repo: path, Status: metrics.StatusError, Reason: err.Error()})
+			continue
+		}
+		all = append(all, d)
+	}
+	if len(all) == 0 {
+		return nil, diags
+	}
+	d := metrics.MergeDeployLeadTime(all)
+	return &d, diags
+}
+
+// parseSelection parses --metrics and turns on deploy lead time when a
+// deploy source is given.
+func parseSelection() (metrics.Selection, error) {
+	selection, err := metrics.ParseSelection(metricsFlag)
+	if err != nil {
+		return selection, err
+	}
+	if deployTags != "" && deployLog != "" {
+		return selection, fmt.Errorf("use either --deploy-tags or --deploy-log, not both")
+	}
+	for _, sha := range excludeCommits {
+		if len(sha) < 4 {
+			return selection, fmt.Errorf("invalid --exclude-commit %q: use at least 4 hex digits", sha)
+		}
+	}
+	if len(sizeBuckets) > 0 {
+		if err := metrics.CheckSizeEdges(sizeBuckets); err != nil {
+			return selection, fmt.Errorf("invalid --size-buckets: %w", err)
+		}
 	}
+	selection.Deploy = !deploySource().IsZero()
+	return selection, nil
 }
 
-// primaryRepo returns the path of the repo with the most commits in stats,
-// falling back to the given path when stats is empty.
-func primaryRepo(stats []git.RepoStats, fallback string) string {
-	primary := fallback
-	maxCommits := -1
-	for _, s := range stats {
-		if s.Commits > maxCommits {
-			maxCommits = s.Commits
-			primary = s.Path
+func deploySource() metrics.DeploySource {
+	return metrics.DeploySource{TagPattern: deployTags, LogFile: deployLog}
+}
+
+// analyzeFailure records a repository that git.Analyze could not read.
+func analyzeFailure(path, author string, err error) metrics.Diagnostic {
+	return metrics.Diagnostic{Metric: metrics.MetricAnalyze, Repo: path, Author: author, Status: metrics.StatusError, Reason: err.Error()}
+}
+
+// computeBaseline builds the baseline of the authors' combined history across
+// all repos and places the period's net output in it. The baseline is nil
+// when the history cannot be analyzed; the diagnostics say why, and whether
+// --seasonal's band could be computed.
+func computeBaseline(paths, authors []string, since, until time.Time, net int, bWindow time.Duration) (*metrics.Baseline, []metrics.Diagnostic) {
+	diag := metrics.Diagnostic{Metric: metrics.MetricBaseline, Status: metrics.StatusOK}
+	baseline, err := metrics.ComputeGroupBaseline(paths, authors, since, bWindow, exclude, excludeCommits)
+	if err != nil {
+		diag.Status, diag.Reason = metrics.StatusError, err.Error()
+		return nil, []metrics.Diagnostic{diag}
+	}
+	if baseline.InsufficientHistory {
+		diag.Status, diag.Reason = metrics.StatusInsufficient, "not enough prior history in the baseline window"
+	}
+	diags := []metrics.Diagnostic{diag}
+	if seasonal {
+		band, err := metrics.ComputeSeasonal(paths, authors, since, until, exclude, excludeCommits)
+		if err != nil {
+			diags = append(diags, metrics.Diagnostic{Metric: metrics.MetricBaseline, Scope: "same period last year", Status: metrics.StatusError, Reason: err.Error()})
+		} else {
+			baseline.Seasonal = &band
 		}
 	}
-	return primary
+	var locPerDay float64
+	if wd := git.WorkingDays(since, until); wd > 0 {
+		locPerDay = float64(net) / float64(wd)
+	}
+	baseline.SetPeriod(locPerDay)
+	return &baseline, diags
+}
+
+// computeNormal computes the selected opt-in metrics over the baseline window
+// [since - window, since), so each one can be shown as "your normal vs this
+// period". Returns nil when no metric is selected.
+func computeNormal(paths []string, author string, since time.Time, sel metrics.Selection, bWindow, cWindow time.Duration, exclude []string) *metrics.Bundle {
+	if !sel.Any() {
+		return nil
+	}
+	normal := computeOptInMetrics(paths, author, since.Add(-bWindow), since, sel, cWindow, exclude)
+	normal.Repos = nil
+	return &normal
+}
+
+// normalErrors returns the failures of a baseline-window bundle, scoped so
+// they read apart from the period's own diagnostics. A thin baseline window
+// already shows as "your normal: no data", so only errors are surfaced.
+func normalErrors(normal *metrics.Bundle) []metrics.Diagnostic {
+	if normal == nil {
+		return nil
+	}
+	errs := metrics.Errors(normal.Diagnostics)
+	for i := range errs {
+		errs[i].Scope = "baseline window"
+	}
+	return errs
 }
 
-// computeOptInMetrics computes the opt-in metrics selected on the given repo for
-// one author. Each metric is best-effort: a failure leaves that field nil rather
-// than aborting the whole report.
-func computeOptInMetrics(primaryPath, author string, since, until time.Time, sel metrics.Selection, cWindow time.Duration, exclude []string) metrics.Bundle {
-	bundle := metrics.Bundle{Selection: sel}
+// computeOptInMetrics computes the selected opt-in metrics for one author in
+// every repo and merges them (see metrics.MergeBundles). Each metric is
+// best-effort: a failure leaves that repo's field nil rather than aborting
+// the whole report. With --per-repo the per-repo values are kept as well.
+func computeOptInMetrics(paths []string, author string, since, until time.Time, sel metrics.Selection, cWindow time.Duration, exclude []string) metrics.Bundle {
+	var repos []metrics.RepoBundle
+	var bundles []metrics.Bundle
+	for _, path := range paths {
+		b := computeRepoMetrics(path, author, since, until, sel, cWindow, exclude)
+		repos = append(repos, metrics.RepoBundle{Path: path, Bundle: b})
+		bundles = append(bundles, b)
+	}
+	bundle := metrics.MergeBundles(bundles)
+	bundle.Selection, bundle.Since, bundle.Until = sel, since, until
+	if perRepo && len(paths) > 1 && sel.Any() {
+		bundle.Repos = repos
+	}
+	return bundle
+}
+
+// computeRepoMetrics computes the selected opt-in metrics on a single repo
+// and records how each one went in the bundle's diagnostics.
+func computeRepoMetrics(path, author string, since, until time.Time, sel metrics.Selection, cWindow time.Duration, exclude []string) metrics.Bundle {
+	bundle := metrics.Bundle{Selection: sel, Since: since, Until: until}
+	errs := make(map[string]error)
 	if sel.CommitSize {
-		if d, err := metrics.ComputeCommitSize(primaryPath, author, since, until, exclude); err == nil {
+		if d, err := metrics.ComputeCommitSize(path, author, since, until, exclude, excludeCommits, sizeBuckets); err == nil {
 			bundle.CommitSize = &d
+		} else {
+			errs[metrics.MetricCommitSize] = err
 		}
 	}
 	if sel.Cadence {
-		if c, err := metrics.ComputeCadence(primaryPath, author, since, until); err == nil {
+		if c, err := metrics.ComputeCadence(path, author, since, until, mainBranch, excludeCommits); err == nil {
 			bundle.Cadence = &c
+		} else {
+			errs[metrics.MetricCadence] = err
 		}
 	}
 	if sel.LeadTime {
-		if lt, err := metrics.ComputeLeadTime(primaryPath, author, since, until); err == nil {
+		if lt, err := metrics.ComputeLeadTime(path, author, since, until, mainBranch, excludeCommits); err == nil {
 			bundle.LeadTime = &lt
+		} else {
+			errs[metrics.MetricLeadTime] = err
+		}
+	}
+	if sel.Deploy {
+		if d, err := metrics.ComputeDeployLeadTime(path, []string{author}, since, until, deploySource()); err == nil {
+			bundle.Deploy = &d
+		} else {
+			errs[metrics.MetricDeploy] = err
 		}
 	}
 	if sel.Churn {
-		if ch, err := metrics.ComputeChurn(primaryPath, author, since, until, cWindow, exclude); err == nil {
+		if ch, err := metrics.ComputeChurn(path, author, since, until, cWindow, exclude, excludeCommits); err == nil {
 			bundle.Churn = &ch
+		} else {
+			errs[metrics.MetricChurn] = err
+		}
+	}
+	if sel.Reverts {
+		if rv, err := metrics.ComputeReverts(path, author, since, until, cWindow, hotfixBranches, mainBranch, excludeCommits); err == nil {
+			bundle.Reverts = &rv
+		} else {
+			errs[metrics.MetricReverts] = err
 		}
 	}
+	bundle.Diagnostics = metrics.Diagnose(bundle, path, errs)
 	return bundle
 }
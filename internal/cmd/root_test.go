@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/metrics"
+	"github.com/juangracia/gitrespect/internal/report"
+)
+
+// normalRepo has eight weeks of steady work before March 2026, a@x.com
+// committing every Wednesday netting 50, 100, ... 400 lines, then a busier
+// fortnight: 500 lines on Tuesday 3 and Thursday 5 March. Each of others
+// commits 100 lines every Thursday and on Wednesday 4 March. Commits are
+// made in date order, as git log's date limits expect.
+func normalRepo(t *testing.T, others ...string) *testRepo {
+	t.Helper()
+	r := newTestRepo(t)
+	commit := func(email, name string, n int, ts time.Time) {
+		r.writeFile(name, lines(n))
+		r.commit("work", email, ts)
+	}
+	for w := 0; w < 8; w++ {
+		commit("a@x.com", fmt.Sprintf("week%d.txt", w), 50*(w+1), time.Date(2026, 1, 7+7*w, 12, 0, 0, 0, time.Local))
+		for _, email := range others {
+			commit(email, fmt.Sprintf("%s-week%d.txt", email, w), 100, time.Date(2026, 1, 8+7*w, 12, 0, 0, 0, time.Local))
+		}
+	}
+	commit("a@x.com", "march3.txt", 500, time.Date(2026, 3, 3, 12, 0, 0, 0, time.Local))
+	for _, email := range others {
+		commit(email, email+"-march.txt", 100, time.Date(2026, 3, 4, 12, 0, 0, 0, time.Local))
+	}
+	commit("a@x.com", "march5.txt", 500, time.Date(2026, 3, 5, 12, 0, 0, 0, time.Local))
+	return r
+}
+
+func TestAnalyzeNormal(t *testing.T) {
+	r := normalRepo(t)
+	restoreFlags(t, &author, &since, &until, &output, &file, &metricsFlag, &baselineWindow, &churnWindow)
+	restoreFlags(t, &year)
+	restoreFlags(t, &legacyBenchmark, &seasonal, &perRepo)
+	author, since, until, year = "a@x.com", "2026-03-02", "2026-03-13", 0
+	metricsFlag, baselineWindow, churnWindow = "commit-size,cadence", "8w", "30d"
+	legacyBenchmark, seasonal, perRepo = false, false, false
+
+	output, file = "json", filepath.Join(t.TempDir(), "report.json")
+	if err := runAnalyze(rootCmd, []string{r.path}); err != nil {
+		t.Fatalf("runAnalyze: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got report.JSONReport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Metrics == nil || got.Metrics.Baseline == nil || got.Metrics.Normal == nil {
+		t.Fatalf("metrics = %+v", got.Metrics)
+	}
+
+	// Weekly samples of 10, 20, ... 80 lines/day: the band is their median
+	// and interquartile range, and 1000 lines over the period's seven working
+	// days (by git.WorkingDays' estimate) is above it.
+	b := got.Metrics.Baseline
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"median", b.LOCPerDay, 45},
+		{"p25", b.P25, 27.5},
+		{"p75", b.P75, 62.5},
+		{"period", b.PeriodLOCPerDay, 1000 / 7.0},
+		{"delta", b.PercentDelta, (1000/7.0 - 45) / 45 * 100},
+	} {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("baseline %s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if b.Weeks != 8 || b.InsufficientHistory || b.Position != "above" {
+		t.Errorf("baseline weeks %d, insufficient %v, position %q", b.Weeks, b.InsufficientHistory, b.Position)
+	}
+
+	// The normal covers the window's commits only, not the period's.
+	n := got.Metrics.Normal
+	if n.Window == nil || n.Window.Since != "2026-01-05" || n.Window.Until != "2026-03-02" {
+		t.Errorf("normal window = %+v", n.Window)
+	}
+	if n.CommitSize == nil || n.CommitSize.Total != 8 || n.CommitSize.P50 != 200 {
+		t.Errorf("normal commit size = %+v", n.CommitSize)
+	}
+	if n.Cadence == nil || n.Cadence.MedianDaysBetween != 7 {
+		t.Errorf("normal cadence = %+v", n.Cadence)
+	}
+	if c := got.Metrics.CommitSize; c == nil || c.Total != 2 || c.P50 != 500 {
+		t.Errorf("period commit size = %+v", c)
+	}
+
+	// Each metric reads against its normal.
+	output, file = "markdown", filepath.Join(t.TempDir(), "report.md")
+	if err := runAnalyze(rootCmd, []string{r.path}); err != nil {
+		t.Fatalf("runAnalyze: %v", err)
+	}
+	md, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Your normal: 45 lines/day (typical week 28–62) → this period: 143 (above your normal range",
+		"- p50 500 · p90 500 · p99 500 lines changed _(your normal: 200 lines)_",
+		"median 2.0 days between commits to main _(your normal: 7.0 days)_",
+	} {
+		if !strings.Contains(string(md), want) {
+			t.Errorf("markdown lacks %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestAnalyzeTeamBaseline(t *testing.T) {
+	r := normalRepo(t, "b@x.com")
+	restoreFlags(t, &metricsFlag, &baselineWindow, &churnWindow)
+	restoreFlags(t, &legacyBenchmark, &seasonal, &strict)
+	metricsFlag, baselineWindow, churnWindow = "", "8w", "30d"
+	legacyBenchmark, seasonal, strict = false, false, false
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	until := time.Date(2026, 3, 13, 23, 59, 59, 0, time.Local)
+
+	stats, teamBundle, bundles, err := analyzeTeam([]string{r.path}, []string{"a@x.com", "b@x.com"}, since, until)
+	if err != nil {
+		t.Fatalf("analyzeTeam: %v", err)
+	}
+	if stats.TotalNet != 1100 {
+		t.Fatalf("team net = %d, want 1100", stats.TotalNet)
+	}
+
+	// The team's band is built from its combined weeks, 30 to 100 lines/day,
+	// not from either member's, and each period is the seven working days'
+	// output of whoever the band belongs to.
+	for _, c := range []struct {
+		who                   string
+		b                     *metrics.Baseline
+		median, p25, p75, loc float64
+		position              string
+	}{
+		{"team", teamBundle.Baseline, 65, 47.5, 82.5, 1100 / 7.0, metrics.PositionAbove},
+		{"a", bundles["a@x.com"].Baseline, 45, 27.5, 62.5, 1000 / 7.0, metrics.PositionAbove},
+		{"b", bundles["b@x.com"].Baseline, 20, 20, 20, 100 / 7.0, metrics.PositionBelow},
+	} {
+		if c.b == nil {
+			t.Errorf("%s: no baseline", c.who)
+			continue
+		}
+		for _, v := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"median", c.b.LOCPerDay, c.median},
+			{"p25", c.b.P25, c.p25},
+			{"p75", c.b.P75, c.p75},
+			{"period", c.b.PeriodLOCPerDay, c.loc},
+			{"delta", c.b.PercentDelta, (c.loc - c.median) / c.median * 100},
+		} {
+			if math.Abs(v.got-v.want) > 1e-9 {
+				t.Errorf("%s baseline %s = %v, want %v", c.who, v.name, v.got, v.want)
+			}
+		}
+		if c.b.Position != c.position {
+			t.Errorf("%s position = %q, want %q", c.who, c.b.Position, c.position)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/juangracia/gitrespect/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [" + strings.Join(report.SchemaNames(), "|") + "]",
+	Short: "Print the JSON Schema of the JSON reports",
+	Long: `Print the JSON Schema (draft 2020-12) of --output json, generated from the
+types gitrespect writes. With no argument the schema accepts any report;
+name one to get just its schema.
+
+Every JSON report carries a schema_version (MAJOR.MINOR). Within a major
+version fields are only added, never removed, renamed or retyped, so
+validate with unknown properties allowed and check the major version.
+
+Example:
+  gitrespect schema > gitrespect.schema.json
+  gitrespect schema team`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: report.SchemaNames(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+		data, err := report.Schema(name)
+		if err != nil {
+			return err
+		}
+		if file == "" {
+			fmt.Println(string(data))
+			return nil
+		}
+		if err := os.WriteFile(file, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		fmt.Printf("✓ Schema saved to %s\n", file)
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&file, "file", "f", "", "Output file path")
+	rootCmd.AddCommand(schemaCmd)
+}
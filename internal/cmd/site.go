@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+	"github.com/juangracia/gitrespect/internal/report"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	siteOut   string
+	siteTeams []string
+	siteTitle string
+)
+
+var siteCmd = &cobra.Command{
+	Use:   "site [paths...]",
+	Short: "Generate a linked static HTML site for teams, members and repositories",
+	Long: `Generate a static HTML site into --out: an index of teams, and a page
+per team, per member, per repository and per team comparison, all linked
+by a navigation bar with a search box. The pages share one stylesheet and
+script in assets/, and open from the filesystem without a server.
+
+Repeat --team name=EMAILS for each team. Member and repository pages cover
+every team's members. Compare pages put each team's --period ranges side
+by side, by default the period before this one against this one.
+
+Pages take the same analysis flags as gitrespect, and --theme,
+--theme-file and --template. A team, comparison or repository that can't
+be analyzed is left out with a warning; with --strict the site fails
+instead.
+
+Example:
+  gitrespect site ./api ./web --out site --since 2026-07-01 \
+    --team platform=alice@x.com,bob@x.com --team mobile=carol@x.com
+  gitrespect site -r ~/src --out site --year 2026 --metrics all \
+    --team core=alice@x.com,bob@x.com --period h1=2026-01:2026-06 --period h2=2026-07:2026-12`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runSite,
+}
+
+func init() {
+	// The analysis flags are gitrespect's own; the site picks the pages.
+	rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		switch f.Name {
+		case "author", "team", "output", "file", "explain":
+			return
+		}
+		siteCmd.Flags().AddFlag(f)
+	})
+	siteCmd.Flags().StringVar(&siteOut, "out", "", "Directory to write the site into (created if missing)")
+	siteCmd.Flags().StringArrayVar(&siteTeams, "team", nil, "Team name=EMAILS (comma-separated; repeatable)")
+	siteCmd.Flags().StringArrayVar(&periodFlags, "period", nil, "Labeled period name=RANGE for the compare pages (repeatable; the first is the reference)")
+	siteCmd.Flags().StringVar(&siteTitle, "title", "gitrespect", "Site title, shown in the navigation bar")
+	siteCmd.MarkFlagRequired("out")
+
+	rootCmd.AddCommand(siteCmd)
+}
+
+// siteTeam is one --team of a site.
+type siteTeam struct {
+	name    string
+	members []string
+	slug    string
+}
+
+// parseSiteTeams parses every --team name=EMAILS. A team without a name is
+// named after its position.
+func parseSiteTeams() ([]siteTeam, error) {
+	if len(siteTeams) == 0 {
+		return nil, fmt.Errorf("need at least one team: repeat --team name=a@x.com,b@x.com")
+	}
+	var teams []siteTeam
+	slugs := make(map[string]bool)
+	for i, raw := range siteTeams {
+		name, list, ok := strings.Cut(raw, "=")
+		if !ok {
+			name, list = fmt.Sprintf("team %d", i+1), raw
+		}
+		name = strings.TrimSpace(name)
+		var members []string
+		for _, m := range strings.Split(list, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				members = append(members, m)
+			}
+		}
+		if name == "" || len(members) == 0 {
+			return nil, fmt.Errorf("invalid --team %q: want name=a@x.com,b@x.com", raw)
+		}
+		teams = append(teams, siteTeam{name: name, members: members, slug: uniqueSlug(name, slugs)})
+	}
+	return teams, nil
+}
+
+// sitePeriods returns the compare pages' periods: --period, else the period
+// of the same length before [since, until] and [since, until] itself.
+func sitePeriods(sinceTime, untilTime time.Time) ([]comparePeriod, error) {
+	if len(periodFlags) > 0 {
+		return parseComparePeriods()
+	}
+	length := untilTime.Sub(sinceTime)
+	prevEnd := sinceTime.Add(-time.Second)
+	prevStart := prevEnd.Add(-length)
+	label := func(start, end time.Time) string {
+		return start.Format("2006-01-02") + ".." + end.Format("2006-01-02")
+	}
+	return []comparePeriod{
+		{label: label(prevStart, prevEnd), start: prevStart, end: prevEnd},
+		{label: label(sinceTime, untilTime), start: sinceTime, end: untilTime},
+	}, nil
+}
+
+// slugify turns a name into a file name: lowercase letters and digits
+// separated by dashes.
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if b.Len() == 0 {
+		return "page"
+	}
+	return b.String()
+}
+
+// uniqueSlug returns name's slug, numbered when another name already took it.
+func uniqueSlug(name string, taken map[string]bool) string {
+	slug := slugify(name)
+	for i := 2; taken[slug]; i++ {
+		slug = fmt.Sprintf("%s-%d", slugify(name), i)
+	}
+	taken[slug] = true
+	return slug
+}
+
+// siteEntry is an index row of stats over the site's period.
+func siteEntry(name, href, note string, commits, net, workingDays int) report.SiteEntryHTMLData {
+	return report.SiteEntryHTMLData{
+		Name:    name,
+		Href:    href,
+		Note:    note,
+		Commits: commits,
+		Net:     net,
+		PerDay:  float64(net) / float64(workingDays),
+	}
+}
+
+func runSite(cmd *cobra.Command, args []string) error {
+	paths, err := resolvePaths(args)
+	if err != nil {
+		return err
+	}
+	sinceTime, untilTime, err := parseDates()
+	if err != nil {
+		return err
+	}
+	teams, err := parseSiteTeams()
+	if err != nil {
+		return err
+	}
+	periods, err := sitePeriods(sinceTime, untilTime)
+	if err != nil {
+		return err
+	}
+	for _, dir := range []string{"teams", "members", "repos", "compare"} {
+		if err := os.MkdirAll(filepath.Join(siteOut, dir), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Join(siteOut, dir), err)
+		}
+	}
+
+	workingDays := git.WorkingDays(sinceTime, untilTime)
+	index := report.SiteHTMLData{
+		Title: siteTitle,
+		Since: sinceTime.Format("Jan 2, 2006"),
+		Until: untilTime.Format("Jan 2, 2006"),
+	}
+	var search []report.SiteSearchEntry
+	pages := 0
+
+	// style places a page in the site, under a section and with links
+	// relative to the site's root.
+	style := func(section, current string, links []report.SiteLink) report.HTMLStyle {
+		s := htmlStyle()
+		for i := range links {
+			links[i].Current = links[i].Href == current
+		}
+		s.Site = &report.SitePage{Title: siteTitle, Section: section, Root: "../", Links: links}
+		return s
+	}
+
+	// Members are analyzed with their teams; a member of several teams gets
+	// one page, from the first.
+	type sitePerson struct {
+		stats  git.RepoStats
+		bundle metrics.Bundle
+		slug   string
+		teams  []int
+	}
+	people := make(map[string]*sitePerson)
+	memberSlugs := make(map[string]bool)
+	var emails []string
+	analyzed := make([]bool, len(teams))
+	teamStats := make([]git.TeamStats, len(teams))
+	teamBundles := make([]metrics.Bundle, len(teams))
+	memberBundles := make([]map[string]metrics.Bundle, len(teams))
+
+	for i, t := range teams {
+		stats, teamBundle, bundles, err := analyzeTeam(paths, t.members, sinceTime, untilTime)
+		if err != nil {
+			if strict {
+				return fmt.Errorf("team %s: %w", t.name, err)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: skipping team %s: %v\n", t.name, err)
+			continue
+		}
+		analyzed[i] = true
+		teamStats[i], teamBundles[i], memberBundles[i] = stats, teamBundle, bundles
+		for _, m := range t.members {
+			ms, ok := stats.Members[m]
+			if !ok {
+				continue
+			}
+			p, seen := people[m]
+			if !seen {
+				b := bundles[m]
+				b.Outliers = git.FindOutliers(ms)
+				p = &sitePerson{stats: ms, bundle: b, slug: uniqueSlug(m, memberSlugs)}
+				people[m] = p
+				emails = append(emails, m)
+			}
+			p.teams = append(p.teams, i)
+		}
+	}
+	if len(people) == 0 {
+		return fmt.Errorf("no team could be analyzed")
+	}
+	sort.Strings(emails)
+
+	teamHref := func(i int) string { return "teams/" + teams[i].slug + ".html" }
+	compareHref := func(i int) string { return "compare/" + teams[i].slug + ".html" }
+	memberHref := func(m string) string { return "members/" + people[m].slug + ".html" }
+
+	// Team pages link to their members and comparison.
+	for i, t := range teams {
+		if !analyzed[i] {
+			continue
+		}
+		comparison, details, err := compareAuthors(paths, t.members, true, periods)
+		compared := err == nil
+		if !compared {
+			if strict {
+				return fmt.Errorf("comparison of team %s: %w", t.name, err)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: skipping comparison of team %s: %v\n", t.name, err)
+		}
+
+		links := []report.SiteLink{{Label: "Team", Href: teamHref(i)}}
+		if compared {
+			links = append(links, report.SiteLink{Label: "Compare", Href: compareHref(i)})
+		}
+		for _, m := range t.members {
+			if _, ok := teamStats[i].Members[m]; ok {
+				links = append(links, report.SiteLink{Label: m, Href: memberHref(m)})
+			}
+		}
+
+		if err := report.TeamHTML(teamStats[i], filepath.Join(siteOut, teamHref(i)), style("Team "+t.name, teamHref(i), links), breakdown, teamBundles[i], memberBundles[i]); err != nil {
+			return err
+		}
+		pages++
+		search = append(search, report.SiteSearchEntry{Name: t.name, Kind: "team", Href: teamHref(i)})
+
+		entry := siteEntry(t.name, teamHref(i), strings.Join(t.members, ", "), teamStats[i].TotalCommits, teamStats[i].TotalNet, workingDays)
+		if compared {
+			links := append([]report.SiteLink(nil), links...)
+			if err := report.CompareHTML(comparison, filepath.Join(siteOut, compareHref(i)), style("Compare "+t.name, compareHref(i), links), details); err != nil {
+				return err
+			}
+			pages++
+			entry.CompareHref = compareHref(i)
+			search = append(search, report.SiteSearchEntry{Name: t.name + " over time", Kind: "comparison", Href: compareHref(i)})
+		}
+		index.Teams = append(index.Teams, entry)
+	}
+
+	// Member pages link to their teams.
+	for _, m := range emails {
+		p := people[m]
+		var links []report.SiteLink
+		var names []string
+		for _, i := range p.teams {
+			links = append(links, report.SiteLink{Label: teams[i].name, Href: teamHref(i)})
+			names = append(names, teams[i].name)
+		}
+		if err := report.HTML(p.stats, filepath.Join(siteOut, memberHref(m)), breakdown, style(m, memberHref(m), links), p.bundle); err != nil {
+			return err
+		}
+		pages++
+		search = append(search, report.SiteSearchEntry{Name: m, Kind: "member", Href: memberHref(m)})
+		index.Members = append(index.Members, siteEntry(m, memberHref(m), strings.Join(names, ", "), p.stats.Commits, p.stats.Net, workingDays))
+	}
+
+	// Repository pages show every member's work in the repository.
+	repoSlugs := make(map[string]bool)
+	for _, path := range paths {
+		name := filepath.Base(path)
+		href := "repos/" + uniqueSlug(name, repoSlugs) + ".html"
+		stats, teamBundle, bundles, err := analyzeTeam([]string{path}, emails, sinceTime, untilTime)
+		if err != nil {
+			if strict {
+				return fmt.Errorf("repository %s: %w", path, err)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: skipping repository %s: %v\n", path, err)
+			continue
+		}
+		if err := report.TeamHTML(stats, filepath.Join(siteOut, href), style("Repository "+name, href, nil), breakdown, teamBundle, bundles); err != nil {
+			return err
+		}
+		pages++
+		search = append(search, report.SiteSearchEntry{Name: name, Kind: "repository", Href: href})
+		index.Repos = append(index.Repos, siteEntry(name, href, path, stats.TotalCommits, stats.TotalNet, workingDays))
+	}
+
+	indexStyle := htmlStyle()
+	indexStyle.Site = &report.SitePage{Title: siteTitle}
+	if err := report.SiteIndex(siteOut, indexStyle, index); err != nil {
+		return err
+	}
+	if err := report.SiteAssets(siteOut, search); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Site saved to %s (%d pages)\n", filepath.Join(siteOut, "index.html"), pages+1)
+	return nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/report"
+)
+
+// siteRepo creates a repository at dir with one commit per author.
+func siteRepo(t *testing.T, dir string, authors ...string) {
+	t.Helper()
+	r := newTestRepoAt(t, dir)
+	for i, author := range authors {
+		r.writeFile(strings.NewReplacer("@", "_", ".", "_").Replace(author)+".go", strings.Repeat("line\n", 10*(i+1)))
+		r.commit("work by "+author, author, time.Date(2026, 3, 2+i, 12, 0, 0, 0, time.UTC))
+	}
+}
+
+var siteLinkRe = regexp.MustCompile(`(?:href|src)="([^"#:]+)"`)
+
+func TestSiteLinks(t *testing.T) {
+	dir := t.TempDir()
+	// Two repositories named api, two teams and two members whose names
+	// slugify alike.
+	siteRepo(t, filepath.Join(dir, "one", "api"), "a.b@x.com", "c@x.com")
+	siteRepo(t, filepath.Join(dir, "two", "api"), "a-b@x.com", "c@x.com")
+
+	restoreFlags(t, &siteOut, &since, &until)
+	restoreFlags(t, &siteTeams)
+	restoreFlags(t, &year)
+	restoreFlags(t, &recursive)
+	siteOut = filepath.Join(dir, "site")
+	siteTeams = []string{"Platform=a.b@x.com,c@x.com", "platform!=a-b@x.com,c@x.com"}
+	since, until, year, recursive = "2026-03-01", "2026-03-31", 0, false
+
+	if err := runSite(siteCmd, []string{filepath.Join(dir, "one", "api"), filepath.Join(dir, "two", "api")}); err != nil {
+		t.Fatalf("runSite: %v", err)
+	}
+
+	for _, page := range []string{
+		"teams/platform.html", "teams/platform-2.html",
+		"members/a-b-x-com.html", "members/a-b-x-com-2.html", "members/c-x-com.html",
+		"repos/api.html", "repos/api-2.html",
+	} {
+		if _, err := os.Stat(filepath.Join(siteOut, page)); err != nil {
+			t.Errorf("missing page %s", page)
+		}
+	}
+
+	pages, err := filepath.Glob(filepath.Join(siteOut, "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested, err := filepath.Glob(filepath.Join(siteOut, "*", "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	links := 0
+	for _, page := range append(pages, nested...) {
+		data, err := os.ReadFile(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range siteLinkRe.FindAllStringSubmatch(string(data), -1) {
+			links++
+			target := filepath.Join(filepath.Dir(page), m[1])
+			if _, err := os.Stat(target); err != nil {
+				rel, _ := filepath.Rel(siteOut, page)
+				t.Errorf("%s links to %s, which wasn't generated", rel, m[1])
+			}
+		}
+	}
+	if links == 0 {
+		t.Fatal("no links found")
+	}
+
+	// Search results link relative to the site's root.
+	script, err := os.ReadFile(filepath.Join(siteOut, "assets", "search.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := strings.TrimSuffix(strings.TrimPrefix(string(script), "window.gitrespectSearch = "), ";\n")
+	var search []report.SiteSearchEntry
+	if err := json.Unmarshal([]byte(raw), &search); err != nil {
+		t.Fatalf("search.js: %v", err)
+	}
+	if len(search) < 7 {
+		t.Errorf("search lists %d pages, want at least 7", len(search))
+	}
+	for _, e := range search {
+		if _, err := os.Stat(filepath.Join(siteOut, e.Href)); err != nil {
+			t.Errorf("search entry %s (%s) links to %s, which wasn't generated", e.Name, e.Kind, e.Href)
+		}
+	}
+}
+
+func TestSiteStrict(t *testing.T) {
+	dir := t.TempDir()
+	repo, notRepo := filepath.Join(dir, "api"), filepath.Join(dir, "notes")
+	siteRepo(t, repo, "a@x.com")
+	if err := os.MkdirAll(notRepo, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	restoreFlags(t, &siteOut, &since, &until)
+	restoreFlags(t, &siteTeams)
+	restoreFlags(t, &year)
+	restoreFlags(t, &recursive, &strict)
+	siteTeams = []string{"core=a@x.com"}
+	since, until, year, recursive = "2026-03-01", "2026-03-31", 0, false
+
+	// notes can't be analyzed: the site skips it, unless --strict.
+	siteOut, strict = filepath.Join(dir, "site"), false
+	if err := runSite(siteCmd, []string{repo, notRepo}); err != nil {
+		t.Fatalf("runSite: %v", err)
+	}
+	siteOut, strict = filepath.Join(dir, "strict"), true
+	if err := runSite(siteCmd, []string{repo, notRepo}); err == nil || !strings.HasPrefix(err.Error(), "team core: ") {
+		t.Errorf("runSite --strict: err = %v, want team core's failure", err)
+	}
+	if _, err := os.Stat(filepath.Join(siteOut, "index.html")); err == nil {
+		t.Error("runSite --strict wrote a partial site")
+	}
+}
+
+func TestParseDatesOrder(t *testing.T) {
+	restoreFlags(t, &since, &until)
+	restoreFlags(t, &year)
+
+	for _, tc := range []struct {
+		since, until string
+		year         int
+		ok           bool
+	}{
+		{"2026-03-01", "2026-03-01", 0, true}, // --until covers its whole day
+		{"2026-03-01", "2026-03-31", 0, true},
+		{"2026-03-31", "2026-03-01", 0, false},
+		{"2999-01-01", "", 0, false},
+		{"", "", 2999, false},
+	} {
+		since, until, year = tc.since, tc.until, tc.year
+		if _, _, err := parseDates(); (err == nil) != tc.ok {
+			t.Errorf("since %q until %q year %d: err = %v, want ok %v", tc.since, tc.until, tc.year, err, tc.ok)
+		}
+	}
+}
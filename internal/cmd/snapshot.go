@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/snapshot"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// outputSnapshot is the output of runAnalyze when called by snapshot: the
+// JSON report, stored rather than printed.
+const outputSnapshot = "snapshot"
+
+var snapshotStore string
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot [paths...]",
+	Short: "Store this period's report for tracking trends across runs",
+	Long: `Run the same analysis as gitrespect and store the report, metrics and
+baselines included, in a local snapshot store instead of printing it.
+
+Snapshots are kept per author (or --team) and set of repositories, and per
+period: taking one again for the same period replaces it. Chart them with
+gitrespect trend. A repository is known by its origin's URL, or its first
+commit when it has no hosted origin, so moving or re-cloning it keeps its
+snapshots.
+
+The store is ` + "`snapshots.db`" + ` in your config directory (e.g. ~/.config/gitrespect),
+or $GITRESPECT_STORE, or --store.
+
+Example:
+  gitrespect snapshot --since 2026-07-01 --until 2026-09-30 --metrics all
+  gitrespect snapshot ./api ./web --team alice@x.com,bob@x.com --since 2026-07-01 --until 2026-09-30`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output = outputSnapshot
+		return runAnalyze(cmd, args)
+	},
+}
+
+func init() {
+	// The analysis flags are gitrespect's own; only how the report is
+	// written differs.
+	rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		switch f.Name {
+		case "output", "file", "theme", "theme-file", "template", "explain":
+			return
+		}
+		snapshotCmd.Flags().AddFlag(f)
+	})
+	snapshotCmd.Flags().StringVar(&snapshotStore, "store", "", "Snapshot store file (default: $GITRESPECT_STORE, else snapshots.db in your config directory)")
+
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+// openStore opens the snapshot store named by --store or the default one.
+func openStore(readOnly bool) (*snapshot.Store, string, error) {
+	path := snapshotStore
+	if path == "" {
+		var err error
+		if path, err = snapshot.DefaultPath(); err != nil {
+			return nil, "", err
+		}
+	}
+	var s *snapshot.Store
+	var err error
+	if readOnly {
+		s, err = snapshot.OpenReadOnly(path)
+	} else {
+		s, err = snapshot.Open(path)
+	}
+	return s, path, err
+}
+
+// saveSnapshot stores a JSON report under subject and its period.
+func saveSnapshot(subject string, since, until time.Time, rep any) error {
+	data, err := json.Marshal(rep)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	store, path, err := openStore(false)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	snap := snapshot.Snapshot{
+		Subject: subject,
+		Since:   since.Format("2006-01-02"),
+		Until:   until.Format("2006-01-02"),
+		TakenAt: time.Now(),
+		Report:  data,
+	}
+	if err := store.Put(snap); err != nil {
+		return fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	fmt.Printf("✓ Snapshot of %s for %s saved to %s\n", subject, snap.Period(), path)
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/juangracia/gitrespect/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates [dir]",
+	Short: "Copy the built-in HTML templates for customizing",
+	Long: `Copy the built-in HTML templates into dir (default: gitrespect-templates),
+to edit and pass back with --template dir. Files left out of the directory,
+or deleted from it, keep their built-in version, so keep only the ones you
+change.
+
+report.html, team.html, compare.html and dora.html are the pages;
+theme.html (the colors) and interactive.html (the charts) are shared by
+them. The data each page is given is documented in docs/templates.md.
+
+For colors alone, --theme-file is simpler: a CSS file overriding the theme's
+variables, e.g. :root { --accent: #e4007c; }.
+
+Example:
+  gitrespect templates brand
+  gitrespect --output html --template brand --theme-file brand.css`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "gitrespect-templates"
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		paths, err := report.WriteTemplates(dir)
+		if err != nil {
+			return err
+		}
+		for _, p := range paths {
+			fmt.Printf("✓ Template saved to %s\n", p)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testRepo struct {
+	t    *testing.T
+	path string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	return newTestRepoAt(t, t.TempDir())
+}
+
+// newTestRepoAt creates the repository at dir, for tests that care about
+// its name.
+func newTestRepoAt(t *testing.T, dir string) *testRepo {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(t, dir, "git", "init", "-q", "-b", "main")
+	run(t, dir, "git", "config", "user.email", "test@example.com")
+	run(t, dir, "git", "config", "user.name", "Test")
+	run(t, dir, "git", "config", "commit.gpgsign", "false")
+	return &testRepo{t: t, path: dir}
+}
+
+func (r *testRepo) writeFile(name, content string) {
+	r.t.Helper()
+	p := filepath.Join(r.path, name)
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		r.t.Fatalf("writeFile: %v", err)
+	}
+	run(r.t, r.path, "git", "add", name)
+}
+
+// commit commits the staged changes as email at ts and returns the hash.
+func (r *testRepo) commit(msg, email string, ts time.Time) string {
+	r.t.Helper()
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test",
+		"GIT_AUTHOR_EMAIL="+email,
+		"GIT_COMMITTER_NAME=Test",
+		"GIT_COMMITTER_EMAIL="+email,
+		"GIT_AUTHOR_DATE="+ts.Format(time.RFC3339),
+		"GIT_COMMITTER_DATE="+ts.Format(time.RFC3339),
+	)
+	cmd := exec.Command("git", "-C", r.path, "commit", "-q", "--allow-empty", "-m", msg)
+	cmd.Env = env
+	if out, err := cmd.CombinedOutput(); err != nil {
+		r.t.Fatalf("commit failed: %v\n%s", err, out)
+	}
+	return r.revParse("HEAD")
+}
+
+func (r *testRepo) revParse(rev string) string {
+	r.t.Helper()
+	out, err := exec.Command("git", "-C", r.path, "rev-parse", rev).Output()
+	if err != nil {
+		r.t.Fatalf("rev-parse %s: %v", rev, err)
+	}
+	return string(out[:len(out)-1])
+}
+
+func run(t *testing.T, dir string, name string, args ...string) {
+	t.Helper()
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%s %v failed: %v\n%s", name, args, err, out)
+	}
+}
+
+// restoreFlags puts the flag variables back when the test ends, since
+// commands share them.
+func restoreFlags[T any](t *testing.T, vars ...*T) {
+	saved := make([]T, len(vars))
+	for i, v := range vars {
+		saved[i] = *v
+	}
+	t.Cleanup(func() {
+		for i, v := range vars {
+			*v = saved[i]
+		}
+	})
+}
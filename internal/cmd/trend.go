@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/report"
+	"github.com/juangracia/gitrespect/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trendMetric string
+	trendMember string
+	trendList   bool
+)
+
+var trendCmd = &cobra.Command{
+	Use:   "trend [paths...]",
+	Short: "Chart a metric across stored snapshots",
+	Long: `Chart how a metric changed across the snapshots taken with gitrespect
+snapshot, oldest period first, each against the one before it. Nothing is
+recomputed: a team can be tracked quarter over quarter from what was stored.
+
+Snapshots are found by the same author (or --team) and repositories they
+were taken with, wherever those repositories are checked out now. --list
+shows everything stored.
+
+--metric picks what to chart: net_per_day (the default), baseline_per_day,
+baseline_delta_pct, any opt-in metric the snapshots hold (e.g. churn_pct,
+lead_time_median_days, commit_size_p50_lines), or all. With --team,
+--member charts one member instead of the team.
+
+Example:
+  gitrespect trend --metric churn_pct
+  gitrespect trend ./api ./web --team alice@x.com,bob@x.com --metric all
+  gitrespect trend --list`,
+	Args: cobra.ArbitraryArgs,
+	RunE: runTrend,
+}
+
+func init() {
+	trendCmd.Flags().StringVarP(&author, "author", "a", "", "Author the snapshots were taken for (default: git config user.email)")
+	trendCmd.Flags().StringSliceVarP(&team, "team", "t", nil, "Team the snapshots were taken for (comma-separated emails)")
+	trendCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Scan subdirectories for git repositories")
+	trendCmd.Flags().StringVarP(&trendMetric, "metric", "m", "net_per_day", "Metric to chart, or 'all'")
+	trendCmd.Flags().StringVar(&trendMember, "member", "", "Chart this team member (with --team)")
+	trendCmd.Flags().BoolVar(&trendList, "list", false, "List the stored subjects and periods")
+	trendCmd.Flags().StringVar(&snapshotStore, "store", "", "Snapshot store file (default: $GITRESPECT_STORE, else snapshots.db in your config directory)")
+
+	rootCmd.AddCommand(trendCmd)
+}
+
+func runTrend(cmd *cobra.Command, args []string) error {
+	if trendMember != "" && len(team) == 0 {
+		return fmt.Errorf("--member needs --team")
+	}
+	store, path, err := openStore(true)
+	if errors.Is(err, snapshot.ErrNoStore) {
+		return fmt.Errorf("no snapshots in %s yet; take one with gitrespect snapshot", path)
+	}
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if trendList {
+		return listSnapshots(store)
+	}
+
+	paths, err := resolvePaths(args)
+	if err != nil {
+		return err
+	}
+	subject := snapshot.Subject(team, true, paths)
+	if len(team) == 0 {
+		email := author
+		if email == "" {
+			email, _ = git.GetDefaultAuthor(paths[0])
+		}
+		subject = snapshot.Subject([]string{email}, false, paths)
+	}
+
+	snaps, err := store.List(subject)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	if len(snaps) == 0 {
+		return fmt.Errorf("no snapshots of %s (see gitrespect trend --list)", subject)
+	}
+	var points []report.TrendPoint
+	for _, snap := range snaps {
+		saved, err := report.Load(snap.Report, snap.Period())
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		points = append(points, report.TrendPoint{Label: snap.Period(), Saved: saved})
+	}
+	return report.TrendTerminal(subject, points, trendMetric, trendMember)
+}
+
+// listSnapshots prints every stored subject with its periods.
+func listSnapshots(store *snapshot.Store) error {
+	subjects, err := store.Subjects()
+	if err != nil {
+		return fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	if len(subjects) == 0 {
+		fmt.Println("No snapshots yet.")
+		return nil
+	}
+	for _, subject := range subjects {
+		snaps, err := store.List(subject)
+		if err != nil {
+			return fmt.Errorf("failed to read snapshots: %w", err)
+		}
+		fmt.Println(subject)
+		for _, snap := range snaps {
+			fmt.Printf("  %s  (taken %s)\n", snap.Period(), snap.TakenAt.Format("Jan 2 2006 15:04"))
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/juangracia/gitrespect/internal/report"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +19,7 @@ var versionCmd = &cobra.Command{
 	Short: "Print version information",
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Printf("gitrespect %s\n", Version)
+		fmt.Printf("  report schema: %s\n", report.SchemaVersion)
 		if Commit != "none" {
 			fmt.Printf("  commit: %s\n", Commit)
 			fmt.Printf("  built:  %s\n", Date)
@@ -2,9 +2,11 @@ package git
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +25,32 @@ type RepoStats struct {
 	Commits      int
 	FilesChanged int
 	Monthly      map[string]MonthStats
+	Weekly       map[string]WeekStats // keyed by ISO week, e.g. "2025-W07"
+	Changes      []CommitChange       // every counted commit, with its files
+	Excluded     []string             // commits skipped by --exclude-commit or config
+	Repos        []string             // repositories CombineStats combined, without duplicates
+}
+
+// CommitChange is one commit's contribution to the totals: its files that
+// were counted, i.e. not binary and not excluded.
+type CommitChange struct {
+	Repo    string
+	Hash    string
+	Date    time.Time
+	Subject string
+	Added   int
+	Deleted int
+	Files   []FileChange
+}
+
+// Lines is the commit's total change, added plus deleted.
+func (c CommitChange) Lines() int { return c.Added + c.Deleted }
+
+// FileChange is one file's lines in a commit.
+type FileChange struct {
+	Path    string
+	Added   int
+	Deleted int
 }
 
 type MonthStats struct {
@@ -34,11 +62,27 @@ type MonthStats struct {
 	Commits int
 }
 
+// WeekStats holds line counts for one ISO week.
+type WeekStats struct {
+	Year    int
+	Week    int
+	Added   int
+	Deleted int
+	Net     int
+	Commits int
+}
+
+// CompareStats holds combined stats for two or more labeled periods in the
+// order they were requested. The first period is the reference the others
+// are compared against.
 type CompareStats struct {
-	Before      RepoStats
-	After       RepoStats
-	BeforeLabel string
-	AfterLabel  string
+	Periods []LabeledStats
+}
+
+// LabeledStats is one named period of a comparison.
+type LabeledStats struct {
+	Label string
+	Stats RepoStats
 }
 
 type TeamStats struct {
@@ -52,18 +96,28 @@ type TeamStats struct {
 	Monthly      map[string]MonthStats
 }
 
-func Analyze(repoPath, author string, since, until time.Time, excludePatterns []string) (RepoStats, error) {
+// ExcludeCommitConfigKey is the git config key a repository can set (possibly
+// several times) to leave commits out of every total, e.g.
+// `git config --add gitrespect.excludeCommit 3f2a9c1`.
+const ExcludeCommitConfigKey = "gitrespect.excludeCommit"
+
+// Analyze sums the author's lines added and deleted in [since, until].
+// Files matching excludePatterns are skipped, and so are commits whose hash
+// starts with one of excludeCommits or the repo's ExcludeCommitConfigKey
+// entries.
+func Analyze(repoPath, author string, since, until time.Time, excludePatterns, excludeCommits []string) (RepoStats, error) {
 	stats := RepoStats{
 		Path:    repoPath,
 		Author:  author,
 		Since:   since,
 		Until:   until,
 		Monthly: make(map[string]MonthStats),
+		Weekly:  make(map[string]WeekStats),
 	}
 
 	// Build git log command
-	sinceStr := since.Format("2006-01-02")
-	untilStr := until.Format("2006-01-02")
+	sinceStr := LogDate(since)
+	untilStr := LogDate(until)
 
 	// Get commit stats with numstat
 	args := []string{
@@ -72,7 +126,7 @@ func Analyze(repoPath, author string, since, until time.Time, excludePatterns []
 		"--author=" + author,
 		"--since=" + sinceStr,
 		"--until=" + untilStr,
-		"--pretty=format:%H|%ad",
+		"--pretty=format:%H%x1f%ad%x1f%s",
 		"--date=short",
 		"--numstat",
 	}
@@ -83,9 +137,13 @@ func Analyze(repoPath, author string, since, until time.Time, excludePatterns []
 		return stats, fmt.Errorf("git log failed: %w", err)
 	}
 
+	excluded := ExcludedCommits(repoPath, excludeCommits)
 	lines := strings.Split(string(output), "\n")
 	var currentDate string
 	var currentMonth string
+	var currentWeek string
+	var change *CommitChange
+	skipping := false
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -94,20 +152,38 @@ func Analyze(repoPath, author string, since, until time.Time, excludePatterns []
 		}
 
 		// Check if it's a commit header line
-		if strings.Contains(line, "|") {
-			parts := strings.Split(line, "|")
+		if strings.Contains(line, "\x1f") {
+			parts := strings.SplitN(line, "\x1f", 3)
 			if len(parts) >= 2 {
+				skipping = excluded(parts[0])
+				if skipping {
+					stats.Excluded = append(stats.Excluded, parts[0])
+					continue
+				}
 				currentDate = parts[1]
 				stats.Commits++
+				stats.Changes = append(stats.Changes, CommitChange{Repo: repoPath, Hash: parts[0]})
+				change = &stats.Changes[len(stats.Changes)-1]
+				if len(parts) == 3 {
+					change.Subject = parts[2]
+				}
 
 				// Track first and last commit dates
+				currentWeek = ""
 				if commitDate, err := time.Parse("2006-01-02", currentDate); err == nil {
+					change.Date = commitDate
 					if stats.FirstCommit.IsZero() || commitDate.Before(stats.FirstCommit) {
 						stats.FirstCommit = commitDate
 					}
 					if stats.LastCommit.IsZero() || commitDate.After(stats.LastCommit) {
 						stats.LastCommit = commitDate
 					}
+
+					currentWeek = WeekKey(commitDate)
+					w := stats.Weekly[currentWeek]
+					w.Year, w.Week = commitDate.ISOWeek()
+					w.Commits++
+					stats.Weekly[currentWeek] = w
 				}
 
 				// Parse month
@@ -120,7 +196,7 @@ func Analyze(repoPath, author string, since, until time.Time, excludePatterns []
 
 		// Parse numstat line: added\tdeleted\tfilename
 		fields := strings.Fields(line)
-		if len(fields) >= 3 {
+		if len(fields) >= 3 && !skipping {
 			// Skip binary files (shown as -)
 			if fields[0] == "-" || fields[1] == "-" {
 				continue
@@ -139,6 +215,11 @@ func Analyze(repoPath, author string, since, until time.Time, excludePatterns []
 				stats.Added += added
 				stats.Deleted += deleted
 				stats.FilesChanged++
+				if change != nil {
+					change.Added += added
+					change.Deleted += deleted
+					change.Files = append(change.Files, FileChange{Path: filename, Added: added, Deleted: deleted})
+				}
 
 				// Update monthly stats
 				if currentMonth != "" {
@@ -157,6 +238,15 @@ func Analyze(repoPath, author string, since, until time.Time, excludePatterns []
 					}
 					stats.Monthly[currentMonth] = m
 				}
+
+				// Update weekly stats
+				if currentWeek != "" {
+					w := stats.Weekly[currentWeek]
+					w.Added += added
+					w.Deleted += deleted
+					w.Net = w.Added - w.Deleted
+					stats.Weekly[currentWeek] = w
+				}
 			}
 		}
 	}
@@ -175,69 +265,48 @@ func GetDefaultAuthor(repoPath string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-func ParseDate(dateStr string) (time.Time, error) {
-	// Try parsing as absolute date
-	formats := []string{
-		"2006-01-02",
-		"2006-01",
-		"2006",
-	}
-
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t, nil
+// RepoID names a repository wherever it is checked out: its origin's URL
+// without scheme, credentials or .git suffix (e.g. github.com/org/api), else
+// its earliest-sorting root commit, else repoPath itself.
+func RepoID(repoPath string) string {
+	if out, err := exec.Command("git", "-C", repoPath, "remote", "get-url", "origin").Output(); err == nil {
+		if id := remoteID(strings.TrimSpace(string(out))); id != "" {
+			return id
 		}
 	}
-
-	// Parse relative dates
-	dateStr = strings.ToLower(dateStr)
-
-	if strings.Contains(dateStr, "day") {
-		parts := strings.Fields(dateStr)
-		if len(parts) >= 2 {
-			n, err := strconv.Atoi(parts[0])
-			if err == nil {
-				return time.Now().AddDate(0, 0, -n), nil
-			}
-		}
-	}
-
-	if strings.Contains(dateStr, "week") {
-		parts := strings.Fields(dateStr)
-		if len(parts) >= 2 {
-			n, err := strconv.Atoi(parts[0])
-			if err == nil {
-				return time.Now().AddDate(0, 0, -n*7), nil
-			}
+	if out, err := exec.Command("git", "-C", repoPath, "rev-list", "--max-parents=0", "HEAD").Output(); err == nil {
+		if roots := strings.Fields(string(out)); len(roots) > 0 {
+			return slices.Min(roots)
 		}
 	}
+	return repoPath
+}
 
-	if strings.Contains(dateStr, "month") {
-		parts := strings.Fields(dateStr)
-		if len(parts) >= 2 {
-			n, err := strconv.Atoi(parts[0])
-			if err == nil {
-				return time.Now().AddDate(0, -n, 0), nil
-			}
+// remoteID returns host/path for a remote URL, either a URL or scp-like
+// (git@host:org/repo.git). Empty for a remote on the local disk, whose path
+// is no more stable than the repo's own.
+func remoteID(remote string) string {
+	var host, path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil || u.Scheme == "file" || u.Host == "" {
+			return ""
 		}
-	}
-
-	if strings.Contains(dateStr, "year") {
-		parts := strings.Fields(dateStr)
-		if len(parts) >= 2 {
-			n, err := strconv.Atoi(parts[0])
-			if err == nil {
-				return time.Now().AddDate(-n, 0, 0), nil
-			}
+		host, path = u.Host, u.Path
+	} else {
+		before, after, ok := strings.Cut(remote, ":")
+		if !ok || len(before) < 2 || strings.Contains(before, "/") {
+			return "" // a path, e.g. ../api.git or C:\src\api
 		}
+		host, path = before[strings.LastIndex(before, "@")+1:], after
 	}
-
-	return time.Time{}, fmt.Errorf("could not parse date: %s", dateStr)
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	return host + "/" + path
 }
 
 func CombineStats(stats []RepoStats) RepoStats {
 	if len(stats) == 0 {
-		return RepoStats{Monthly: make(map[string]MonthStats)}
+		return RepoStats{Monthly: make(map[string]MonthStats), Weekly: make(map[string]WeekStats)}
 	}
 
 	combined := RepoStats{
@@ -245,13 +314,27 @@ func CombineStats(stats []RepoStats) RepoStats {
 		Since:   stats[0].Since,
 		Until:   stats[0].Until,
 		Monthly: make(map[string]MonthStats),
+		Weekly:  make(map[string]WeekStats),
 	}
 
+	seen := make(map[string]bool)
 	for _, s := range stats {
+		repos := s.Repos
+		if len(repos) == 0 && s.Path != "" {
+			repos = []string{s.Path}
+		}
+		for _, repo := range repos {
+			if !seen[repo] {
+				seen[repo] = true
+				combined.Repos = append(combined.Repos, repo)
+			}
+		}
 		combined.Added += s.Added
 		combined.Deleted += s.Deleted
 		combined.Commits += s.Commits
 		combined.FilesChanged += s.FilesChanged
+		combined.Changes = append(combined.Changes, s.Changes...)
+		combined.Excluded = append(combined.Excluded, s.Excluded...)
 
 		// Track earliest first commit and latest last commit
 		if !s.FirstCommit.IsZero() {
@@ -276,6 +359,18 @@ func CombineStats(stats []RepoStats) RepoStats {
 			existing.Month = m.Month
 			combined.Monthly[month] = existing
 		}
+
+		// Merge weekly stats
+		for week, w := range s.Weekly {
+			existing := combined.Weekly[week]
+			existing.Added += w.Added
+			existing.Deleted += w.Deleted
+			existing.Net = existing.Added - existing.Deleted
+			existing.Commits += w.Commits
+			existing.Year = w.Year
+			existing.Week = w.Week
+			combined.Weekly[week] = existing
+		}
 	}
 
 	combined.Net = combined.Added - combined.Deleted
@@ -289,6 +384,48 @@ func CombineStats(stats []RepoStats) RepoStats {
 	return combined
 }
 
+// ExcludedCommits returns a test for the commits left out of repoPath's
+// totals: those matching excludeCommits or the repo's ExcludeCommitConfigKey
+// entries.
+func ExcludedCommits(repoPath string, excludeCommits []string) func(hash string) bool {
+	excludes := append(configuredExcludes(repoPath), excludeCommits...)
+	return func(hash string) bool { return excludedCommit(hash, excludes) }
+}
+
+// configuredExcludes reads the repo's ExcludeCommitConfigKey entries.
+func configuredExcludes(repoPath string) []string {
+	out, err := exec.Command("git", "-C", repoPath, "config", "--get-all", ExcludeCommitConfigKey).Output()
+	if err != nil {
+		return nil
+	}
+	var shas []string
+	for _, line := range strings.Split(string(out), "\n") {
+		for _, sha := range strings.Split(line, ",") {
+			if sha = strings.TrimSpace(sha); sha != "" {
+				shas = append(shas, sha)
+			}
+		}
+	}
+	return shas
+}
+
+// excludedCommit reports whether hash starts with one of the excluded
+// (possibly abbreviated) hashes.
+func excludedCommit(hash string, excludes []string) bool {
+	for _, e := range excludes {
+		if len(e) >= 4 && strings.HasPrefix(hash, strings.ToLower(e)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WeekKey returns the ISO week key ("2025-W07") used by RepoStats.Weekly.
+func WeekKey(t time.Time) string {
+	y, w := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", y, w)
+}
+
 // WorkingDays calculates approximate working days between two dates
 func WorkingDays(since, until time.Time) int {
 	days := int(until.Sub(since).Hours() / 24)
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// aiAssistRe matches commit trailers crediting an AI assistant, e.g.
+// "Co-authored-by: Copilot <...>" or "Assisted-by: Cursor".
+var aiAssistRe = regexp.MustCompile(`(?im)^(co-authored-by|assisted-by|generated-by|ai-assisted-by):.*\b(copilot|claude|anthropic|openai|chatgpt|gpt-\d|cursor|codeium|tabnine|aider|devin|gemini|codex|windsurf)`)
+
+// CommitRecord is one commit with everything Analyze knows about it, for
+// export. Added and Deleted count the files Analyze would count; excluded
+// and binary files are listed but not counted.
+type CommitRecord struct {
+	Repo        string
+	Hash        string
+	Parents     []string
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  time.Time
+	CommitDate  time.Time
+	Subject     string
+	Added       int
+	Deleted     int
+	Files       []FileRecord
+	Excluded    bool // left out by --exclude-commit or ExcludeCommitConfigKey
+	AIAssisted  bool // a trailer credits an AI assistant
+}
+
+// FileRecord is one file of a CommitRecord.
+type FileRecord struct {
+	Path     string
+	Added    int
+	Deleted  int
+	Binary   bool
+	Excluded bool // matches an exclude pattern
+}
+
+// ListCommits returns the author's commits in [since, until] with the same
+// filters as Analyze, keeping excluded commits and files but marking them.
+// An empty author lists everyone's commits.
+func ListCommits(repoPath, author string, since, until time.Time, excludePatterns, excludeCommits []string) ([]CommitRecord, error) {
+	args := []string{
+		"-C", repoPath, "log",
+		"--author=" + author,
+		"--since=" + LogDate(since),
+		"--until=" + LogDate(until),
+		"--pretty=format:%x1e%H%x1f%P%x1f%an%x1f%ae%x1f%aI%x1f%cI%x1f%s%x1f%b%x1f",
+		"--numstat",
+	}
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	excluded := ExcludedCommits(repoPath, excludeCommits)
+	var records []CommitRecord
+	for _, chunk := range strings.Split(string(out), "\x1e") {
+		parts := strings.SplitN(chunk, "\x1f", 9)
+		if len(parts) < 9 {
+			continue
+		}
+		r := CommitRecord{
+			Repo:        repoPath,
+			Hash:        parts[0],
+			Parents:     strings.Fields(parts[1]),
+			AuthorName:  parts[2],
+			AuthorEmail: parts[3],
+			Subject:     parts[6],
+			Excluded:    excluded(parts[0]),
+			AIAssisted:  aiAssistRe.MatchString(parts[7]),
+		}
+		r.AuthorDate, _ = time.Parse(time.RFC3339, parts[4])
+		r.CommitDate, _ = time.Parse(time.RFC3339, parts[5])
+
+		for _, line := range strings.Split(parts[8], "\n") {
+			fields := strings.SplitN(strings.TrimSpace(line), "\t", 3)
+			if len(fields) < 3 {
+				continue
+			}
+			f := FileRecord{Path: fields[2], Excluded: shouldExclude(fields[2], excludePatterns)}
+			if fields[0] == "-" || fields[1] == "-" {
+				f.Binary = true
+			} else {
+				f.Added, _ = strconv.Atoi(fields[0])
+				f.Deleted, _ = strconv.Atoi(fields[1])
+			}
+			if !f.Binary && !f.Excluded {
+				r.Added += f.Added
+				r.Deleted += f.Deleted
+			}
+			r.Files = append(r.Files, f)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
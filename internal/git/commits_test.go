@@ -0,0 +1,90 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAIAssistTrailer(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		want bool
+	}{
+		{"Co-authored-by: Copilot <175728472+Copilot@users.noreply.github.com>", true},
+		{"Fix the parser.\n\nCo-Authored-By: Claude <noreply@anthropic.com>", true},
+		{"Assisted-by: Cursor", true},
+		{"Generated-by: GPT-4o", true},
+		{"Co-authored-by: Alice <alice@example.com>", false},
+		{"Mention copilot in the docs", false},
+		{"", false},
+	} {
+		if got := aiAssistRe.MatchString(tc.body); got != tc.want {
+			t.Errorf("%q: ai-assisted %v, want %v", tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestListCommits(t *testing.T) {
+	r := newTestRepo(t)
+	day := func(n int) time.Time { return time.Date(2025, 6, 1+n, 9, 0, 0, 0, time.UTC) }
+
+	r.writeFile("old.go", "package old\n")
+	r.commit("before the period", "a@x.com", day(0))
+
+	r.writeFile("main.go", "package main\n\nfunc main() {}\n")
+	r.writeFile("vendor.txt", "vendored\nlines\n")
+	r.writeFile("logo.png", "\x00\x01\x02")
+	first := r.commit("Add main\n\nA body line that looks like numstat:\n1\t2\tfake.go\n\nCo-authored-by: Copilot <copilot@users.noreply.github.com>", "a@x.com", day(2))
+
+	r.writeFile("main.go", "package main\n")
+	second := r.commit("Shrink main", "a@x.com", day(3))
+	r.writeFile("other.go", "package other\n")
+	third := r.commit("By someone else", "b@x.com", day(4))
+	run(t, r.path, "git", "config", "--add", ExcludeCommitConfigKey, third[:7])
+
+	got, err := ListCommits(r.path, "a@x.com", day(1), day(5), []string{"vendor.txt"}, []string{second[:8]})
+	if err != nil {
+		t.Fatalf("ListCommits: %v", err)
+	}
+	if len(got) != 2 || got[0].Hash != second || got[1].Hash != first {
+		t.Fatalf("got %d commits, want %s then %s: %+v", len(got), second, first, got)
+	}
+
+	c := got[1]
+	if c.Repo != r.path || c.Subject != "Add main" || c.AuthorName != "Test" || c.AuthorEmail != "a@x.com" {
+		t.Errorf("first commit = %+v", c)
+	}
+	if len(c.Parents) != 1 || !c.AuthorDate.Equal(day(2)) || !c.CommitDate.Equal(day(2)) {
+		t.Errorf("parents %v, dates %v and %v", c.Parents, c.AuthorDate, c.CommitDate)
+	}
+	if !c.AIAssisted || c.Excluded {
+		t.Errorf("AIAssisted=%v Excluded=%v, want true and false", c.AIAssisted, c.Excluded)
+	}
+	// vendor.txt and the binary logo are listed but not counted.
+	if c.Added != 3 || c.Deleted != 0 || len(c.Files) != 3 {
+		t.Errorf("Added=%d Deleted=%d with %d files, want 3, 0 and 3: %+v", c.Added, c.Deleted, len(c.Files), c.Files)
+	}
+	files := make(map[string]FileRecord)
+	for _, f := range c.Files {
+		files[f.Path] = f
+	}
+	if f := files["logo.png"]; !f.Binary || f.Added != 0 {
+		t.Errorf("logo.png = %+v, want binary", f)
+	}
+	if f := files["vendor.txt"]; !f.Excluded || f.Added != 2 {
+		t.Errorf("vendor.txt = %+v, want excluded with 2 added", f)
+	}
+
+	if s := got[0]; !s.Excluded || s.AIAssisted || s.Added != 0 || s.Deleted != 2 {
+		t.Errorf("second commit = %+v, want excluded with 2 deleted", s)
+	}
+
+	// No author lists everyone; the repo's config excludes third.
+	all, err := ListCommits(r.path, "", day(1), day(5), nil, nil)
+	if err != nil {
+		t.Fatalf("ListCommits: %v", err)
+	}
+	if len(all) != 3 || all[0].Hash != third || !all[0].Excluded || all[1].Excluded {
+		t.Errorf("everyone: %d commits, first %s excluded=%v", len(all), all[0].Hash, all[0].Excluded)
+	}
+}
@@ -0,0 +1,506 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateParser resolves date expressions against a fixed clock, so the same
+// input always yields the same result. Relative expressions ("yesterday",
+// "2 weeks 3 days ago") are computed from Now in Now's location, and
+// absolute dates without a zone are read in that location too.
+type DateParser struct {
+	Now time.Time
+}
+
+// ParseDate parses a --since/--until style date against the current time
+// and returns the start of the period it names. See DateParser.Date.
+func ParseDate(dateStr string) (time.Time, error) {
+	return DateParser{Now: time.Now()}.Date(dateStr)
+}
+
+// ParseUntil parses a --until style date against the current time and
+// returns the end of the period it names. See DateParser.Until.
+func ParseUntil(dateStr string) (time.Time, error) {
+	return DateParser{Now: time.Now()}.Until(dateStr)
+}
+
+// LogDate formats t for git log's --since and --until: the exact instant
+// with its offset, so a bound finer than a day reaches git as given.
+func LogDate(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+// ParseRange parses a period expression against the current time. See
+// DateParser.Range.
+func ParseRange(expr string) (time.Time, time.Time, error) {
+	return DateParser{Now: time.Now()}.Range(expr)
+}
+
+// dateSpan is what a date expression denotes: the calendar unit it names,
+// as [start, end). An instant like "30 days ago" has start == end.
+type dateSpan struct {
+	start time.Time
+	end   time.Time
+}
+
+func (s dateSpan) isInstant() bool {
+	return !s.end.After(s.start)
+}
+
+// lastInstant returns the last moment the span covers.
+func (s dateSpan) lastInstant() time.Time {
+	if s.isInstant() {
+		return s.start
+	}
+	return s.end.Add(-time.Second)
+}
+
+var (
+	quarterRe = regexp.MustCompile(`^(\d{4})-q([1-4])$`)
+	isoWeekRe = regexp.MustCompile(`^(\d{4})-w(\d{1,2})$`)
+	trailRe   = regexp.MustCompile(`^(?:last|past|previous)\s+(\d+)\s+([a-z]+)$`)
+	offsetRe  = regexp.MustCompile(`^(.+)\+(\d+)([dwmqy])$`)
+	compactRe = regexp.MustCompile(`^(?:\d+[a-z]+)+$`)
+	termRe    = regexp.MustCompile(`(\d+)([a-z]+)`)
+)
+
+// offsetUnits are the single-letter units of the A+N<unit> range form.
+var offsetUnits = map[string]string{
+	"d": "day", "w": "week", "m": "month", "q": "quarter", "y": "year",
+}
+
+// absoluteLayouts are tried in order; each names the unit it spans.
+var absoluteLayouts = []struct {
+	layout string
+	unit   string // "" for an instant
+}{
+	{time.RFC3339, ""},
+	{"2006-01-02T15:04:05", ""},
+	{"2006-01-02T15:04", ""},
+	{"2006-01-02 15:04:05", ""},
+	{"2006-01-02 15:04", ""},
+	{"2006-01-02", "day"},
+	{"2006-01", "month"},
+	{"2006", "year"},
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// unitNames maps every accepted spelling to a canonical unit.
+var unitNames = map[string]string{
+	"s": "second", "sec": "second", "secs": "second", "second": "second", "seconds": "second",
+	"min": "minute", "mins": "minute", "minute": "minute", "minutes": "minute",
+	"h": "hour", "hr": "hour", "hrs": "hour", "hour": "hour", "hours": "hour",
+	"d": "day", "day": "day", "days": "day",
+	"w": "week", "wk": "week", "wks": "week", "week": "week", "weeks": "week",
+	"mo": "month", "mon": "month", "mos": "month", "month": "month", "months": "month",
+	"q": "quarter", "quarter": "quarter", "quarters": "quarter",
+	"y": "year", "yr": "year", "yrs": "year", "year": "year", "years": "year",
+}
+
+// Date parses a single date expression and returns the start of the period
+// it names. Accepted forms (case-insensitive):
+//
+//	2025-06-01T09:00, 2025-06-01 09:00:00, RFC 3339   an exact instant
+//	2025-06-01, 2025-06, 2025, 2025-Q3, 2025-W07      a calendar unit
+//	now, today, yesterday, tomorrow
+//	monday, last friday, next tue                     a named day
+//	this week, last month, next quarter, this year    a calendar unit
+//	start of month, end of last quarter, start of 2025-Q3
+//	3 days ago, 2 weeks 3 days ago, a year ago, 1y2mo ago, in 2 weeks
+//	2 days before 2025-06-01, 1 week after last monday
+//
+// A bare offset such as "30 days" means "30 days ago".
+func (p DateParser) Date(expr string) (time.Time, error) {
+	span, err := p.span(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return span.start, nil
+}
+
+// Until parses a date expression used as an inclusive end bound and
+// returns the last moment of the period it names, so "--until today" or
+// "--until 2025-06-30" covers that whole day. Instants ("now", "6 hours
+// ago", "2025-06-01T09:00") are returned as they are.
+func (p DateParser) Until(expr string) (time.Time, error) {
+	span, err := p.span(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return span.lastInstant(), nil
+}
+
+// Range parses a period expression into an inclusive [start, end] range. It
+// accepts:
+//
+//	A:B             from the start of A to the end of B (2025-01:2025-07)
+//	A..B, A to B    the same, for ends that contain ":" themselves
+//	A               the whole unit A names (2025-03-12, 2025-Q2, last month)
+//	A+N<unit>       N days/weeks/months/quarters/years from the start of A (2025-08-15+6w)
+//	last N <units>  the trailing window ending now (last 90 days)
+//
+// where A and B are anything Date accepts. An instant on its own ("3 weeks
+// ago") covers everything from then until now.
+func (p DateParser) Range(expr string) (time.Time, time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	norm := normalize(expr)
+
+	if m := trailRe.FindStringSubmatch(norm); m != nil {
+		if unit, ok := unitNames[m[2]]; ok {
+			n, _ := strconv.Atoi(m[1])
+			return addUnit(p.Now, -n, unit), p.Now, nil
+		}
+	}
+
+	if m := offsetRe.FindStringSubmatch(norm); m != nil {
+		if span, err := p.span(m[1]); err == nil {
+			n, _ := strconv.Atoi(m[2])
+			if n <= 0 {
+				return time.Time{}, time.Time{}, fmt.Errorf("offset must be positive in %q", expr)
+			}
+			end := addUnit(span.start, n, offsetUnits[m[3]])
+			return span.start, end.Add(-time.Second), nil
+		}
+	}
+
+	for _, sep := range []string{"..", " to ", ":"} {
+		// Timestamps contain ":" too, so accept the first split where both
+		// sides parse.
+		for i := strings.Index(norm, sep); i >= 0; {
+			from, err1 := p.span(norm[:i])
+			to, err2 := p.span(norm[i+len(sep):])
+			if err1 == nil && err2 == nil {
+				end := to.lastInstant()
+				if end.Before(from.start) {
+					return time.Time{}, time.Time{}, fmt.Errorf("range %q ends before it starts", expr)
+				}
+				return from.start, end, nil
+			}
+			next := strings.Index(norm[i+len(sep):], sep)
+			if next < 0 {
+				break
+			}
+			i += len(sep) + next
+		}
+	}
+
+	span, err := p.span(expr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period %q (examples: 2025-01:2025-06, 2025-Q3, 2025-W07, 2025-08-15+6w, last 90 days)", expr)
+	}
+	if span.isInstant() {
+		if span.start.After(p.Now) {
+			return p.Now, span.start, nil
+		}
+		return span.start, p.Now, nil
+	}
+	return span.start, span.lastInstant(), nil
+}
+
+// span resolves a single date expression.
+func (p DateParser) span(expr string) (dateSpan, error) {
+	raw := strings.TrimSpace(expr)
+	loc := p.Now.Location()
+
+	for _, l := range absoluteLayouts {
+		// Upper-case so "2025-06-01t09:00z" reads like its canonical form.
+		t, err := time.ParseInLocation(l.layout, strings.ToUpper(raw), loc)
+		if err != nil {
+			continue
+		}
+		if l.unit == "" {
+			return dateSpan{start: t, end: t}, nil
+		}
+		return dateSpan{start: t, end: addUnit(t, 1, l.unit)}, nil
+	}
+
+	s := normalize(raw)
+	if s == "" {
+		return dateSpan{}, fmt.Errorf("empty date")
+	}
+
+	if m := quarterRe.FindStringSubmatch(s); m != nil {
+		y, _ := strconv.Atoi(m[1])
+		q, _ := strconv.Atoi(m[2])
+		start := time.Date(y, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, loc)
+		return dateSpan{start: start, end: start.AddDate(0, 3, 0)}, nil
+	}
+	if m := isoWeekRe.FindStringSubmatch(s); m != nil {
+		y, _ := strconv.Atoi(m[1])
+		w, _ := strconv.Atoi(m[2])
+		start, err := isoWeekStart(y, w, loc)
+		if err != nil {
+			return dateSpan{}, err
+		}
+		return dateSpan{start: start, end: start.AddDate(0, 0, 7)}, nil
+	}
+
+	today := startOfDay(p.Now)
+	switch s {
+	case "now":
+		return dateSpan{start: p.Now, end: p.Now}, nil
+	case "today":
+		return dayOf(today), nil
+	case "yesterday":
+		return dayOf(today.AddDate(0, 0, -1)), nil
+	case "tomorrow":
+		return dayOf(today.AddDate(0, 0, 1)), nil
+	}
+
+	for _, prefix := range []string{"start of ", "beginning of ", "end of "} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			inner, err := p.anchor(rest)
+			if err != nil {
+				return dateSpan{}, err
+			}
+			t := inner.start
+			if prefix == "end of " {
+				t = inner.lastInstant()
+			}
+			return dateSpan{start: t, end: t}, nil
+		}
+	}
+
+	if span, ok := p.relativeUnit(s); ok {
+		return span, nil
+	}
+
+	return p.offset(s)
+}
+
+// anchor resolves the argument of "start of"/"end of": a bare unit means
+// the current one ("start of month"), anything else is a full expression.
+func (p DateParser) anchor(s string) (dateSpan, error) {
+	if unit, ok := unitNames[s]; ok {
+		return unitSpan(p.Now, unit, 0), nil
+	}
+	return p.span(s)
+}
+
+// relativeUnit handles "[this|last|next] <weekday|unit>".
+func (p DateParser) relativeUnit(s string) (dateSpan, bool) {
+	modifier, name := "", s
+	if fields := strings.Fields(s); len(fields) == 2 {
+		switch fields[0] {
+		case "this", "last", "previous", "next":
+			modifier, name = fields[0], fields[1]
+		}
+	}
+	if strings.Contains(name, " ") {
+		return dateSpan{}, false
+	}
+
+	if wd, ok := weekdays[name]; ok {
+		today := startOfDay(p.Now)
+		back := (int(today.Weekday()) - int(wd) + 7) % 7
+		switch modifier {
+		case "", "this":
+			// The most recent such day, today included.
+			return dayOf(today.AddDate(0, 0, -back)), true
+		case "last", "previous":
+			// The most recent such day strictly before today.
+			if back == 0 {
+				back = 7
+			}
+			return dayOf(today.AddDate(0, 0, -back)), true
+		case "next":
+			fwd := (int(wd) - int(today.Weekday()) + 7) % 7
+			if fwd == 0 {
+				fwd = 7
+			}
+			return dayOf(today.AddDate(0, 0, fwd)), true
+		}
+	}
+
+	unit, ok := unitNames[name]
+	if !ok || modifier == "" {
+		return dateSpan{}, false
+	}
+	switch unit {
+	case "day", "week", "month", "quarter", "year":
+	default:
+		return dateSpan{}, false
+	}
+	n := 0
+	switch modifier {
+	case "last", "previous":
+		n = -1
+	case "next":
+		n = 1
+	}
+	return unitSpan(p.Now, unit, n), true
+}
+
+// offset handles sequences of relative offsets: "3 days ago", "2 weeks 3
+// days ago", "in 2 weeks", "1 week from now", "2 days before yesterday".
+func (p DateParser) offset(s string) (dateSpan, error) {
+	base := p.Now
+	sign := -1
+	body := s
+
+	switch {
+	case strings.HasPrefix(body, "in "):
+		sign, body = 1, strings.TrimPrefix(body, "in ")
+	case strings.HasSuffix(body, " from now"):
+		sign, body = 1, strings.TrimSuffix(body, " from now")
+	case strings.HasSuffix(body, " ago"):
+		body = strings.TrimSuffix(body, " ago")
+	default:
+		for _, rel := range []string{" before ", " after "} {
+			i := strings.Index(body, rel)
+			if i < 0 {
+				continue
+			}
+			ref, err := p.span(body[i+len(rel):])
+			if err != nil {
+				return dateSpan{}, err
+			}
+			base = ref.start
+			if rel == " after " {
+				sign = 1
+			}
+			body = body[:i]
+			break
+		}
+	}
+
+	var tokens []string
+	for _, f := range strings.Fields(body) {
+		// Compact terms like "1y2mo" expand to "1 y 2 mo".
+		if compactRe.MatchString(f) {
+			for _, m := range termRe.FindAllStringSubmatch(f, -1) {
+				tokens = append(tokens, m[1], m[2])
+			}
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	if len(tokens) == 0 {
+		return dateSpan{}, fmt.Errorf("could not parse date: %s", s)
+	}
+	t := base
+	for i := 0; i < len(tokens); {
+		n, unit, used, ok := offsetTerm(tokens[i:])
+		if !ok {
+			return dateSpan{}, fmt.Errorf("could not parse date: %s", s)
+		}
+		t = addUnit(t, sign*n, unit)
+		i += used
+	}
+	return dateSpan{start: t, end: t}, nil
+}
+
+// offsetTerm reads one "<n> <unit>" term ("3 days", "a week") from the
+// front of tokens and reports how many tokens it used.
+func offsetTerm(tokens []string) (n int, unit string, used int, ok bool) {
+	if len(tokens) < 2 {
+		return 0, "", 0, false
+	}
+	switch tokens[0] {
+	case "a", "an", "one":
+		n = 1
+	default:
+		v, err := strconv.Atoi(tokens[0])
+		if err != nil || v < 0 {
+			return 0, "", 0, false
+		}
+		n = v
+	}
+	u, found := unitNames[tokens[1]]
+	if !found {
+		return 0, "", 0, false
+	}
+	return n, u, 2, true
+}
+
+// normalize lowercases and collapses whitespace, dropping the commas and
+// "and" that read naturally in "1 year, 2 months and 3 days ago".
+func normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, ",", " ")
+	fields := strings.Fields(s)
+	out := fields[:0]
+	for _, f := range fields {
+		if f != "and" {
+			out = append(out, f)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// unitSpan returns the calendar unit containing now, shifted by n units.
+func unitSpan(now time.Time, unit string, n int) dateSpan {
+	loc := now.Location()
+	var start time.Time
+	switch unit {
+	case "day":
+		start = startOfDay(now)
+	case "week":
+		start = startOfDay(now)
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	case "month":
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	case "quarter":
+		q := (int(now.Month()) - 1) / 3
+		start = time.Date(now.Year(), time.Month(3*q+1), 1, 0, 0, 0, 0, loc)
+	default: // year
+		start = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, loc)
+	}
+	start = addUnit(start, n, unit)
+	return dateSpan{start: start, end: addUnit(start, 1, unit)}
+}
+
+// isoWeekStart returns the Monday that starts ISO week w of year y.
+func isoWeekStart(y, w int, loc *time.Location) (time.Time, error) {
+	// Jan 4 is always in week 1.
+	jan4 := time.Date(y, 1, 4, 0, 0, 0, 0, loc)
+	week1 := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	start := week1.AddDate(0, 0, 7*(w-1))
+	if wy, ww := start.ISOWeek(); w < 1 || wy != y || ww != w {
+		return time.Time{}, fmt.Errorf("year %d has no ISO week %d", y, w)
+	}
+	return start, nil
+}
+
+// addUnit moves t by n units of the canonical unit name.
+func addUnit(t time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "second":
+		return t.Add(time.Duration(n) * time.Second)
+	case "minute":
+		return t.Add(time.Duration(n) * time.Minute)
+	case "hour":
+		return t.Add(time.Duration(n) * time.Hour)
+	case "day":
+		return t.AddDate(0, 0, n)
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	case "quarter":
+		return t.AddDate(0, 3*n, 0)
+	default: // year
+		return t.AddDate(n, 0, 0)
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func dayOf(t time.Time) dateSpan {
+	return dateSpan{start: t, end: t.AddDate(0, 0, 1)}
+}
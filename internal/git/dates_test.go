@@ -0,0 +1,229 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+// testNow is a Wednesday afternoon; every test resolves against it.
+var testNow = time.Date(2025, 9, 10, 15, 30, 0, 0, time.UTC)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func endOf(t time.Time) time.Time { return t.Add(-time.Second) }
+
+func TestDate(t *testing.T) {
+	p := DateParser{Now: testNow}
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		// Absolute dates and timestamps.
+		{"2025-06-01", day(2025, 6, 1)},
+		{"2025-06", day(2025, 6, 1)},
+		{"2025", day(2025, 1, 1)},
+		{"2025-Q2", day(2025, 4, 1)},
+		{"2025-W23", day(2025, 6, 2)},
+		{"2025-06-01T09:00", time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)},
+		{"2025-06-01 09:15:30", time.Date(2025, 6, 1, 9, 15, 30, 0, time.UTC)},
+		{"2025-06-01T09:00:00+02:00", time.Date(2025, 6, 1, 7, 0, 0, 0, time.UTC)},
+
+		// Named days.
+		{"now", testNow},
+		{"today", day(2025, 9, 10)},
+		{"Yesterday", day(2025, 9, 9)},
+		{"tomorrow", day(2025, 9, 11)},
+		{"monday", day(2025, 9, 8)},
+		{"wednesday", day(2025, 9, 10)},
+		{"last wednesday", day(2025, 9, 3)},
+		{"last monday", day(2025, 9, 8)},
+		{"last fri", day(2025, 9, 5)},
+		{"next monday", day(2025, 9, 15)},
+		{"next wednesday", day(2025, 9, 17)},
+
+		// Calendar units relative to now.
+		{"this week", day(2025, 9, 8)},
+		{"last week", day(2025, 9, 1)},
+		{"next week", day(2025, 9, 15)},
+		{"this month", day(2025, 9, 1)},
+		{"last month", day(2025, 8, 1)},
+		{"last quarter", day(2025, 4, 1)},
+		{"this year", day(2025, 1, 1)},
+		{"previous year", day(2024, 1, 1)},
+
+		// Anchors.
+		{"start of month", day(2025, 9, 1)},
+		{"beginning of quarter", day(2025, 7, 1)},
+		{"start of year", day(2025, 1, 1)},
+		{"start of week", day(2025, 9, 8)},
+		{"end of month", endOf(day(2025, 10, 1))},
+		{"end of last quarter", endOf(day(2025, 7, 1))},
+		{"start of 2024-Q4", day(2024, 10, 1)},
+
+		// Relative offsets.
+		{"3 days ago", testNow.AddDate(0, 0, -3)},
+		{"30 days", testNow.AddDate(0, 0, -30)},
+		{"a week ago", testNow.AddDate(0, 0, -7)},
+		{"2 weeks 3 days ago", testNow.AddDate(0, 0, -17)},
+		{"1 year, 2 months and 3 days ago", testNow.AddDate(-1, -2, -3)},
+		{"1y2mo ago", testNow.AddDate(-1, -2, 0)},
+		{"6 hours ago", testNow.Add(-6 * time.Hour)},
+		{"in 2 weeks", testNow.AddDate(0, 0, 14)},
+		{"3 days from now", testNow.AddDate(0, 0, 3)},
+		{"2 days before 2025-06-01", day(2025, 5, 30)},
+		{"1 week after last monday", day(2025, 9, 15)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			got, err := p.Date(tc.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("Date(%q) = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDateErrors(t *testing.T) {
+	p := DateParser{Now: testNow}
+	for _, expr := range []string{
+		"",
+		"garbage",
+		"last",
+		"next blursday",
+		"3 fortnights ago",
+		"days ago",
+		"start of nothing",
+		"2025-13-01",
+	} {
+		if _, err := p.Date(expr); err == nil {
+			t.Errorf("Date(%q): expected error", expr)
+		}
+	}
+}
+
+func TestDateUsesClockLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*3600)
+	p := DateParser{Now: time.Date(2025, 9, 10, 2, 0, 0, 0, loc)}
+	got, err := p.Date("today")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 9, 10, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("today = %v, want %v", got, want)
+	}
+	got, _ = p.Date("2025-06-01")
+	if want := time.Date(2025, 6, 1, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("2025-06-01 = %v, want %v", got, want)
+	}
+}
+
+func TestUntil(t *testing.T) {
+	p := DateParser{Now: testNow}
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		// A named day or unit runs to its last second.
+		{"today", endOf(day(2025, 9, 11))},
+		{"yesterday", endOf(day(2025, 9, 10))},
+		{"2025-06-30", endOf(day(2025, 7, 1))},
+		{"2025-06", endOf(day(2025, 7, 1))},
+		{"2025-Q2", endOf(day(2025, 7, 1))},
+		{"last week", endOf(day(2025, 9, 8))},
+		{"last friday", endOf(day(2025, 9, 6))},
+
+		// Instants are kept as they are.
+		{"now", testNow},
+		{"6 hours ago", testNow.Add(-6 * time.Hour)},
+		{"2025-06-01T09:00", time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)},
+		{"end of month", endOf(day(2025, 10, 1))},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			got, err := p.Until(tc.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("Until(%q) = %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLogDateKeepsTime(t *testing.T) {
+	at := time.Date(2025, 6, 1, 9, 30, 0, 0, time.FixedZone("", 2*3600))
+	if got, want := LogDate(at), "2025-06-01T09:30:00+02:00"; got != want {
+		t.Errorf("LogDate = %q, want %q", got, want)
+	}
+}
+
+func TestRange(t *testing.T) {
+	p := DateParser{Now: testNow}
+	tests := []struct {
+		expr      string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"2025-01:2025-07", day(2025, 1, 1), endOf(day(2025, 8, 1))},
+		{"2025-03-12:2025-03-25", day(2025, 3, 12), endOf(day(2025, 3, 26))},
+		{"2025-Q1:2025-Q2", day(2025, 1, 1), endOf(day(2025, 7, 1))},
+		{"2025-Q3", day(2025, 7, 1), endOf(day(2025, 10, 1))},
+		{"2025-q4", day(2025, 10, 1), endOf(day(2026, 1, 1))},
+		{"2025-W07", day(2025, 2, 10), endOf(day(2025, 2, 17))},
+		{"2026-W01", day(2025, 12, 29), endOf(day(2026, 1, 5))},
+		{"2025-03-12", day(2025, 3, 12), endOf(day(2025, 3, 13))},
+		{"2024", day(2024, 1, 1), endOf(day(2025, 1, 1))},
+		{"2025-08-15+6w", day(2025, 8, 15), endOf(day(2025, 9, 26))},
+		{"2025-08+2m", day(2025, 8, 1), endOf(day(2025, 10, 1))},
+		{"2025-Q1+1q", day(2025, 1, 1), endOf(day(2025, 4, 1))},
+		{"last 90 days", testNow.AddDate(0, 0, -90), testNow},
+		{"Last 2 Weeks", testNow.AddDate(0, 0, -14), testNow},
+		{"last 1 quarter", testNow.AddDate(0, -3, 0), testNow},
+		{"2025-W02:2025-03", day(2025, 1, 6), endOf(day(2025, 4, 1))},
+		{"3 months ago", testNow.AddDate(0, -3, 0), testNow},
+		{"last month", day(2025, 8, 1), endOf(day(2025, 9, 1))},
+		{"yesterday", day(2025, 9, 9), endOf(day(2025, 9, 10))},
+		{"last monday:today", day(2025, 9, 8), endOf(day(2025, 9, 11))},
+		{"2025-06-01T09:00:2025-06-01T17:30", time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 17, 30, 0, 0, time.UTC)},
+		{"start of quarter..now", day(2025, 7, 1), testNow},
+		{"2025-01 to last month", day(2025, 1, 1), endOf(day(2025, 9, 1))},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			start, end, err := p.Range(tc.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(tc.wantStart) {
+				t.Errorf("start = %v, want %v", start, tc.wantStart)
+			}
+			if !end.Equal(tc.wantEnd) {
+				t.Errorf("end = %v, want %v", end, tc.wantEnd)
+			}
+		})
+	}
+}
+
+func TestRangeErrors(t *testing.T) {
+	p := DateParser{Now: testNow}
+	for _, expr := range []string{
+		"",
+		"garbage",
+		"2025-07:2025-01",
+		"2025-Q5",
+		"2025-W54",
+		"2025-08-15+0w",
+		"2025-13",
+		"today:last week",
+	} {
+		if _, _, err := p.Range(expr); err == nil {
+			t.Errorf("Range(%q): expected error", expr)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// ExplainTotal is the Explain bucket covering the whole period.
+const ExplainTotal = "total"
+
+var (
+	monthBucketRe = regexp.MustCompile(`^\d{4}-\d{2}$`)
+	weekBucketRe  = regexp.MustCompile(`^\d{4}-W\d{2}$`)
+)
+
+// Explanation lists the commits and files behind a total or a monthly or
+// weekly bucket, biggest contribution (lines added plus deleted) first.
+type Explanation struct {
+	Bucket  string
+	Added   int
+	Deleted int
+	Net     int
+	Commits int
+	Top     []CommitChange
+	Files   []FileContribution
+}
+
+// FileContribution is one file's lines summed over a bucket's commits.
+type FileContribution struct {
+	Repo    string
+	Path    string
+	Added   int
+	Deleted int
+	Commits int
+}
+
+// Lines is the file's total change, added plus deleted.
+func (f FileContribution) Lines() int { return f.Added + f.Deleted }
+
+// ValidExplainBucket reports whether bucket names something Explain can
+// break down: ExplainTotal, a month ("2025-03") or an ISO week ("2025-W07").
+func ValidExplainBucket(bucket string) error {
+	if bucket == ExplainTotal || monthBucketRe.MatchString(bucket) || weekBucketRe.MatchString(bucket) {
+		return nil
+	}
+	return fmt.Errorf("unknown bucket %q (use total, YYYY-MM or YYYY-Www)", bucket)
+}
+
+// Explain breaks bucket down into the n commits and n files that contributed
+// most to it.
+func Explain(stats RepoStats, bucket string, n int) (Explanation, error) {
+	if err := ValidExplainBucket(bucket); err != nil {
+		return Explanation{}, err
+	}
+	ex := Explanation{Bucket: bucket}
+	files := make(map[string]*FileContribution)
+	var commits []CommitChange
+	for _, c := range stats.Changes {
+		if !inBucket(c, bucket) {
+			continue
+		}
+		commits = append(commits, c)
+		ex.Added += c.Added
+		ex.Deleted += c.Deleted
+		for _, f := range c.Files {
+			key := c.Repo + "\x00" + f.Path
+			fc, ok := files[key]
+			if !ok {
+				fc = &FileContribution{Repo: c.Repo, Path: f.Path}
+				files[key] = fc
+			}
+			fc.Added += f.Added
+			fc.Deleted += f.Deleted
+			fc.Commits++
+		}
+	}
+	ex.Net = ex.Added - ex.Deleted
+	ex.Commits = len(commits)
+
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].Lines() > commits[j].Lines() })
+	ex.Top = commits[:min(n, len(commits))]
+
+	for _, fc := range files {
+		ex.Files = append(ex.Files, *fc)
+	}
+	sort.Slice(ex.Files, func(i, j int) bool {
+		a, b := ex.Files[i], ex.Files[j]
+		if a.Lines() != b.Lines() {
+			return a.Lines() > b.Lines()
+		}
+		return a.Path < b.Path
+	})
+	ex.Files = ex.Files[:min(n, len(ex.Files))]
+	return ex, nil
+}
+
+// inBucket reports whether c falls in bucket.
+func inBucket(c CommitChange, bucket string) bool {
+	switch {
+	case bucket == ExplainTotal:
+		return true
+	case c.Date.IsZero():
+		return false
+	case weekBucketRe.MatchString(bucket):
+		return WeekKey(c.Date) == bucket
+	}
+	return c.Date.Format("2006-01") == bucket
+}
@@ -0,0 +1,158 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Kinds of outlier commit.
+const (
+	OutlierHuge       = "huge"        // far above the period's other commits
+	OutlierMassDelete = "mass-delete" // almost only deletions, and many
+	OutlierLargeFile  = "large-file"  // one file gained a very large block
+	OutlierGenerated  = "generated"   // mostly lockfiles, vendored or generated code
+)
+
+// Outlier thresholds, in lines.
+const (
+	hugeMinLines       = 500  // a commit is never "huge" below this
+	hugeMinSample      = 8    // commits needed before the fence means anything
+	massDeleteMinLines = 500  // deleted lines for a mass delete
+	largeFileMinLines  = 1000 // lines added to a single file
+	generatedMinLines  = 200  // lines added to generated-looking files
+)
+
+// generatedPatterns match paths that are usually generated or vendored.
+// Patterns without a slash match the base name; "dir/" prefixes match any
+// path under a directory of that name.
+var generatedPatterns = []string{
+	"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "go.sum", "Cargo.lock",
+	"Gemfile.lock", "poetry.lock", "composer.lock", "*.lock",
+	"*.pb.go", "*_pb2.py", "*.pb.*", "*_generated.*", "*.generated.*", "*.gen.*",
+	"*.min.js", "*.min.css", "*.map", "*.snap",
+	"vendor/", "node_modules/", "dist/", "third_party/",
+}
+
+// generatedMarkers appear near the top of generated files.
+var generatedMarkers = []string{"Code generated", "DO NOT EDIT", "@generated", "auto-generated", "autogenerated"}
+
+// Outlier is a commit that distorts the totals, with why it was flagged.
+type Outlier struct {
+	Commit  CommitChange
+	Kinds   []string
+	Reasons []string
+}
+
+// FindOutliers flags the commits of stats that are statistically huge (above
+// the far-out Tukey fence, Q3 + 3×IQR of lines changed), mass deletes, large
+// single-file additions or likely generated dumps. Biggest first.
+func FindOutliers(stats RepoStats) []Outlier {
+	fence := hugeFence(stats.Changes)
+	var out []Outlier
+	for _, c := range stats.Changes {
+		o := Outlier{Commit: c}
+		flag := func(kind, reason string) {
+			o.Kinds = append(o.Kinds, kind)
+			o.Reasons = append(o.Reasons, reason)
+		}
+		if fence > 0 && c.Lines() > fence {
+			flag(OutlierHuge, fmt.Sprintf("%d lines changed, over %d for this period", c.Lines(), fence))
+		}
+		if c.Deleted >= massDeleteMinLines && c.Deleted*10 >= c.Lines()*9 {
+			flag(OutlierMassDelete, fmt.Sprintf("deletes %d lines", c.Deleted))
+		}
+		for _, f := range c.Files {
+			if f.Added >= largeFileMinLines {
+				flag(OutlierLargeFile, fmt.Sprintf("adds %d lines to %s", f.Added, f.Path))
+				break
+			}
+		}
+		if files := generatedFiles(c); len(files) > 0 {
+			flag(OutlierGenerated, "likely generated: "+strings.Join(files, ", "))
+		}
+		if len(o.Kinds) > 0 {
+			out = append(out, o)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Commit.Lines() > out[j].Commit.Lines() })
+	return out
+}
+
+// hugeFence returns the lines-changed size above which a commit is huge, or
+// 0 when there are too few commits to tell.
+func hugeFence(changes []CommitChange) int {
+	if len(changes) < hugeMinSample {
+		return 0
+	}
+	sizes := make([]int, len(changes))
+	for i, c := range changes {
+		sizes[i] = c.Lines()
+	}
+	sort.Ints(sizes)
+	q1, q3 := sizes[len(sizes)/4], sizes[len(sizes)*3/4]
+	return max(hugeMinLines, q3+3*(q3-q1))
+}
+
+// generatedFiles returns the commit's files that look generated, when
+// together they hold most of its added lines.
+func generatedFiles(c CommitChange) []string {
+	var files []string
+	added := 0
+	for _, f := range c.Files {
+		if f.Added == 0 {
+			continue
+		}
+		if looksGenerated(f.Path) || (f.Added >= hugeMinLines && hasGeneratedMarker(c.Repo, c.Hash, f.Path)) {
+			files = append(files, f.Path)
+			added += f.Added
+		}
+	}
+	if added < generatedMinLines || added*2 < c.Added {
+		return nil
+	}
+	return files
+}
+
+// looksGenerated reports whether p matches generatedPatterns.
+func looksGenerated(p string) bool {
+	base := path.Base(p)
+	for _, pattern := range generatedPatterns {
+		if dir, ok := strings.CutSuffix(pattern, "/"); ok {
+			if strings.HasPrefix(p, dir+"/") || strings.Contains(p, "/"+dir+"/") {
+				return true
+			}
+			continue
+		}
+		if m, _ := path.Match(pattern, base); m {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGeneratedMarker reports whether the first lines of the file as of
+// commit carry a generated-code marker.
+func hasGeneratedMarker(repoPath, commit, file string) bool {
+	if repoPath == "" || commit == "" {
+		return false
+	}
+	out, err := exec.Command("git", "-C", repoPath, "show", commit+":"+file).Output()
+	if err != nil {
+		return false
+	}
+	head := strings.SplitN(string(out), "\n", 6)
+	if len(head) > 5 {
+		head = head[:5]
+	}
+	for _, line := range head {
+		for _, marker := range generatedMarkers {
+			if strings.Contains(line, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
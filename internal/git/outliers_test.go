@@ -0,0 +1,123 @@
+package git
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// change builds a commit of the given files.
+func change(hash string, date time.Time, files ...FileChange) CommitChange {
+	c := CommitChange{Hash: hash, Date: date, Subject: "commit " + hash, Files: files}
+	for _, f := range files {
+		c.Added += f.Added
+		c.Deleted += f.Deleted
+	}
+	return c
+}
+
+func TestFindOutliers(t *testing.T) {
+	var stats RepoStats
+	for i := range 20 {
+		stats.Changes = append(stats.Changes, change("small"+string(rune('a'+i)), day(2025, 2, 1+i),
+			FileChange{Path: "main.go", Added: 20 + i, Deleted: 5}))
+	}
+	stats.Changes = append(stats.Changes,
+		change("dump", day(2025, 3, 12), FileChange{Path: "fixtures/data.sql", Added: 30000}),
+		change("purge", day(2025, 3, 13), FileChange{Path: "old.go", Deleted: 900}),
+		change("deps", day(2025, 3, 14), FileChange{Path: "web/package-lock.json", Added: 400}, FileChange{Path: "web/app.js", Added: 10}),
+		change("mixed", day(2025, 3, 15), FileChange{Path: "a.go", Added: 300}, FileChange{Path: "b.go", Added: 300}),
+	)
+
+	got := make(map[string][]string)
+	var order []string
+	for _, o := range FindOutliers(stats) {
+		got[o.Commit.Hash] = o.Kinds
+		order = append(order, o.Commit.Hash)
+		if len(o.Reasons) != len(o.Kinds) {
+			t.Errorf("%s: %d reasons for %d kinds", o.Commit.Hash, len(o.Reasons), len(o.Kinds))
+		}
+	}
+	want := map[string][]string{
+		"dump":  {OutlierHuge, OutlierLargeFile},
+		"purge": {OutlierHuge, OutlierMassDelete},
+		"deps":  {OutlierGenerated},
+		"mixed": {OutlierHuge},
+	}
+	for hash, kinds := range want {
+		if !slices.Equal(got[hash], kinds) {
+			t.Errorf("%s: kinds %v, want %v", hash, got[hash], kinds)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("flagged %v, want only %d commits", order, len(want))
+	}
+	if len(order) > 0 && order[0] != "dump" {
+		t.Errorf("order %v, want the biggest commit first", order)
+	}
+
+	// Too few commits for the fence: only the rule-based kinds remain.
+	few := RepoStats{Changes: stats.Changes[20:]}
+	for _, o := range FindOutliers(few) {
+		if slices.Contains(o.Kinds, OutlierHuge) {
+			t.Errorf("%s flagged huge among %d commits", o.Commit.Hash, len(few.Changes))
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	stats := RepoStats{Changes: []CommitChange{
+		change("a1", day(2025, 3, 3), FileChange{Path: "big.json", Added: 1000}, FileChange{Path: "main.go", Added: 10}),
+		change("a2", day(2025, 3, 20), FileChange{Path: "main.go", Added: 30, Deleted: 20}),
+		change("b1", day(2025, 4, 2), FileChange{Path: "main.go", Added: 5}),
+	}}
+
+	ex, err := Explain(stats, "2025-03", 1)
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	if ex.Commits != 2 || ex.Added != 1040 || ex.Deleted != 20 || ex.Net != 1020 {
+		t.Errorf("March: %d commits +%d -%d net %d, want 2 commits +1040 -20 net 1020", ex.Commits, ex.Added, ex.Deleted, ex.Net)
+	}
+	if len(ex.Top) != 1 || ex.Top[0].Hash != "a1" || len(ex.Files) != 1 || ex.Files[0].Path != "big.json" {
+		t.Errorf("March top: %+v files %+v, want a1 and big.json", ex.Top, ex.Files)
+	}
+
+	total, err := Explain(stats, ExplainTotal, 10)
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	if total.Commits != 3 || len(total.Files) != 2 || total.Files[1].Path != "main.go" || total.Files[1].Commits != 3 {
+		t.Errorf("total: %d commits, files %+v; want 3 commits and main.go in all of them", total.Commits, total.Files)
+	}
+
+	week, _ := Explain(stats, WeekKey(day(2025, 4, 2)), 10)
+	if week.Commits != 1 || week.Top[0].Hash != "b1" {
+		t.Errorf("week: %+v, want b1 only", week)
+	}
+
+	for _, bad := range []string{"", "March", "2025-3", "2025-W7"} {
+		if _, err := Explain(stats, bad, 10); err == nil {
+			t.Errorf("Explain(%q): want an error", bad)
+		}
+	}
+}
+
+func TestExcludedCommit(t *testing.T) {
+	hash := "3f2a9c1e0b5d4a7f8e9d0c1b2a3f4e5d6c7b8a90"
+	for _, tc := range []struct {
+		excludes []string
+		want     bool
+	}{
+		{[]string{"3f2a9c1"}, true},
+		{[]string{"3F2A9C1E"}, true},
+		{[]string{hash}, true},
+		{[]string{"3f2"}, false}, // too short to be safe
+		{[]string{"deadbeef", "4f2a9c1"}, false},
+		{nil, false},
+	} {
+		if got := excludedCommit(hash, tc.excludes); got != tc.want {
+			t.Errorf("excludedCommit(%v) = %v, want %v", tc.excludes, got, tc.want)
+		}
+	}
+}
@@ -1,31 +1,86 @@
 package metrics
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/juangracia/gitrespect/internal/git"
 )
 
-// Baseline holds a personal productivity baseline derived from prior commit history.
+// MinBaselineWeeks is the number of weekly samples a baseline needs before
+// its band is meaningful.
+const MinBaselineWeeks = 4
+
+// Position of the current period relative to a baseline band.
+const (
+	PositionBelow  = "below"
+	PositionWithin = "within"
+	PositionAbove  = "above"
+)
+
+// Band summarizes the weekly net LOC/day samples of a window: the median
+// week and the interquartile range around it. Because it is built from
+// weekly samples, one unusually large commit moves a single week rather
+// than the whole baseline.
+type Band struct {
+	WindowStart  time.Time `json:"window_start"`
+	WindowEnd    time.Time `json:"window_end"`
+	Weeks        int       `json:"weeks"`
+	Median       float64   `json:"median_loc_per_day"`
+	P25          float64   `json:"p25_loc_per_day"`
+	P75          float64   `json:"p75_loc_per_day"`
+	Insufficient bool      `json:"insufficient_history"`
+	Position     string    `json:"position,omitempty"`
+}
+
+// Locate returns where v falls relative to the band's interquartile range.
+func (b Band) Locate(v float64) string {
+	switch {
+	case v < b.P25:
+		return PositionBelow
+	case v > b.P75:
+		return PositionAbove
+	default:
+		return PositionWithin
+	}
+}
+
+// Baseline holds a personal productivity baseline derived from prior commit
+// history: the author's typical week in the window before the period, and
+// optionally the same period one year earlier to account for seasonality.
 type Baseline struct {
 	WindowStart         time.Time `json:"window_start"`
 	WindowEnd           time.Time `json:"window_end"`
 	WorkingDays         int       `json:"working_days"`
-	LOCPerDay           float64   `json:"loc_per_day"`
+	Weeks               int       `json:"weeks"`
+	LOCPerDay           float64   `json:"loc_per_day"` // median week
+	P25                 float64   `json:"p25_loc_per_day"`
+	P75                 float64   `json:"p75_loc_per_day"`
 	InsufficientHistory bool      `json:"insufficient_history"`
 	PeriodLOCPerDay     float64   `json:"period_loc_per_day"`
 	PercentDelta        float64   `json:"percent_delta"`
+	Position            string    `json:"position,omitempty"`
+	Seasonal            *Band     `json:"seasonal,omitempty"`
+}
+
+// ComputeBaseline analyzes the author's history in every repo over the
+// window [periodStart - window, periodStart) and summarizes its weekly net
+// LOC/day. If the actual commit activity span in the window is under 30
+// days, or it covers fewer than MinBaselineWeeks weeks, marks
+// InsufficientHistory.
+func ComputeBaseline(repoPaths []string, author string, periodStart time.Time, window time.Duration, exclude, excludeCommits []string) (Baseline, error) {
+	return ComputeGroupBaseline(repoPaths, []string{author}, periodStart, window, exclude, excludeCommits)
 }
 
-// ComputeBaseline runs git.Analyze on the window [periodStart - window, periodStart)
-// and returns the resulting net LOC/day. If the actual commit activity span in
-// the window is under 30 days, marks InsufficientHistory.
-func ComputeBaseline(repoPath, author string, periodStart time.Time, window time.Duration, exclude []string) (Baseline, error) {
+// ComputeGroupBaseline is ComputeBaseline for the combined output of several
+// authors, e.g. a whole team.
+func ComputeGroupBaseline(repoPaths, authors []string, periodStart time.Time, window time.Duration, exclude, excludeCommits []string) (Baseline, error) {
 	b := Baseline{
 		WindowStart: periodStart.Add(-window),
 		WindowEnd:   periodStart,
 	}
-	stats, err := git.Analyze(repoPath, author, b.WindowStart, b.WindowEnd, exclude)
+	stats, err := analyzeAll(repoPaths, authors, b.WindowStart, b.WindowEnd, exclude, excludeCommits)
 	if err != nil {
 		return b, err
 	}
@@ -38,19 +93,88 @@ func ComputeBaseline(repoPath, author string, periodStart time.Time, window time
 		b.InsufficientHistory = true
 		return b, nil
 	}
+	band := weeklyBand(stats)
 	b.WorkingDays = git.WorkingDays(b.WindowStart, b.WindowEnd)
-	if b.WorkingDays > 0 {
-		b.LOCPerDay = float64(stats.Net) / float64(b.WorkingDays)
-	}
+	b.Weeks = band.Weeks
+	b.LOCPerDay, b.P25, b.P75 = band.Median, band.P25, band.P75
+	b.InsufficientHistory = band.Insufficient
 	return b, nil
 }
 
-// SetPeriod records the current period's LOC/day and computes PercentDelta
-// relative to the baseline. No-op if InsufficientHistory or LOCPerDay is zero.
+// ComputeSeasonal summarizes the authors' combined weekly net LOC/day over
+// the same dates one year before [periodStart, periodEnd).
+func ComputeSeasonal(repoPaths, authors []string, periodStart, periodEnd time.Time, exclude, excludeCommits []string) (Band, error) {
+	since, until := periodStart.AddDate(-1, 0, 0), periodEnd.AddDate(-1, 0, 0)
+	stats, err := analyzeAll(repoPaths, authors, since, until, exclude, excludeCommits)
+	if err != nil {
+		return Band{WindowStart: since, WindowEnd: until, Insufficient: true}, err
+	}
+	band := weeklyBand(stats)
+	if stats.Commits == 0 {
+		band.Insufficient = true
+	}
+	return band, nil
+}
+
+// analyzeAll combines git.Analyze over every repo and author. Repos that
+// fail to analyze are skipped; it errors only when none succeed.
+func analyzeAll(repoPaths, authors []string, since, until time.Time, exclude, excludeCommits []string) (git.RepoStats, error) {
+	var all []git.RepoStats
+	var firstErr error
+	for _, path := range repoPaths {
+		for _, author := range authors {
+			stats, err := git.Analyze(path, author, since, until, exclude, excludeCommits)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			all = append(all, stats)
+		}
+	}
+	if len(all) == 0 {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("no repositories to analyze")
+		}
+		return git.RepoStats{}, firstErr
+	}
+	return git.CombineStats(all), nil
+}
+
+// weeklyBand builds a Band from the weekly samples of stats.
+func weeklyBand(stats git.RepoStats) Band {
+	samples := WeeklySamples(stats)
+	band := Band{
+		WindowStart:  stats.Since,
+		WindowEnd:    stats.Until,
+		Weeks:        len(samples),
+		Insufficient: len(samples) < MinBaselineWeeks,
+	}
+	if len(samples) == 0 {
+		return band
+	}
+	sort.Float64s(samples)
+	band.Median = percentile(samples, 0.5)
+	band.P25 = percentile(samples, 0.25)
+	band.P75 = percentile(samples, 0.75)
+	return band
+}
+
+// SetPeriod records the current period's LOC/day and places it relative to
+// the baseline band and, when present, the seasonal band. PercentDelta is
+// measured against the median week. No-op on a band with insufficient
+// history.
 func (b *Baseline) SetPeriod(periodLOCPerDay float64) {
 	b.PeriodLOCPerDay = periodLOCPerDay
-	if b.InsufficientHistory || b.LOCPerDay == 0 {
+	if b.Seasonal != nil && !b.Seasonal.Insufficient {
+		b.Seasonal.Position = b.Seasonal.Locate(periodLOCPerDay)
+	}
+	if b.InsufficientHistory {
 		return
 	}
-	b.PercentDelta = (periodLOCPerDay - b.LOCPerDay) / b.LOCPerDay * 100
+	b.Position = Band{P25: b.P25, P75: b.P75}.Locate(periodLOCPerDay)
+	if b.LOCPerDay != 0 {
+		b.PercentDelta = (periodLOCPerDay - b.LOCPerDay) / b.LOCPerDay * 100
+	}
 }
@@ -27,7 +27,7 @@ func TestBaseline(t *testing.T) {
 		r.commit("d", author, day.Add(12*time.Hour))
 	}
 
-	b, err := ComputeBaseline(r.path, "test@example.com", periodStart, 90*24*time.Hour, nil)
+	b, err := ComputeBaseline([]string{r.path}, "test@example.com", periodStart, 90*24*time.Hour, nil, nil)
 	if err != nil {
 		t.Fatalf("ComputeBaseline: %v", err)
 	}
@@ -51,9 +51,9 @@ func TestBaselineInsufficient(t *testing.T) {
 	r.writeFile("x.txt", "x\n")
 	r.commit("only", "Test <test@example.com>", time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC))
 
-	b, err := ComputeBaseline(r.path, "test@example.com",
+	b, err := ComputeBaseline([]string{r.path}, "test@example.com",
 		time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
-		90*24*time.Hour, nil)
+		90*24*time.Hour, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -61,3 +61,94 @@ func TestBaselineInsufficient(t *testing.T) {
 		t.Error("expected InsufficientHistory=true for <30 days of activity")
 	}
 }
+
+func TestBaselineBandIgnoresOutlierCommit(t *testing.T) {
+	r := newTestRepo(t)
+	author := "Test <test@example.com>"
+
+	// Ten weeks of steady work (one 50-line commit every Wednesday) plus a
+	// single 5000-line dump in the middle.
+	periodStart := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC) // Monday
+	content := ""
+	for w := 10; w >= 1; w-- {
+		content += baselineLines(50)
+		r.writeFile("a.txt", content)
+		r.commit("steady", author, periodStart.AddDate(0, 0, -7*w+2).Add(12*time.Hour))
+		if w == 5 {
+			r.writeFile("dump.txt", baselineLines(5000))
+			r.commit("dump", author, periodStart.AddDate(0, 0, -7*w+3).Add(12*time.Hour))
+		}
+	}
+
+	b, err := ComputeBaseline([]string{r.path}, "test@example.com", periodStart, 70*24*time.Hour, nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeBaseline: %v", err)
+	}
+	if b.InsufficientHistory {
+		t.Fatal("should not be flagged insufficient")
+	}
+	if b.Weeks != 10 {
+		t.Errorf("Weeks=%d, want 10", b.Weeks)
+	}
+	if b.LOCPerDay != 10 {
+		t.Errorf("median LOCPerDay=%v, want 10 (50 lines / 5 days)", b.LOCPerDay)
+	}
+	if b.P25 != 10 || b.P75 != 10 {
+		t.Errorf("band=[%v, %v], want [10, 10]", b.P25, b.P75)
+	}
+
+	b.SetPeriod(10)
+	if b.Position != PositionWithin {
+		t.Errorf("Position=%q, want within", b.Position)
+	}
+	b.SetPeriod(25)
+	if b.Position != PositionAbove {
+		t.Errorf("Position=%q, want above", b.Position)
+	}
+	b.SetPeriod(2)
+	if b.Position != PositionBelow {
+		t.Errorf("Position=%q, want below", b.Position)
+	}
+}
+
+func TestComputeSeasonal(t *testing.T) {
+	r := newTestRepo(t)
+	author := "Test <test@example.com>"
+
+	// Daily commits across March 2025; the period is March 2026.
+	content := ""
+	for d := 1; d <= 31; d++ {
+		day := time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC)
+		if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			continue
+		}
+		content += baselineLines(20)
+		r.writeFile("a.txt", content)
+		r.commit("work", author, day)
+	}
+
+	band, err := ComputeSeasonal([]string{r.path}, []string{"test@example.com"},
+		time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeSeasonal: %v", err)
+	}
+	if band.Insufficient {
+		t.Fatal("a month of history should be enough")
+	}
+	if !band.WindowStart.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("WindowStart=%v, want 2025-03-01", band.WindowStart)
+	}
+	if band.Median != 20 {
+		t.Errorf("Median=%v, want 20", band.Median)
+	}
+
+	b := Baseline{InsufficientHistory: true, Seasonal: &band}
+	b.SetPeriod(40)
+	if band.Position != PositionAbove {
+		t.Errorf("seasonal Position=%q, want above", band.Position)
+	}
+	if b.Position != "" {
+		t.Errorf("Position=%q, want empty for insufficient baseline", b.Position)
+	}
+}
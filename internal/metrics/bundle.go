@@ -1,11 +1,26 @@
 package metrics
 
+import (
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+)
+
 type Bundle struct {
 	Selection       Selection
+	Since           time.Time // window the opt-in metrics cover
+	Until           time.Time
 	Baseline        *Baseline
 	CommitSize      *CommitSizeDistribution
 	Cadence         *Cadence
 	LeadTime        *LeadTime
+	Deploy          *DeployLeadTime
 	Churn           *Churn
+	Reverts         *Reverts
+	Normal          *Bundle          // the same opt-in metrics over the baseline window
+	Repos           []RepoBundle     // per-repo values, set with --per-repo
+	Diagnostics     []Diagnostic     // how each metric went, per repo
+	Outliers        []git.Outlier    // commits distorting the totals
+	Explain         *git.Explanation // set with --explain
 	LegacyBenchmark bool
 }
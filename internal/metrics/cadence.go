@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
 )
 
 // Cadence measures how frequently an author commits to the main branch.
@@ -14,41 +16,45 @@ type Cadence struct {
 	MedianDaysBetween float64 `json:"median_days_between"`
 	Samples           int     `json:"samples"`
 	MainBranch        string  `json:"main_branch"`
+
+	intervals []float64 // raw samples, kept so repos can be pooled
 }
 
 // ComputeCadence returns the median number of days between the author's
-// commits on the main branch within [since, until].
-func ComputeCadence(repoPath, author string, since, until time.Time) (Cadence, error) {
+// commits on the main branches within [since, until]. branches are the
+// --main-branch patterns; when empty the repo's configured or detected main
+// branch is used (see mainBranches). Commits git.Analyze leaves out
+// (excludeCommits and the repo's git.ExcludeCommitConfigKey) are skipped.
+func ComputeCadence(repoPath, author string, since, until time.Time, branches, excludeCommits []string) (Cadence, error) {
 	var c Cadence
-	branch := detectMainBranch(repoPath)
-	if branch == "" {
+	trunk := mainBranches(repoPath, branches)
+	if len(trunk) == 0 {
 		return c, nil
 	}
-	c.MainBranch = branch
+	c.MainBranch = strings.Join(trunk, ", ")
 
-	// git --since is exclusive (commits on that calendar day are excluded), so
-	// subtract one day to include commits that fall on the since date itself.
-	sinceStr := since.AddDate(0, 0, -1).Format("2006-01-02")
 	args := []string{
-		"-C", repoPath, "log", branch,
+		"-C", repoPath, "log",
 		"--author=" + author,
-		"--since=" + sinceStr,
-		"--until=" + until.Format("2006-01-02"),
+		"--since=" + git.LogDate(since),
+		"--until=" + git.LogDate(until),
 		"--no-merges",
-		"--format=%ct",
+		"--format=%H %ct",
 	}
+	args = append(append(args, trunk...), "--")
 	out, err := exec.Command("git", args...).Output()
 	if err != nil {
 		return c, fmt.Errorf("git log: %w", err)
 	}
 
+	excluded := git.ExcludedCommits(repoPath, excludeCommits)
 	var timestamps []int64
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
+		hash, ct, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || excluded(hash) {
 			continue
 		}
-		ts, err := strconv.ParseInt(line, 10, 64)
+		ts, err := strconv.ParseInt(ct, 10, 64)
 		if err != nil {
 			continue
 		}
@@ -67,6 +73,7 @@ func ComputeCadence(repoPath, author string, since, until time.Time) (Cadence, e
 		intervals = append(intervals, delta)
 	}
 
+	c.intervals = intervals
 	c.Samples = len(intervals)
 	c.MedianDaysBetween = median(intervals)
 	return c, nil
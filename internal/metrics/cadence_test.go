@@ -20,7 +20,7 @@ func TestCadence(t *testing.T) {
 	since := base.Add(-1 * time.Hour)
 	until := base.Add(10 * 24 * time.Hour)
 
-	c, err := ComputeCadence(r.path, "test@example.com", since, until)
+	c, err := ComputeCadence(r.path, "test@example.com", since, until, nil, nil)
 	if err != nil {
 		t.Fatalf("ComputeCadence: %v", err)
 	}
@@ -42,7 +42,7 @@ func TestCadenceInsufficient(t *testing.T) {
 
 	c, err := ComputeCadence(r.path, "test@example.com",
 		time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
-		time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+		time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -52,3 +52,77 @@ func TestCadenceInsufficient(t *testing.T) {
 }
 
 func line(i int) string { return string(rune('a'+i)) + "\n" }
+
+func TestCadenceMainBranches(t *testing.T) {
+	r := newTestRepo(t)
+	author := "Test <test@example.com>"
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// develop: commits on days 0 and 2; release/1.0 branches off it and adds
+	// day 3; main is renamed away so nothing is auto-detected.
+	r.writeFile("f.txt", line(0))
+	r.commit("c0", author, base)
+	r.writeFile("f.txt", line(1))
+	r.commit("c1", author, base.Add(48*time.Hour))
+	run(t, r.path, "git", "branch", "-m", "main", "develop")
+	run(t, r.path, "git", "checkout", "-q", "-b", "release/1.0")
+	r.writeFile("f.txt", line(2))
+	r.commit("c2", author, base.Add(72*time.Hour))
+
+	since, until := base.Add(-time.Hour), base.Add(10*24*time.Hour)
+
+	c, err := ComputeCadence(r.path, "test@example.com", since, until, nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeCadence: %v", err)
+	}
+	if c.MainBranch != "develop" || c.Samples != 1 {
+		t.Errorf("detected: MainBranch=%q Samples=%d, want develop with 1 sample", c.MainBranch, c.Samples)
+	}
+
+	c, err = ComputeCadence(r.path, "test@example.com", since, until, []string{"develop", "release/*"}, nil)
+	if err != nil {
+		t.Fatalf("ComputeCadence: %v", err)
+	}
+	// The union of both branches has three commits, each counted once.
+	if c.MainBranch != "develop, release/1.0" || c.Samples != 2 {
+		t.Errorf("patterns: MainBranch=%q Samples=%d, want develop, release/1.0 with 2 samples", c.MainBranch, c.Samples)
+	}
+
+	run(t, r.path, "git", "config", "--add", MainBranchConfigKey, "release/*")
+	c, err = ComputeCadence(r.path, "test@example.com", since, until, nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeCadence: %v", err)
+	}
+	if c.MainBranch != "release/1.0" {
+		t.Errorf("config: MainBranch=%q, want release/1.0", c.MainBranch)
+	}
+
+	c, err = ComputeCadence(r.path, "test@example.com", since, until, []string{"nope"}, nil)
+	if err != nil {
+		t.Fatalf("ComputeCadence: %v", err)
+	}
+	if c.MainBranch != "" {
+		t.Errorf("unmatched pattern: MainBranch=%q, want empty", c.MainBranch)
+	}
+}
+
+func TestCadenceExcludeCommits(t *testing.T) {
+	r := newTestRepo(t)
+	author := "Test <test@example.com>"
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Commits on days 0, 2 and 4, with a bulk reformat on day 1 left out.
+	for i, day := range []int{0, 1, 2, 4} {
+		r.writeFile("f.txt", line(i))
+		r.commit("c", author, base.Add(time.Duration(day*24)*time.Hour))
+	}
+	reformat := revParse(t, r, "HEAD~2")
+
+	c, err := ComputeCadence(r.path, "test@example.com", base.Add(-time.Hour), base.Add(10*24*time.Hour), nil, []string{reformat})
+	if err != nil {
+		t.Fatalf("ComputeCadence: %v", err)
+	}
+	if c.Samples != 2 || math.Abs(c.MedianDaysBetween-2.0) > 0.01 {
+		t.Errorf("Samples=%d MedianDays=%v, want 2 and 2.0", c.Samples, c.MedianDaysBetween)
+	}
+}
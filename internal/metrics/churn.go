@@ -2,6 +2,8 @@ package metrics
 
 import (
 	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
 )
 
 // Churn holds code churn metrics for an author over a time window.
@@ -13,16 +15,19 @@ type Churn struct {
 }
 
 // ComputeChurn calculates code churn for an author by comparing lines added
-// in a prior window against lines deleted in the current period.
-func ComputeChurn(repoPath, author string, since, until time.Time, window time.Duration, exclude []string) (Churn, error) {
+// in a prior window against lines deleted in the current period. Commits
+// git.Analyze leaves out (excludeCommits and the repo's
+// git.ExcludeCommitConfigKey) count on neither side.
+func ComputeChurn(repoPath, author string, since, until time.Time, window time.Duration, exclude, excludeCommits []string) (Churn, error) {
 	priorStart := since.Add(-window)
+	excluded := git.ExcludedCommits(repoPath, excludeCommits)
 
-	added, _, err := sumNumstat(repoPath, author, priorStart, since, exclude)
+	added, _, err := sumNumstat(repoPath, author, priorStart, since, exclude, excluded)
 	if err != nil {
 		return Churn{}, err
 	}
 
-	_, deleted, err := sumNumstat(repoPath, author, since, until, exclude)
+	_, deleted, err := sumNumstat(repoPath, author, since, until, exclude, excluded)
 	if err != nil {
 		return Churn{}, err
 	}
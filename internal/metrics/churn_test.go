@@ -33,7 +33,7 @@ func TestChurnBasic(t *testing.T) {
 	until := base.Add(15 * 24 * time.Hour)
 	window := 30 * 24 * time.Hour
 
-	c, err := ComputeChurn(repo.path, "dev@example.com", since, until, window, nil)
+	c, err := ComputeChurn(repo.path, "dev@example.com", since, until, window, nil, nil)
 	if err != nil {
 		t.Fatalf("ComputeChurn error: %v", err)
 	}
@@ -66,7 +66,7 @@ func TestChurnNoActivity(t *testing.T) {
 	until := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
 	window := 30 * 24 * time.Hour
 
-	c, err := ComputeChurn(repo.path, "dev@example.com", since, until, window, nil)
+	c, err := ComputeChurn(repo.path, "dev@example.com", since, until, window, nil, nil)
 	if err != nil {
 		t.Fatalf("ComputeChurn error: %v", err)
 	}
@@ -78,3 +78,30 @@ func TestChurnNoActivity(t *testing.T) {
 		t.Errorf("Ratio = %.4f, want 0", c.Ratio)
 	}
 }
+
+func TestChurnExcludeCommits(t *testing.T) {
+	repo := newTestRepo(t)
+	author := "Dev User <dev@example.com>"
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo.writeFile("a.txt", churnLines(100))
+	repo.commit("initial commit", author, base)
+	repo.writeFile("vendor.txt", churnLines(400))
+	repo.commit("vendor a library", author, base.Add(24*time.Hour))
+	vendored := revParse(t, repo, "HEAD")
+	repo.writeFile("a.txt", churnLines(70))
+	repo.commit("shrink file", author, base.Add(10*24*time.Hour))
+	repo.writeFile("vendor.txt", "")
+	repo.commit("drop the vendored library", author, base.Add(11*24*time.Hour))
+	dropped := revParse(t, repo, "HEAD")
+
+	since := base.Add(5 * 24 * time.Hour)
+	until := base.Add(15 * 24 * time.Hour)
+	c, err := ComputeChurn(repo.path, "dev@example.com", since, until, 30*24*time.Hour, nil, []string{vendored[:8], dropped})
+	if err != nil {
+		t.Fatalf("ComputeChurn error: %v", err)
+	}
+	if c.AddedLines != 100 || c.ChurnedLines != 30 {
+		t.Errorf("AddedLines=%d ChurnedLines=%d, want 100 and 30", c.AddedLines, c.ChurnedLines)
+	}
+}
@@ -3,45 +3,211 @@ package metrics
 import (
 	"fmt"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
 )
 
-// SizeBucket categorizes a commit by total lines changed.
-type SizeBucket int
+// SizeBucketsConfigKey is the git config key a repository can set to choose
+// its commit size bucket edges, e.g.
+// `git config gitrespect.sizeBuckets 10,50,200,1000`.
+const SizeBucketsConfigKey = "gitrespect.sizeBuckets"
 
-const (
-	BucketMicro  SizeBucket = iota // <10 LOC total change
-	BucketSmall                    // 10-99
-	BucketMedium                   // 100-499
-	BucketLarge                    // 500+
-)
+// DefaultSizeEdges are the bucket edges used when none are configured:
+// micro (<10 lines changed), small (10-99), medium (100-499), large (500+).
+var DefaultSizeEdges = []int{10, 100, 500}
+
+// LargestCommits is how many of the biggest commits a distribution lists.
+const LargestCommits = 5
+
+// SizedCommit is one commit with its line counts.
+type SizedCommit struct {
+	Hash    string `json:"sha"`
+	Subject string `json:"subject"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+}
+
+// Lines is the commit's total change, added plus deleted.
+func (c SizedCommit) Lines() int { return c.Added + c.Deleted }
+
+// SizeHistogram counts commits per bucket of one measure of their size,
+// with its percentiles.
+type SizeHistogram struct {
+	Counts []int `json:"counts"` // one per bucket, see CommitSizeDistribution.Edges
+	P50    int   `json:"p50"`
+	P90    int   `json:"p90"`
+	P99    int   `json:"p99"`
+}
 
-// CommitSizeDistribution holds counts of commits per size bucket.
+// CommitSizeDistribution holds counts of commits per size bucket. Bucket i
+// holds commits with Edges[i-1] <= lines < Edges[i]; the last bucket is
+// open-ended. Counts and the percentiles are by total lines changed; Added
+// and Deleted bucket the same commits by each side alone.
 type CommitSizeDistribution struct {
-	Counts [4]int `json:"counts"`
-	Total  int    `json:"total"`
+	Edges   []int         `json:"edges"`
+	Counts  []int         `json:"counts"`
+	Total   int           `json:"total"`
+	P50     int           `json:"p50"`
+	P90     int           `json:"p90"`
+	P99     int           `json:"p99"`
+	Added   SizeHistogram `json:"added"`
+	Deleted SizeHistogram `json:"deleted"`
+	Largest []SizedCommit `json:"largest"` // the LargestCommits biggest, biggest first
+
+	commits []SizedCommit // every commit, kept so repos can be pooled
 }
 
-// Percent returns the percentage of commits in bucket b. Returns 0 if Total is 0.
-func (d CommitSizeDistribution) Percent(b SizeBucket) float64 {
-	if d.Total == 0 {
+// Percent returns the percentage of commits in bucket i. Returns 0 if Total is 0.
+func (d CommitSizeDistribution) Percent(i int) float64 {
+	if d.Total == 0 || i >= len(d.Counts) {
 		return 0
 	}
-	return float64(d.Counts[b]) * 100 / float64(d.Total)
+	return float64(d.Counts[i]) * 100 / float64(d.Total)
+}
+
+// BucketLabel describes bucket i's range, e.g. "<10", "10-99" or "500+".
+func (d CommitSizeDistribution) BucketLabel(i int) string {
+	switch {
+	case len(d.Edges) == 0:
+		return "all"
+	case i == 0:
+		return fmt.Sprintf("<%d", d.Edges[0])
+	case i == len(d.Edges):
+		return fmt.Sprintf("%d+", d.Edges[i-1])
+	}
+	return fmt.Sprintf("%d-%d", d.Edges[i-1], d.Edges[i]-1)
+}
+
+// bucketOf returns the index of the bucket holding a commit of n lines.
+func bucketOf(edges []int, n int) int {
+	return sort.Search(len(edges), func(i int) bool { return n < edges[i] })
+}
+
+// newCommitSize buckets commits by edges and derives the percentiles and
+// the largest commits.
+func newCommitSize(edges []int, commits []SizedCommit) CommitSizeDistribution {
+	d := CommitSizeDistribution{
+		Edges:   edges,
+		Total:   len(commits),
+		commits: commits,
+	}
+	var total, added, deleted []int
+	for _, c := range commits {
+		total = append(total, c.Lines())
+		added = append(added, c.Added)
+		deleted = append(deleted, c.Deleted)
+	}
+	d.Counts, d.P50, d.P90, d.P99 = histogram(edges, total)
+	d.Added.Counts, d.Added.P50, d.Added.P90, d.Added.P99 = histogram(edges, added)
+	d.Deleted.Counts, d.Deleted.P50, d.Deleted.P90, d.Deleted.P99 = histogram(edges, deleted)
+
+	largest := append([]SizedCommit(nil), commits...)
+	sort.SliceStable(largest, func(i, j int) bool { return largest[i].Lines() > largest[j].Lines() })
+	if len(largest) > LargestCommits {
+		largest = largest[:LargestCommits]
+	}
+	d.Largest = largest
+	return d
+}
+
+// histogram counts sizes per bucket and returns their p50, p90 and p99.
+func histogram(edges, sizes []int) (counts []int, p50, p90, p99 int) {
+	counts = make([]int, len(edges)+1)
+	for _, n := range sizes {
+		counts[bucketOf(edges, n)]++
+	}
+	sorted := append([]int(nil), sizes...)
+	sort.Ints(sorted)
+	return counts, nearestRank(sorted, 50), nearestRank(sorted, 90), nearestRank(sorted, 99)
+}
+
+// nearestRank returns the nearest-rank p-th percentile of sorted, always an
+// actual size; 0 when empty.
+func nearestRank(sorted []int, p int) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// parseSizeEdges parses comma-separated bucket edges such as "10,100,500".
+// Edges must be positive and strictly increasing.
+func parseSizeEdges(raw string) ([]int, error) {
+	var edges []int
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size bucket %q: %w", f, err)
+		}
+		edges = append(edges, n)
+	}
+	if err := CheckSizeEdges(edges); err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
+
+// CheckSizeEdges reports whether edges are positive and strictly increasing.
+func CheckSizeEdges(edges []int) error {
+	if len(edges) == 0 {
+		return fmt.Errorf("no size buckets given")
+	}
+	for i, e := range edges {
+		if e <= 0 {
+			return fmt.Errorf("size bucket edge %d must be positive", e)
+		}
+		if i > 0 && e <= edges[i-1] {
+			return fmt.Errorf("size bucket edges must increase: %d after %d", e, edges[i-1])
+		}
+	}
+	return nil
+}
+
+// sizeEdges resolves the bucket edges of a repo: the given edges
+// (--size-buckets) if any, else the repo's SizeBucketsConfigKey, else
+// DefaultSizeEdges.
+func sizeEdges(repoPath string, edges []int) ([]int, error) {
+	if len(edges) > 0 {
+		return edges, CheckSizeEdges(edges)
+	}
+	out, err := exec.Command("git", "-C", repoPath, "config", "--get", SizeBucketsConfigKey).Output()
+	if err != nil {
+		return DefaultSizeEdges, nil
+	}
+	parsed, err := parseSizeEdges(string(out))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", SizeBucketsConfigKey, err)
+	}
+	return parsed, nil
 }
 
 // ComputeCommitSize analyzes the size distribution of commits in repoPath for the
-// given author and date window. Binary files and files matching exclude patterns
-// are ignored.
-func ComputeCommitSize(repoPath, author string, since, until time.Time, exclude []string) (CommitSizeDistribution, error) {
+// given author and date window, bucketed by edges (see sizeEdges for the
+// fallbacks when empty). Binary files and files matching exclude patterns
+// are ignored, and so are the commits git.Analyze leaves out
+// (excludeCommits and the repo's git.ExcludeCommitConfigKey).
+func ComputeCommitSize(repoPath, author string, since, until time.Time, exclude, excludeCommits []string, edges []int) (CommitSizeDistribution, error) {
+	edges, err := sizeEdges(repoPath, edges)
+	if err != nil {
+		return CommitSizeDistribution{}, err
+	}
 	args := []string{
 		"-C", repoPath, "log",
 		"--author=" + author,
-		"--since=" + since.Format("2006-01-02"),
-		"--until=" + until.Format("2006-01-02"),
-		"--pretty=format:COMMIT %H",
+		"--since=" + git.LogDate(since),
+		"--until=" + git.LogDate(until),
+		"--pretty=format:COMMIT %H%x1f%s",
 		"--numstat",
 	}
 	out, err := exec.Command("git", args...).Output()
@@ -49,40 +215,22 @@ func ComputeCommitSize(repoPath, author string, since, until time.Time, exclude
 		return CommitSizeDistribution{}, fmt.Errorf("git log: %w", err)
 	}
 
-	var dist CommitSizeDistribution
-	inCommit := false
-	commitTotal := 0
-
-	flush := func() {
-		if !inCommit {
-			return
-		}
-		var b SizeBucket
-		switch {
-		case commitTotal < 10:
-			b = BucketMicro
-		case commitTotal < 100:
-			b = BucketSmall
-		case commitTotal < 500:
-			b = BucketMedium
-		default:
-			b = BucketLarge
-		}
-		dist.Counts[b]++
-		dist.Total++
-		inCommit = false
-		commitTotal = 0
-	}
-
+	excluded := git.ExcludedCommits(repoPath, excludeCommits)
+	var commits []SizedCommit
+	var cur *SizedCommit
 	for _, line := range strings.Split(string(out), "\n") {
-		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "COMMIT ") {
-			flush()
-			inCommit = true
-			commitTotal = 0
+			hash, subject, _ := strings.Cut(strings.TrimPrefix(line, "COMMIT "), "\x1f")
+			if excluded(hash) {
+				cur = nil
+				continue
+			}
+			commits = append(commits, SizedCommit{Hash: hash, Subject: subject})
+			cur = &commits[len(commits)-1]
 			continue
 		}
-		if !inCommit || line == "" {
+		line = strings.TrimSpace(line)
+		if cur == nil || line == "" {
 			continue
 		}
 		fields := strings.Fields(line)
@@ -102,9 +250,9 @@ func ComputeCommitSize(repoPath, author string, since, until time.Time, exclude
 		if err1 != nil || err2 != nil {
 			continue
 		}
-		commitTotal += a + d
+		cur.Added += a
+		cur.Deleted += d
 	}
-	flush()
 
-	return dist, nil
+	return newCommitSize(edges, commits), nil
 }
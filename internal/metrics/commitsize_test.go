@@ -1,9 +1,12 @@
 package metrics
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
 )
 
 func lines(n int) string {
@@ -40,7 +43,7 @@ func TestCommitSize(t *testing.T) {
 		since := base.Add(-time.Hour)
 		until := base.Add(96 * time.Hour)
 
-		dist, err := ComputeCommitSize(repo.path, author, since, until, nil)
+		dist, err := ComputeCommitSize(repo.path, author, since, until, nil, nil, nil)
 		if err != nil {
 			t.Fatalf("ComputeCommitSize: %v", err)
 		}
@@ -48,19 +51,65 @@ func TestCommitSize(t *testing.T) {
 		if dist.Total != 4 {
 			t.Errorf("Total = %d, want 4", dist.Total)
 		}
-		for _, tc := range []struct {
-			bucket SizeBucket
-			name   string
-		}{
-			{BucketMicro, "Micro"},
-			{BucketSmall, "Small"},
-			{BucketMedium, "Medium"},
-			{BucketLarge, "Large"},
-		} {
-			if dist.Counts[tc.bucket] != 1 {
-				t.Errorf("Counts[%s] = %d, want 1", tc.name, dist.Counts[tc.bucket])
+		for i, c := range dist.Counts {
+			if c != 1 {
+				t.Errorf("Counts[%s] = %d, want 1", dist.BucketLabel(i), c)
 			}
 		}
+		if len(dist.Largest) != 4 || dist.Largest[0].Subject != "large commit" || dist.Largest[0].Added != 600 {
+			t.Errorf("Largest = %+v, want the large commit first", dist.Largest)
+		}
+
+		// Excluded commits are left out, by flag or by config.
+		large := revParse(t, repo, "HEAD")
+		dist, err = ComputeCommitSize(repo.path, author, since, until, nil, []string{large[:7]}, nil)
+		if err != nil || dist.Total != 3 || dist.Counts[3] != 0 {
+			t.Errorf("excluding the large commit: Total=%d Counts=%v err=%v, want 3 commits and no large one", dist.Total, dist.Counts, err)
+		}
+		run(t, repo.path, "git", "config", "--add", git.ExcludeCommitConfigKey, revParse(t, repo, "HEAD~1")[:8])
+		dist, err = ComputeCommitSize(repo.path, author, since, until, nil, []string{large[:7]}, nil)
+		if err != nil || dist.Total != 2 || dist.Counts[2] != 0 {
+			t.Errorf("also excluding the medium commit by config: Total=%d Counts=%v err=%v, want 2 commits", dist.Total, dist.Counts, err)
+		}
+	})
+
+	t.Run("configured edges, percentiles and deletions", func(t *testing.T) {
+		repo := newTestRepo(t)
+		for i, n := range []int{5, 20, 40, 80} {
+			repo.writeFile("f.txt", lines(n))
+			repo.commit("grow", author, base.Add(time.Duration(i)*time.Hour))
+		}
+		// Shrinking f.txt from 80 to 10 lines deletes 70.
+		repo.writeFile("f.txt", lines(10))
+		repo.commit("shrink", author, base.Add(5*time.Hour))
+		run(t, repo.path, "git", "config", SizeBucketsConfigKey, "16,64")
+
+		dist, err := ComputeCommitSize(repo.path, author, base.Add(-time.Hour), base.Add(24*time.Hour), nil, nil, nil)
+		if err != nil {
+			t.Fatalf("ComputeCommitSize: %v", err)
+		}
+		// Lines changed: 5, 15, 20, 40, 70.
+		if !slices.Equal(dist.Edges, []int{16, 64}) || !slices.Equal(dist.Counts, []int{2, 2, 1}) {
+			t.Errorf("Edges=%v Counts=%v, want [16 64] and [2 2 1]", dist.Edges, dist.Counts)
+		}
+		if dist.P50 != 20 || dist.P90 != 70 || dist.P99 != 70 {
+			t.Errorf("P50=%d P90=%d P99=%d, want 20, 70 and 70", dist.P50, dist.P90, dist.P99)
+		}
+		if !slices.Equal(dist.Deleted.Counts, []int{4, 0, 1}) || dist.Deleted.P90 != 70 || dist.Added.P50 != 15 {
+			t.Errorf("Deleted=%+v Added=%+v, want one 70-line deletion and added p50 15", dist.Deleted, dist.Added)
+		}
+		if got := dist.BucketLabel(1); got != "16-63" {
+			t.Errorf("BucketLabel(1) = %q, want 16-63", got)
+		}
+
+		// Explicit edges win over the config.
+		dist, err = ComputeCommitSize(repo.path, author, base.Add(-time.Hour), base.Add(24*time.Hour), nil, nil, []int{50})
+		if err != nil {
+			t.Fatalf("ComputeCommitSize: %v", err)
+		}
+		if !slices.Equal(dist.Counts, []int{4, 1}) {
+			t.Errorf("Counts=%v with edges [50], want [4 1]", dist.Counts)
+		}
 	})
 
 	t.Run("empty window returns zero distribution", func(t *testing.T) {
@@ -73,7 +122,7 @@ func TestCommitSize(t *testing.T) {
 		since := base.Add(-48 * time.Hour)
 		until := base.Add(-24 * time.Hour)
 
-		dist, err := ComputeCommitSize(repo.path, author, since, until, nil)
+		dist, err := ComputeCommitSize(repo.path, author, since, until, nil, nil, nil)
 		if err != nil {
 			t.Fatalf("ComputeCommitSize: %v", err)
 		}
@@ -87,3 +136,14 @@ func TestCommitSize(t *testing.T) {
 		}
 	})
 }
+
+func TestSizeEdges(t *testing.T) {
+	if got, err := parseSizeEdges("10, 50,200"); err != nil || !slices.Equal(got, []int{10, 50, 200}) {
+		t.Errorf("parseSizeEdges = %v, %v; want [10 50 200]", got, err)
+	}
+	for _, raw := range []string{"", "10,x", "0,10", "100,50", "10,10"} {
+		if _, err := parseSizeEdges(raw); err == nil {
+			t.Errorf("parseSizeEdges(%q): want an error", raw)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeploySource says where deploys are recorded: tags matching TagPattern
+// (e.g. v* or deploy-*), or a local log file with one deploy per line. The
+// zero value means deploys are not tracked.
+type DeploySource struct {
+	TagPattern string
+	LogFile    string
+}
+
+// IsZero reports whether no deploy source is configured.
+func (s DeploySource) IsZero() bool {
+	return s.TagPattern == "" && s.LogFile == ""
+}
+
+func (s DeploySource) String() string {
+	if s.LogFile != "" {
+		return s.LogFile
+	}
+	return "tags " + s.TagPattern
+}
+
+// DeployLeadTime measures the path to production: how long the author's
+// commits took from being committed to their first deploy, and how often
+// deploys carrying their commits went out.
+type DeployLeadTime struct {
+	Source     string  `json:"source"`
+	MedianDays float64 `json:"median_days"`
+	Samples    int     `json:"samples"` // commits deployed in the period
+	Deploys    int     `json:"deploys"` // deploys in the period shipping at least one of them
+	Weeks      float64 `json:"weeks"`
+	PerWeek    float64 `json:"deploys_per_week"`
+
+	days []float64 // raw samples, kept so repos can be pooled
+}
+
+// deploy is one deploy marker resolved to a commit.
+type deploy struct {
+	at   time.Time
+	rev  string
+	name string // tag name, or the commit as written in the log
+}
+
+// ComputeDeployLeadTime finds the deploys in [since, until] and, for each,
+// the authors' commits it shipped for the first time: those reachable from
+// the deploy but from no earlier one. Lead time runs from the commit to that
+// deploy. The repo's first deploy ever is skipped, since it ships its whole
+// history. Deploy log entries naming commits this repo doesn't have are
+// ignored, so one log can cover several repos.
+func ComputeDeployLeadTime(repoPath string, authors []string, since, until time.Time, src DeploySource) (DeployLeadTime, error) {
+	deploys, err := deployMarkers(repoPath, src)
+	if err != nil {
+		return DeployLeadTime{Source: src.String()}, err
+	}
+	return deployLeadTime(repoPath, authors, since, until, src, deploys)
+}
+
+func deployLeadTime(repoPath string, authors []string, since, until time.Time, src DeploySource, deploys []deploy) (DeployLeadTime, error) {
+	out := DeployLeadTime{Source: src.String(), Weeks: until.Sub(since).Hours() / (24 * 7)}
+	var earlier []string
+	for _, d := range deploys {
+		if d.at.After(until) {
+			break
+		}
+		if !d.at.Before(since) && len(earlier) > 0 {
+			args := []string{"-C", repoPath, "log", "--no-merges", "--format=%ct"}
+			for _, a := range authors {
+				args = append(args, "--author="+a)
+			}
+			args = append(append(append(args, d.rev, "--not"), earlier...), "--")
+			logOut, err := exec.Command("git", args...).Output()
+			if err != nil {
+				return out, fmt.Errorf("git log %s: %w", d.rev, err)
+			}
+			shipped := 0
+			for _, line := range strings.Fields(string(logOut)) {
+				ct, err := strconv.ParseInt(line, 10, 64)
+				if err != nil {
+					continue
+				}
+				days := d.at.Sub(time.Unix(ct, 0)).Hours() / 24
+				if days < 0 {
+					days = 0
+				}
+				out.days = append(out.days, days)
+				shipped++
+			}
+			if shipped > 0 {
+				out.Deploys++
+			}
+		}
+		earlier = append(earlier, d.rev)
+	}
+
+	out.Samples = len(out.days)
+	out.MedianDays = median(out.days)
+	if out.Weeks > 0 {
+		out.PerWeek = float64(out.Deploys) / out.Weeks
+	}
+	return out, nil
+}
+
+// deployMarkers returns the repo's deploys in chronological order.
+func deployMarkers(repoPath string, src DeploySource) ([]deploy, error) {
+	var deploys []deploy
+	var err error
+	if src.LogFile != "" {
+		deploys, err = deployLog(repoPath, src.LogFile)
+	} else {
+		deploys, err = deployTags(repoPath, src.TagPattern)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(deploys, func(i, j int) bool { return deploys[i].at.Before(deploys[j].at) })
+	return deploys, nil
+}
+
+// deployTags lists the tags matching pattern, dated by the tagger (annotated
+// tags) or the tagged commit (lightweight tags).
+func deployTags(repoPath, pattern string) ([]deploy, error) {
+	out, err := exec.Command("git", "-C", repoPath, "for-each-ref",
+		"--format=%(creatordate:unix) %(refname)", "refs/tags/"+pattern).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref: %w", err)
+	}
+	var deploys []deploy
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		ts, ref, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		unix, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			continue
+		}
+		deploys = append(deploys, deploy{at: time.Unix(unix, 0), rev: ref + "^{commit}", name: strings.TrimPrefix(ref, "refs/tags/")})
+	}
+	return deploys, nil
+}
+
+// deployLog reads a deploy log of (time, commit) entries, where time is
+// RFC 3339, YYYY-MM-DD or Unix seconds and commit is anything git can
+// resolve. A .csv log needs "time" and "commit" header columns, a .json log
+// is an array of {"time", "commit"} objects, and any other file has one
+// "<time> <commit>" line per deploy (anything after the commit, blank lines
+// and lines starting with # are ignored).
+func deployLog(repoPath, path string) ([]deploy, error) {
+	entries, err := readDeployLog(path)
+	if err != nil {
+		return nil, fmt.Errorf("deploy log: %w", err)
+	}
+	var deploys []deploy
+	for _, e := range entries {
+		at, err := parseDeployTime(e.time)
+		if err != nil {
+			return nil, fmt.Errorf("deploy log %s: %w", e.where, err)
+		}
+		rev, err := exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", e.commit+"^{commit}").Output()
+		if err != nil {
+			continue // not a commit of this repo
+		}
+		deploys = append(deploys, deploy{at: at, rev: strings.TrimSpace(string(rev)), name: e.commit})
+	}
+	return deploys, nil
+}
+
+// logEntry is one raw deploy log entry; where locates it for errors.
+type logEntry struct {
+	time, commit, where string
+}
+
+func readDeployLog(path string) ([]logEntry, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rows, err := readCSV(path, 2, "time", "commit")
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]logEntry, len(rows))
+		for i, row := range rows {
+			entries[i] = logEntry{time: row[0], commit: row[1], where: fmt.Sprintf("%s:%d", path, i+2)}
+		}
+		return entries, nil
+	case ".json":
+		var records []struct {
+			Time   string `json:"time"`
+			Commit string `json:"commit"`
+		}
+		if err := readJSON(path, &records); err != nil {
+			return nil, err
+		}
+		entries := make([]logEntry, len(records))
+		for i, r := range records {
+			entries[i] = logEntry{time: r.Time, commit: r.Commit, where: fmt.Sprintf("%s entry %d", path, i+1)}
+		}
+		return entries, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []logEntry
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		where := fmt.Sprintf("%s:%d", path, n)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s: want \"<time> <commit>\"", where)
+		}
+		entries = append(entries, logEntry{time: fields[0], commit: fields[1], where: where})
+	}
+	return entries, scanner.Err()
+}
+
+// readCSV reads a CSV file with a header row and returns, for every data
+// row, the values of the named columns in order. Column names are matched
+// case-insensitively; the first required columns must be present, the
+// rest may be missing and read as "".
+func readCSV(path string, required int, columns ...string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	index := make([]int, len(columns))
+	for i, col := range columns {
+		index[i] = -1
+		for j, h := range records[0] {
+			if strings.EqualFold(strings.TrimSpace(h), col) {
+				index[i] = j
+			}
+		}
+	}
+	for i := 0; i < required; i++ {
+		if index[i] < 0 {
+			return nil, fmt.Errorf("%s: missing %q column", path, columns[i])
+		}
+	}
+	var rows [][]string
+	for _, rec := range records[1:] {
+		row := make([]string, len(columns))
+		for i, j := range index {
+			if j >= 0 && j < len(rec) {
+				row[i] = strings.TrimSpace(rec[j])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func parseDeployTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid deploy time %q", s)
+}
@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeployLeadTimeFromTags(t *testing.T) {
+	r := newTestRepo(t)
+	dev := "Dev <dev@example.com>"
+	other := "Other <other@example.com>"
+	base := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	r.writeFile("f.txt", line(0))
+	r.commit("init", dev, base)
+	tagAt(t, r, "v1.0", base)
+
+	// Shipped by v1.1 on day 4: dev's commits from day 1 and day 3, and one
+	// by someone else.
+	r.writeFile("f.txt", line(1))
+	r.commit("a", dev, base.Add(24*time.Hour))
+	r.writeFile("f.txt", line(2))
+	r.commit("b", other, base.Add(48*time.Hour))
+	r.writeFile("f.txt", line(3))
+	r.commit("c", dev, base.Add(72*time.Hour))
+	tagAt(t, r, "v1.1", base.Add(96*time.Hour))
+
+	// Shipped by v1.2 on day 8: only the other author's work.
+	r.writeFile("f.txt", line(4))
+	r.commit("d", other, base.Add(120*time.Hour))
+	tagAt(t, r, "v1.2", base.Add(192*time.Hour))
+
+	since, until := base.Add(-time.Hour), base.Add(14*24*time.Hour)
+	d, err := ComputeDeployLeadTime(r.path, []string{"dev@example.com"}, since, until, DeploySource{TagPattern: "v*"})
+	if err != nil {
+		t.Fatalf("ComputeDeployLeadTime: %v", err)
+	}
+	// v1.0 is the first deploy ever and is skipped; v1.1 ships dev's two
+	// commits after 3 and 1 days.
+	if d.Samples != 2 || math.Abs(d.MedianDays-2) > 0.01 {
+		t.Errorf("Samples=%d MedianDays=%.2f, want 2 samples with median 2", d.Samples, d.MedianDays)
+	}
+	if d.Deploys != 1 || math.Abs(d.PerWeek-0.5) > 0.01 {
+		t.Errorf("Deploys=%d PerWeek=%.2f, want 1 deploy, 0.5/week", d.Deploys, d.PerWeek)
+	}
+
+	team, err := ComputeDeployLeadTime(r.path, []string{"dev@example.com", "other@example.com"}, since, until, DeploySource{TagPattern: "v*"})
+	if err != nil {
+		t.Fatalf("ComputeDeployLeadTime: %v", err)
+	}
+	if team.Samples != 4 || team.Deploys != 2 {
+		t.Errorf("team: Samples=%d Deploys=%d, want 4 commits over 2 deploys", team.Samples, team.Deploys)
+	}
+}
+
+func TestDeployLeadTimeFromLog(t *testing.T) {
+	r := newTestRepo(t)
+	dev := "Dev <dev@example.com>"
+	base := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	r.writeFile("f.txt", line(0))
+	r.commit("init", dev, base)
+	first := revParse(t, r, "HEAD")
+	r.writeFile("f.txt", line(1))
+	r.commit("a", dev, base.Add(24*time.Hour))
+	second := revParse(t, r, "HEAD")
+
+	log := filepath.Join(t.TempDir(), "deploys.log")
+	content := strings.Join([]string{
+		"# time commit",
+		base.Format(time.RFC3339) + " " + first + " production",
+		"2026-03-05 " + second,
+		base.Format(time.RFC3339) + " 0123456789abcdef0123456789abcdef01234567 other-repo",
+		"",
+	}, "\n")
+	if err := os.WriteFile(log, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := ComputeDeployLeadTime(r.path, []string{"dev@example.com"}, base.Add(-time.Hour), base.Add(7*24*time.Hour), DeploySource{LogFile: log})
+	if err != nil {
+		t.Fatalf("ComputeDeployLeadTime: %v", err)
+	}
+	if d.Samples != 1 || d.Deploys != 1 || d.Source != log {
+		t.Errorf("got %+v, want one commit shipped by one deploy from %s", d, log)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.log")
+	if err := os.WriteFile(bad, []byte("yesterday "+first+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ComputeDeployLeadTime(r.path, nil, base, base.Add(time.Hour), DeploySource{LogFile: bad}); err == nil {
+		t.Error("expected an error for an unparseable deploy time")
+	}
+}
+
+func TestDeployLogCSVColumns(t *testing.T) {
+	r := newTestRepo(t)
+	base := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	r.writeFile("f.txt", line(0))
+	r.commit("init", "Dev <dev@example.com>", base)
+	head := revParse(t, r, "HEAD")
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"no-commit.csv": "time,env\n" + base.Format(time.RFC3339) + ",production\n",
+		"no-time.csv":   "when,commit\n" + base.Format(time.RFC3339) + "," + head + "\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ComputeDeployLeadTime(r.path, nil, base.Add(-time.Hour), base.Add(time.Hour), DeploySource{LogFile: path})
+		want := `missing "commit" column`
+		if name == "no-time.csv" {
+			want = `missing "time" column`
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %s", name, err, want)
+		}
+	}
+
+	// Columns may come in any order and case. The first deploy ships the
+	// whole history and is skipped.
+	r.writeFile("f.txt", line(1))
+	r.commit("a", "Dev <dev@example.com>", base.Add(time.Hour))
+	second := revParse(t, r, "HEAD")
+	ok := filepath.Join(dir, "deploys.csv")
+	content := "Commit,Time\n" + head + "," + base.Format(time.RFC3339) + "\n" + second + "," + base.Add(2*time.Hour).Format(time.RFC3339) + "\n"
+	if err := os.WriteFile(ok, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := ComputeDeployLeadTime(r.path, []string{"dev@example.com"}, base.Add(-time.Hour), base.Add(3*time.Hour), DeploySource{LogFile: ok})
+	if err != nil || d.Deploys != 1 {
+		t.Errorf("got %+v, %v; want one deploy", d, err)
+	}
+}
+
+// tagAt creates an annotated tag on HEAD dated ts.
+func tagAt(t *testing.T, r *testRepo, name string, ts time.Time) {
+	t.Helper()
+	runEnv(t, []string{"GIT_COMMITTER_DATE=" + ts.Format(time.RFC3339)}, "git", "-C", r.path, "tag", "-a", "-m", name, name)
+}
+
+func revParse(t *testing.T, r *testRepo, rev string) string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", r.path, "rev-parse", rev).Output()
+	if err != nil {
+		t.Fatalf("rev-parse %s: %v", rev, err)
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status is the outcome of computing one metric.
+type Status string
+
+const (
+	StatusOK           Status = "ok"
+	StatusSkipped      Status = "skipped"           // not applicable, e.g. no main branch
+	StatusInsufficient Status = "insufficient_data" // ran, but had too little to measure
+	StatusError        Status = "error"             // failed; Reason says why
+)
+
+// Diagnostic records how a metric (or the analysis feeding it) went for one
+// repository, author or window, so reports can explain a missing section
+// instead of silently dropping it.
+type Diagnostic struct {
+	Metric string `json:"metric"` // commit-size, cadence, lead-time, deploy, churn, reverts, baseline, analyze or explain
+	Repo   string `json:"repo,omitempty"`
+	Author string `json:"author,omitempty"`
+	Scope  string `json:"scope,omitempty"` // e.g. "baseline window" or a compare period label
+	Status Status `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (d Diagnostic) String() string {
+	var b strings.Builder
+	b.WriteString(d.Metric)
+	if d.Scope != "" {
+		fmt.Fprintf(&b, " (%s)", d.Scope)
+	}
+	b.WriteString(": ")
+	b.WriteString(strings.ReplaceAll(string(d.Status), "_", " "))
+	if d.Repo != "" {
+		fmt.Fprintf(&b, " in %s", d.Repo)
+	}
+	if d.Author != "" {
+		fmt.Fprintf(&b, " for %s", d.Author)
+	}
+	if d.Reason != "" {
+		fmt.Fprintf(&b, " — %s", d.Reason)
+	}
+	return b.String()
+}
+
+// Errors returns the diagnostics with StatusError.
+func Errors(diags []Diagnostic) []Diagnostic {
+	var out []Diagnostic
+	for _, d := range diags {
+		if d.Status == StatusError {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// StrictError returns an error listing every failed diagnostic, or nil when
+// none failed. Used by --strict, and to explain a run where nothing could
+// be analyzed.
+func StrictError(diags []Diagnostic) error {
+	errs := Errors(diags)
+	if len(errs) == 0 {
+		return nil
+	}
+	lines := make([]string, len(errs))
+	for i, d := range errs {
+		lines[i] = "  " + d.String()
+	}
+	return fmt.Errorf("%d metric error(s):\n%s", len(errs), strings.Join(lines, "\n"))
+}
+
+const noMainBranch = "no main branch found; set --main-branch or " + MainBranchConfigKey
+
+// Diagnose classifies each computed metric in b, which covers a single repo.
+// errs holds the error of each metric that failed, keyed by metric name;
+// failed metrics are nil in b.
+func Diagnose(b Bundle, repo string, errs map[string]error) []Diagnostic {
+	var out []Diagnostic
+	add := func(metric string, status Status, reason string) {
+		out = append(out, Diagnostic{Metric: metric, Repo: repo, Status: status, Reason: reason})
+	}
+	check := func(metric string, selected bool, classify func() (Status, string)) {
+		if !selected {
+			return
+		}
+		if err := errs[metric]; err != nil {
+			add(metric, StatusError, err.Error())
+			return
+		}
+		status, reason := classify()
+		add(metric, status, reason)
+	}
+
+	check(MetricCommitSize, b.Selection.CommitSize, func() (Status, string) {
+		if b.CommitSize == nil || b.CommitSize.Total == 0 {
+			return StatusInsufficient, "no commits in period"
+		}
+		return StatusOK, ""
+	})
+	check(MetricCadence, b.Selection.Cadence, func() (Status, string) {
+		switch {
+		case b.Cadence == nil || b.Cadence.MainBranch == "":
+			return StatusSkipped, noMainBranch
+		case b.Cadence.Samples < 1:
+			return StatusInsufficient, "need 2+ commits on " + b.Cadence.MainBranch
+		}
+		return StatusOK, ""
+	})
+	check(MetricLeadTime, b.Selection.LeadTime, func() (Status, string) {
+		switch {
+		case b.LeadTime == nil || b.LeadTime.MainBranch == "":
+			return StatusSkipped, noMainBranch
+		case b.LeadTime.Samples == 0:
+			return StatusInsufficient, "no changes with a detectable branch in period"
+		}
+		return StatusOK, ""
+	})
+	check(MetricDeploy, b.Selection.Deploy, func() (Status, string) {
+		if b.Deploy == nil || b.Deploy.Samples == 0 {
+			return StatusInsufficient, "no deploys shipping the author's commits in period"
+		}
+		return StatusOK, ""
+	})
+	check(MetricChurn, b.Selection.Churn, func() (Status, string) {
+		if b.Churn == nil || b.Churn.AddedLines == 0 {
+			return StatusInsufficient, "no added lines in the churn window"
+		}
+		return StatusOK, ""
+	})
+	check(MetricReverts, b.Selection.Reverts, func() (Status, string) {
+		if b.Reverts == nil || b.Reverts.Commits == 0 {
+			return StatusInsufficient, "no commits in period"
+		}
+		return StatusOK, ""
+	})
+	return out
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	b := Bundle{
+		Selection:  Selection{CommitSize: true, Cadence: true, LeadTime: true, Churn: true},
+		CommitSize: &CommitSizeDistribution{Counts: []int{1, 0, 0, 0}, Total: 1},
+		Cadence:    &Cadence{},
+		Churn:      &Churn{WindowDays: 30},
+	}
+	errs := map[string]error{MetricLeadTime: errors.New("git log failed")}
+
+	got := Diagnose(b, "/src/api", errs)
+
+	want := map[string]Status{
+		MetricCommitSize: StatusOK,
+		MetricCadence:    StatusSkipped,
+		MetricLeadTime:   StatusError,
+		MetricChurn:      StatusInsufficient,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d diagnostics, want %d: %+v", len(got), len(want), got)
+	}
+	for _, d := range got {
+		if d.Status != want[d.Metric] {
+			t.Errorf("%s: status %q, want %q", d.Metric, d.Status, want[d.Metric])
+		}
+		if d.Repo != "/src/api" {
+			t.Errorf("%s: repo %q, want /src/api", d.Metric, d.Repo)
+		}
+	}
+}
+
+func TestDiagnoseOnlySelected(t *testing.T) {
+	if got := Diagnose(Bundle{Selection: Selection{Churn: true}, Churn: &Churn{AddedLines: 10}}, "r", nil); len(got) != 1 || got[0].Metric != MetricChurn {
+		t.Errorf("got %+v, want a single churn diagnostic", got)
+	}
+}
+
+func TestStrictError(t *testing.T) {
+	ok := []Diagnostic{
+		{Metric: MetricCadence, Status: StatusSkipped, Reason: noMainBranch},
+		{Metric: MetricChurn, Status: StatusInsufficient},
+	}
+	if err := StrictError(ok); err != nil {
+		t.Errorf("StrictError without errors = %v, want nil", err)
+	}
+
+	failed := append(ok, Diagnostic{Metric: MetricLeadTime, Repo: "api", Status: StatusError, Reason: "git log failed"})
+	err := StrictError(failed)
+	if err == nil {
+		t.Fatal("StrictError with an error = nil")
+	}
+	if msg := err.Error(); !strings.Contains(msg, "lead-time: error in api — git log failed") || strings.Contains(msg, "cadence") {
+		t.Errorf("StrictError = %q, want only the failed metric", msg)
+	}
+}
@@ -0,0 +1,370 @@
+package metrics
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FailureSource says how failed deploys are recognised besides revert
+// commits, which are always used: branches matching HotfixPattern (e.g.
+// hotfix/*) and a local incident file. Empty fields are disabled.
+// MainBranches are the --main-branch patterns hotfix branches fork from
+// (default: the repo's main branches, see mainBranches).
+type FailureSource struct {
+	HotfixPattern string
+	IncidentFile  string
+	MainBranches  []string
+}
+
+// Failure kinds.
+const (
+	FailureRevert   = "revert"
+	FailureHotfix   = "hotfix"
+	FailureIncident = "incident"
+)
+
+// Failure is one production failure traced back to the deploy that caused
+// it. A failure is restored when the fix (the revert, the hotfix) is
+// deployed, or at the incident's end time.
+type Failure struct {
+	Repo         string    `json:"repo"`
+	Kind         string    `json:"kind"`
+	Detail       string    `json:"detail,omitempty"` // revert subject, hotfix branch or incident description
+	Deploy       string    `json:"deploy"`
+	DeployedAt   time.Time `json:"deployed_at"`
+	Restored     bool      `json:"restored"`
+	RestoredBy   string    `json:"restored_by,omitempty"` // deploy shipping the fix; empty for incidents
+	RestoreHours float64   `json:"restore_hours,omitempty"`
+
+	started time.Time // an incident's start, when its deploy was inferred from it
+}
+
+// DORA holds the four DORA metrics for a period: deploy frequency, lead
+// time for changes (commit to deploy), change failure rate (deploys that
+// caused a failure) and time to restore (median over restored failures).
+type DORA struct {
+	Source             string         `json:"source"`
+	Weeks              float64        `json:"weeks"`
+	Deploys            int            `json:"deploys"`
+	PerWeek            float64        `json:"deploys_per_week"`
+	LeadTime           DeployLeadTime `json:"lead_time"`
+	FailedDeploys      int            `json:"failed_deploys"`
+	ChangeFailureRate  float64        `json:"change_failure_rate"`
+	Restores           int            `json:"restores"`
+	RestoreMedianHours float64        `json:"restore_median_hours"`
+	Failures           []Failure      `json:"failures"`
+}
+
+var revertRe = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+
+// ComputeDORA computes the DORA metrics of a repo over [since, until], from
+// the deploys in src and the failures found by fs. Lead time only counts
+// the authors' commits (all commits when authors is empty); deploys and
+// failures are the repo's. Failures count in the period of the deploy that
+// caused them.
+func ComputeDORA(repoPath string, authors []string, since, until time.Time, src DeploySource, fs FailureSource) (DORA, error) {
+	out := DORA{Source: src.String(), Weeks: until.Sub(since).Hours() / (24 * 7)}
+	deploys, err := deployMarkers(repoPath, src)
+	if err != nil {
+		return out, err
+	}
+	if out.LeadTime, err = deployLeadTime(repoPath, authors, since, until, src, deploys); err != nil {
+		return out, err
+	}
+
+	f := failureFinder{repoPath: repoPath, deploys: deploys}
+	if err := f.index(); err != nil {
+		return out, err
+	}
+	if err := f.reverts(); err != nil {
+		return out, err
+	}
+	if fs.HotfixPattern != "" {
+		if err := f.hotfixes(fs.HotfixPattern, fs.MainBranches); err != nil {
+			return out, err
+		}
+	}
+	if fs.IncidentFile != "" {
+		if err := f.incidents(fs.IncidentFile); err != nil {
+			return out, err
+		}
+	}
+
+	inPeriod := func(t time.Time) bool { return !t.Before(since) && !t.After(until) }
+	for _, d := range deploys {
+		if inPeriod(d.at) {
+			out.Deploys++
+		}
+	}
+	for _, fl := range f.failures {
+		if inPeriod(fl.DeployedAt) {
+			out.Failures = append(out.Failures, fl)
+		}
+	}
+	out.summarize()
+	return out, nil
+}
+
+// AttributeIncidents keeps each incident whose deploy was inferred from its
+// start in only one of ds, the repo with the latest deploy before it: every
+// repo reading the same incident file otherwise blames its own deploy.
+func AttributeIncidents(ds []DORA) {
+	type owner struct {
+		repo int
+		at   time.Time
+	}
+	owners := make(map[string]owner)
+	key := func(f Failure) string { return f.Detail + "\x00" + f.started.UTC().String() }
+	for i, d := range ds {
+		for _, f := range d.Failures {
+			if f.started.IsZero() {
+				continue
+			}
+			if o, ok := owners[key(f)]; !ok || f.DeployedAt.After(o.at) {
+				owners[key(f)] = owner{repo: i, at: f.DeployedAt}
+			}
+		}
+	}
+	for i := range ds {
+		kept := ds[i].Failures[:0]
+		for _, f := range ds[i].Failures {
+			if f.started.IsZero() || owners[key(f)].repo == i {
+				kept = append(kept, f)
+			}
+		}
+		ds[i].Failures = kept
+		ds[i].summarize()
+	}
+}
+
+// MergeDORA pools the DORA metrics of several repos over the same period.
+// Run AttributeIncidents on ds first so shared incidents count once.
+func MergeDORA(ds []DORA) DORA {
+	out := DORA{Source: ds[0].Source, Weeks: ds[0].Weeks}
+	leads := make([]DeployLeadTime, len(ds))
+	for i, d := range ds {
+		leads[i] = d.LeadTime
+		out.Deploys += d.Deploys
+		out.Failures = append(out.Failures, d.Failures...)
+	}
+	out.LeadTime = MergeDeployLeadTime(leads)
+	out.summarize()
+	return out
+}
+
+// summarize derives the rates and medians from Deploys and Failures.
+func (d *DORA) summarize() {
+	failed := make(map[string]bool)
+	var hours []float64
+	for _, f := range d.Failures {
+		failed[f.Repo+"\x00"+f.Deploy] = true
+		if f.Restored {
+			hours = append(hours, f.RestoreHours)
+		}
+	}
+	d.FailedDeploys = len(failed)
+	d.Restores = len(hours)
+	d.RestoreMedianHours = median(hours)
+	d.PerWeek, d.ChangeFailureRate = 0, 0
+	if d.Weeks > 0 {
+		d.PerWeek = float64(d.Deploys) / d.Weeks
+	}
+	if d.Deploys > 0 {
+		d.ChangeFailureRate = float64(d.FailedDeploys) / float64(d.Deploys)
+	}
+}
+
+// failureFinder traces failures back to deploys. shipped maps every commit
+// to the first deploy that contained it.
+type failureFinder struct {
+	repoPath string
+	deploys  []deploy
+	shipped  map[string]int
+	failures []Failure
+}
+
+// index walks each deploy's commits down to the previous deploy. A commit
+// reachable from the previous deploy was indexed with it or earlier, so
+// only commits not yet indexed take deploy i.
+func (f *failureFinder) index() error {
+	f.shipped = make(map[string]int)
+	for i, d := range f.deploys {
+		args := []string{"-C", f.repoPath, "rev-list", d.rev}
+		if i > 0 {
+			args = append(args, "--not", f.deploys[i-1].rev)
+		}
+		out, err := exec.Command("git", append(args, "--")...).Output()
+		if err != nil {
+			return fmt.Errorf("git rev-list %s: %w", d.rev, err)
+		}
+		for _, sha := range strings.Fields(string(out)) {
+			if _, ok := f.shipped[sha]; !ok {
+				f.shipped[sha] = i
+			}
+		}
+	}
+	return nil
+}
+
+// deployOf returns the deploy that first shipped rev.
+func (f *failureFinder) deployOf(rev string) (int, bool) {
+	i, ok := f.shipped[resolveCommit(f.repoPath, rev)]
+	return i, ok
+}
+
+// deployBefore returns the last deploy at or before t.
+func (f *failureFinder) deployBefore(t time.Time) (int, bool) {
+	found := -1
+	for i, d := range f.deploys {
+		if d.at.After(t) {
+			break
+		}
+		found = i
+	}
+	return found, found >= 0
+}
+
+// add records a failure of deploy failed, restored by deploy restoredBy
+// (-1 when the fix isn't deployed yet).
+func (f *failureFinder) add(kind, detail string, failed, restoredBy int) {
+	d := f.deploys[failed]
+	fl := Failure{Repo: f.repoPath, Kind: kind, Detail: detail, Deploy: d.name, DeployedAt: d.at}
+	if restoredBy >= 0 {
+		r := f.deploys[restoredBy]
+		fl.Restored, fl.RestoredBy = true, r.name
+		fl.RestoreHours = r.at.Sub(d.at).Hours()
+	}
+	f.failures = append(f.failures, fl)
+}
+
+// reverts finds commits reverting deployed work. The deploy that first
+// shipped the reverted commit failed; the one shipping the revert restored
+// it. Reverts shipped together with what they revert never reached
+// production and are ignored.
+func (f *failureFinder) reverts() error {
+	out, err := exec.Command("git", "-C", f.repoPath, "log", "--all",
+		"--grep=This reverts commit", "--format=%H%x1f%s%x1f%b%x1e").Output()
+	if err != nil {
+		return fmt.Errorf("git log: %w", err)
+	}
+	for _, rec := range strings.Split(string(out), "\x1e") {
+		parts := strings.SplitN(strings.TrimSpace(rec), "\x1f", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		m := revertRe.FindStringSubmatch(parts[2])
+		if m == nil {
+			continue
+		}
+		failed, ok := f.deployOf(m[1])
+		if !ok {
+			continue
+		}
+		restoredBy, ok := f.shipped[parts[0]]
+		if !ok {
+			restoredBy = -1
+		} else if restoredBy == failed {
+			continue
+		}
+		f.add(FailureRevert, parts[1], failed, restoredBy)
+	}
+	return nil
+}
+
+// hotfixes finds hotfix branches (see findHotfixBranches). The deploy live
+// when the hotfix was started failed; the one shipping it restored it.
+func (f *failureFinder) hotfixes(pattern string, mainPatterns []string) error {
+	branches, err := findHotfixBranches(f.repoPath, pattern, mainPatterns)
+	if err != nil {
+		return err
+	}
+	for _, b := range branches {
+		if start, ok := oldestCommit(f.repoPath, b.revs...); ok {
+			f.addHotfix(b.name, start, b.fix)
+		}
+	}
+	return nil
+}
+
+// addHotfix records the failure fixed by the hotfix branch started at
+// start (Unix seconds) and shipped with fix.
+func (f *failureFinder) addHotfix(branch string, start int64, fix string) {
+	failed, ok := f.deployBefore(time.Unix(start, 0))
+	if !ok {
+		return
+	}
+	restoredBy := -1
+	if i, ok := f.shipped[fix]; ok && i > failed {
+		restoredBy = i
+	}
+	f.add(FailureHotfix, branch, failed, restoredBy)
+}
+
+// incidents reads a CSV file (header with start, end, deploy and
+// description columns) or a JSON array of such objects. start is required;
+// deploy, a deploy tag or commit, defaults to the deploy live at start; an
+// incident without end is still open.
+func (f *failureFinder) incidents(file string) error {
+	type incident struct {
+		Start       string `json:"start"`
+		End         string `json:"end"`
+		Deploy      string `json:"deploy"`
+		Description string `json:"description"`
+	}
+	var list []incident
+	if strings.ToLower(filepath.Ext(file)) == ".json" {
+		if err := readJSON(file, &list); err != nil {
+			return fmt.Errorf("incidents: %w", err)
+		}
+	} else {
+		rows, err := readCSV(file, 1, "start", "end", "deploy", "description")
+		if err != nil {
+			return fmt.Errorf("incidents: %w", err)
+		}
+		for _, r := range rows {
+			list = append(list, incident{Start: r[0], End: r[1], Deploy: r[2], Description: r[3]})
+		}
+	}
+
+	for n, inc := range list {
+		start, err := parseDeployTime(inc.Start)
+		if err != nil {
+			return fmt.Errorf("incidents %s entry %d: %w", file, n+1, err)
+		}
+		failed, ok := f.incidentDeploy(inc.Deploy, start)
+		if !ok {
+			continue // before the first deploy, or another repo's
+		}
+		f.add(FailureIncident, inc.Description, failed, -1)
+		fl := &f.failures[len(f.failures)-1]
+		if inc.Deploy == "" {
+			fl.started = start
+		}
+		if inc.End == "" {
+			continue
+		}
+		end, err := parseDeployTime(inc.End)
+		if err != nil {
+			return fmt.Errorf("incidents %s entry %d: %w", file, n+1, err)
+		}
+		fl.Restored = true
+		fl.RestoreHours = end.Sub(start).Hours()
+	}
+	return nil
+}
+
+func (f *failureFinder) incidentDeploy(name string, start time.Time) (int, bool) {
+	if name == "" {
+		return f.deployBefore(start)
+	}
+	for i, d := range f.deploys {
+		if d.name == name {
+			return i, true
+		}
+	}
+	return f.deployOf(name)
+}
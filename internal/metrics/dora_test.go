@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDORA(t *testing.T) {
+	r := newTestRepo(t)
+	dev := "Dev <dev@example.com>"
+	day := func(n int) time.Time { return time.Date(2026, 3, 2+n, 12, 0, 0, 0, time.UTC) }
+
+	r.writeFile("f.txt", line(0))
+	r.commit("init", dev, day(0))
+	tagAt(t, r, "v1", day(0))
+
+	// v2 ships a bad change, reverted and restored by v3 a day later.
+	r.writeFile("f.txt", line(1))
+	r.commit("bad change", dev, day(1))
+	bad := revParse(t, r, "HEAD")
+	tagAt(t, r, "v2", day(2))
+	runEnv(t, []string{"GIT_COMMITTER_DATE=" + day(3).Format(time.RFC3339), "GIT_AUTHOR_DATE=" + day(3).Format(time.RFC3339)},
+		"git", "-C", r.path, "revert", "--no-edit", bad)
+	tagAt(t, r, "v3", day(3))
+
+	// A hotfix branch started after v3 and merged for v4 two days later.
+	run(t, r.path, "git", "checkout", "-q", "-b", "hotfix/login")
+	r.writeFile("g.txt", line(2))
+	r.commit("fix login", dev, day(4))
+	run(t, r.path, "git", "checkout", "-q", "main")
+	runEnv(t, []string{"GIT_COMMITTER_DATE=" + day(5).Format(time.RFC3339), "GIT_AUTHOR_DATE=" + day(5).Format(time.RFC3339)},
+		"git", "-C", r.path, "merge", "--no-ff", "--no-edit", "hotfix/login")
+	tagAt(t, r, "v4", day(5))
+
+	// An incident on v4, resolved after 90 minutes.
+	incidents := filepath.Join(t.TempDir(), "incidents.csv")
+	csv := "start,end,description\n" +
+		day(6).Format(time.RFC3339) + "," + day(6).Add(90*time.Minute).Format(time.RFC3339) + ",login outage\n"
+	if err := os.WriteFile(incidents, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := ComputeDORA(r.path, nil, day(0), day(14), DeploySource{TagPattern: "v*"},
+		FailureSource{HotfixPattern: "hotfix/*", IncidentFile: incidents})
+	if err != nil {
+		t.Fatalf("ComputeDORA: %v", err)
+	}
+
+	if d.Deploys != 4 || math.Abs(d.PerWeek-2) > 0.01 {
+		t.Errorf("Deploys=%d PerWeek=%.2f, want 4 deploys, 2/week", d.Deploys, d.PerWeek)
+	}
+	want := map[string]string{FailureRevert: "v2", FailureHotfix: "v3", FailureIncident: "v4"}
+	if len(d.Failures) != len(want) {
+		t.Fatalf("got %d failures, want %d: %+v", len(d.Failures), len(want), d.Failures)
+	}
+	for _, f := range d.Failures {
+		if f.Deploy != want[f.Kind] || !f.Restored {
+			t.Errorf("%s failure: deploy %q restored=%v, want %s restored", f.Kind, f.Deploy, f.Restored, want[f.Kind])
+		}
+	}
+	if d.FailedDeploys != 3 || math.Abs(d.ChangeFailureRate-0.75) > 0.001 {
+		t.Errorf("FailedDeploys=%d CFR=%.2f, want 3 and 0.75", d.FailedDeploys, d.ChangeFailureRate)
+	}
+	// Restores: revert 24h, hotfix v3→v4 48h, incident 1.5h.
+	if d.Restores != 3 || math.Abs(d.RestoreMedianHours-24) > 0.01 {
+		t.Errorf("Restores=%d median=%.2fh, want 3 with median 24h", d.Restores, d.RestoreMedianHours)
+	}
+	if d.LeadTime.Samples == 0 {
+		t.Error("expected lead time samples")
+	}
+
+	// Pooled with a repo that deployed four times without failing.
+	merged := MergeDORA([]DORA{d, {Weeks: d.Weeks, Deploys: 4}})
+	if merged.Deploys != 8 || math.Abs(merged.ChangeFailureRate-0.375) > 0.001 || merged.Restores != 3 {
+		t.Errorf("merged: Deploys=%d CFR=%.3f Restores=%d, want 8, 0.375 and 3", merged.Deploys, merged.ChangeFailureRate, merged.Restores)
+	}
+}
+
+func TestDeployLogCSVAndJSON(t *testing.T) {
+	r := newTestRepo(t)
+	dev := "Dev <dev@example.com>"
+	base := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	r.writeFile("f.txt", line(0))
+	r.commit("init", dev, base)
+	first := revParse(t, r, "HEAD")
+	r.writeFile("f.txt", line(1))
+	r.commit("a", dev, base.Add(24*time.Hour))
+	second := revParse(t, r, "HEAD")
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"deploys.csv":  "env,commit,time\nprod," + first + ",2026-03-02T13:00:00Z\nprod," + second + ",2026-03-04\n",
+		"deploys.json": `[{"time": "2026-03-02T13:00:00Z", "commit": "` + first + `"}, {"time": "2026-03-04", "commit": "` + second + `"}]`,
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		d, err := ComputeDeployLeadTime(r.path, nil, base.Add(-time.Hour), base.Add(7*24*time.Hour), DeploySource{LogFile: p})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if d.Samples != 1 || d.Deploys != 1 {
+			t.Errorf("%s: Samples=%d Deploys=%d, want one commit shipped by one deploy", name, d.Samples, d.Deploys)
+		}
+	}
+}
+
+func TestDORAIncidentsAcrossRepos(t *testing.T) {
+	dev := "Dev <dev@example.com>"
+	day := func(n int) time.Time { return time.Date(2026, 3, 2+n, 12, 0, 0, 0, time.UTC) }
+
+	// api deploys on day 0, web on day 2; both read one incident file.
+	api, web := newTestRepo(t), newTestRepo(t)
+	for _, r := range []struct {
+		repo *testRepo
+		tag  string
+		at   time.Time
+	}{{api, "api-1", day(0)}, {web, "web-1", day(2)}} {
+		r.repo.writeFile("f.txt", line(0))
+		r.repo.commit("init", dev, r.at)
+		tagAt(t, r.repo, r.tag, r.at)
+	}
+	incidents := filepath.Join(t.TempDir(), "incidents.csv")
+	csv := "start,end,deploy,description\n" +
+		day(3).Format(time.RFC3339) + "," + day(3).Add(2*time.Hour).Format(time.RFC3339) + ",,checkout outage\n" +
+		day(4).Format(time.RFC3339) + ",,api-1,slow search\n"
+	if err := os.WriteFile(incidents, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var ds []DORA
+	for _, r := range []*testRepo{api, web} {
+		d, err := ComputeDORA(r.path, nil, day(0), day(7), DeploySource{TagPattern: "*-1"}, FailureSource{IncidentFile: incidents})
+		if err != nil {
+			t.Fatalf("ComputeDORA: %v", err)
+		}
+		ds = append(ds, d)
+	}
+	if len(ds[0].Failures) != 2 || len(ds[1].Failures) != 1 {
+		t.Fatalf("before attribution: %d and %d failures, want the shared incident in both", len(ds[0].Failures), len(ds[1].Failures))
+	}
+
+	// The incident without a deploy goes to web, which deployed last before
+	// it; the one naming api-1 stays with api.
+	AttributeIncidents(ds)
+	if len(ds[0].Failures) != 1 || ds[0].Failures[0].Detail != "slow search" || ds[0].FailedDeploys != 1 {
+		t.Errorf("api failures = %+v, want only slow search", ds[0].Failures)
+	}
+	if len(ds[1].Failures) != 1 || ds[1].Failures[0].Deploy != "web-1" || ds[1].Restores != 1 {
+		t.Errorf("web failures = %+v, want checkout outage on web-1", ds[1].Failures)
+	}
+
+	merged := MergeDORA(ds)
+	if merged.FailedDeploys != 2 || merged.Restores != 1 || len(merged.Failures) != 2 || math.Abs(merged.ChangeFailureRate-1) > 0.001 {
+		t.Errorf("merged: FailedDeploys=%d Restores=%d failures=%d CFR=%.2f, want 2, 1, 2 and 1.00",
+			merged.FailedDeploys, merged.Restores, len(merged.Failures), merged.ChangeFailureRate)
+	}
+}
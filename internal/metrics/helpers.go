@@ -3,15 +3,102 @@ package metrics
 import (
 	"fmt"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
 )
 
-// detectMainBranch returns "main", "master", or the resolved origin HEAD name.
-// Empty string means no main-like branch found.
+// MainBranchConfigKey is the git config key a repository can set (possibly
+// several times) to name its integration branches, e.g.
+// `git config --add gitrespect.mainBranch 'release/*'`.
+const MainBranchConfigKey = "gitrespect.mainBranch"
+
+// mainBranches resolves the integration branches of a repo: the given
+// patterns (--main-branch) if any, else the repo's MainBranchConfigKey
+// entries, else the auto-detected main branch. Patterns may be globs such
+// as release/*, matched against local branches and then origin's. The
+// result is empty when nothing matches.
+func mainBranches(repoPath string, patterns []string) []string {
+	if len(patterns) == 0 {
+		patterns = configuredMainBranches(repoPath)
+	}
+	if len(patterns) == 0 {
+		if branch := detectMainBranch(repoPath); branch != "" {
+			return []string{branch}
+		}
+		return nil
+	}
+	var branches []string
+	seen := make(map[string]bool)
+	for _, p := range patterns {
+		for _, b := range matchBranches(repoPath, p) {
+			if !seen[b] {
+				seen[b] = true
+				branches = append(branches, b)
+			}
+		}
+	}
+	return branches
+}
+
+// configuredMainBranches reads the repo's MainBranchConfigKey entries.
+func configuredMainBranches(repoPath string) []string {
+	out, err := exec.Command("git", "-C", repoPath, "config", "--get-all", MainBranchConfigKey).Output()
+	if err != nil {
+		return nil
+	}
+	var patterns []string
+	for _, line := range strings.Split(string(out), "\n") {
+		for _, p := range strings.Split(line, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				patterns = append(patterns, p)
+			}
+		}
+	}
+	return patterns
+}
+
+// matchBranches returns the branches matching pattern, preferring local
+// branches over origin's remote-tracking ones.
+func matchBranches(repoPath, pattern string) []string {
+	if !strings.ContainsAny(pattern, "*?[") {
+		for _, name := range []string{pattern, "origin/" + pattern} {
+			if branchExists(repoPath, name) {
+				return []string{name}
+			}
+		}
+		return nil
+	}
+	for _, refs := range []struct{ prefix, short string }{
+		{"refs/heads/", ""},
+		{"refs/remotes/origin/", "origin/"},
+	} {
+		out, err := exec.Command("git", "-C", repoPath, "for-each-ref", "--format=%(refname)", refs.prefix).Output()
+		if err != nil {
+			continue
+		}
+		var matched []string
+		for _, ref := range strings.Fields(string(out)) {
+			name := strings.TrimPrefix(ref, refs.prefix)
+			if ok, _ := path.Match(pattern, name); ok {
+				matched = append(matched, refs.short+name)
+			}
+		}
+		if len(matched) > 0 {
+			return matched
+		}
+	}
+	return nil
+}
+
+// detectMainBranch returns the resolved origin HEAD name, or the first of
+// main, master, trunk and develop that exists. Empty string means no
+// main-like branch found.
 func detectMainBranch(repoPath string) string {
 	if out, err := exec.Command("git", "-C", repoPath, "symbolic-ref", "refs/remotes/origin/HEAD").Output(); err == nil {
 		ref := strings.TrimSpace(string(out))
@@ -22,7 +109,7 @@ func detectMainBranch(repoPath string) string {
 			}
 		}
 	}
-	for _, candidate := range []string{"main", "master"} {
+	for _, candidate := range []string{"main", "master", "trunk", "develop"} {
 		if branchExists(repoPath, candidate) {
 			return candidate
 		}
@@ -73,14 +160,14 @@ func shouldExcludeFile(filename string, patterns []string) bool {
 }
 
 // sumNumstat returns (totalAdded, totalDeleted) for the author's commits in the window,
-// excluding binary files and excluded patterns.
-func sumNumstat(repoPath, author string, since, until time.Time, exclude []string) (int, int, error) {
+// excluding binary files, excluded patterns and the commits excluded reports.
+func sumNumstat(repoPath, author string, since, until time.Time, exclude []string, excluded func(hash string) bool) (int, int, error) {
 	args := []string{
 		"-C", repoPath, "log",
 		"--author=" + author,
-		"--since=" + since.Format("2006-01-02"),
-		"--until=" + until.Format("2006-01-02"),
-		"--pretty=format:",
+		"--since=" + git.LogDate(since),
+		"--until=" + git.LogDate(until),
+		"--pretty=format:COMMIT %H",
 		"--numstat",
 	}
 	out, err := exec.Command("git", args...).Output()
@@ -88,9 +175,14 @@ func sumNumstat(repoPath, author string, since, until time.Time, exclude []strin
 		return 0, 0, fmt.Errorf("git log: %w", err)
 	}
 	totalAdded, totalDeleted := 0, 0
+	skip := false
 	for _, line := range strings.Split(string(out), "\n") {
+		if hash, ok := strings.CutPrefix(line, "COMMIT "); ok {
+			skip = excluded(hash)
+			continue
+		}
 		line = strings.TrimSpace(line)
-		if line == "" {
+		if skip || line == "" {
 			continue
 		}
 		fields := strings.Fields(line)
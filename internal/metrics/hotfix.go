@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var mergedBranchRe = regexp.MustCompile(`^Merge (?:remote-tracking )?branch '([^']+)'|^Merge pull request #\d+ from [^/\s]+/(\S+)`)
+
+// hotfixBranch is a branch whose work fixes production.
+type hotfixBranch struct {
+	name string
+	fix  string   // the commit that lands it: its merge, or its tip
+	revs []string // git log/rev-list arguments selecting its own commits
+}
+
+// findHotfixBranches finds the branches matching pattern (e.g. hotfix/*):
+// merged ones by their merge commit's subject, and those still present
+// locally or on origin. An unmerged branch's own commits are those off the
+// main branches (see mainBranches).
+func findHotfixBranches(repoPath, pattern string, branchPatterns []string) ([]hotfixBranch, error) {
+	out, err := exec.Command("git", "-C", repoPath, "log", "--all", "--merges",
+		"--format=%H%x1f%s%x1f%P").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	var branches []hotfixBranch
+	merged := make(map[string]bool) // tips already seen through their merge
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, "\x1f", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		m := mergedBranchRe.FindStringSubmatch(parts[1])
+		if m == nil {
+			continue
+		}
+		name := m[1] + m[2]
+		if ok, _ := path.Match(pattern, strings.TrimPrefix(name, "origin/")); !ok {
+			continue
+		}
+		parents := strings.Fields(parts[2])
+		if len(parents) < 2 {
+			continue
+		}
+		merged[parents[1]] = true
+		branches = append(branches, hotfixBranch{name: name, fix: parts[0], revs: []string{parents[0] + ".." + parents[1]}})
+	}
+
+	trunk := mainBranches(repoPath, branchPatterns)
+	for _, name := range matchBranches(repoPath, pattern) {
+		tip, err := exec.Command("git", "-C", repoPath, "rev-parse", name+"^{commit}").Output()
+		sha := strings.TrimSpace(string(tip))
+		if err != nil || merged[sha] {
+			continue
+		}
+		b := hotfixBranch{name: name, fix: sha, revs: []string{sha}}
+		if len(trunk) > 0 {
+			b.revs = append(append(b.revs, "--not"), trunk...)
+		}
+		if _, ok := oldestCommit(repoPath, b.revs...); !ok {
+			// Already on the main branch: the tip is the whole hotfix.
+			b.revs = []string{"-1", sha}
+		}
+		branches = append(branches, b)
+	}
+	return branches, nil
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHotfixBranchesMainBranchPatterns(t *testing.T) {
+	r := newTestRepo(t)
+	dev := "Dev <dev@example.com>"
+	day := func(n int) time.Time { return time.Date(2026, 3, 2+n, 12, 0, 0, 0, time.UTC) }
+
+	r.writeFile("a.txt", "a\n")
+	r.commit("init", dev, day(0))
+
+	// Releases ship from develop; the hotfix forks from it on day 5.
+	run(t, r.path, "git", "checkout", "-q", "-b", "develop")
+	r.writeFile("b.txt", "b\n")
+	r.commit("develop work", dev, day(1))
+	run(t, r.path, "git", "checkout", "-q", "-b", "hotfix/b")
+	r.writeFile("b.txt", "b fixed\n")
+	r.commit("patch b", dev, day(5))
+
+	for _, tc := range []struct {
+		patterns []string
+		start    time.Time
+	}{
+		{nil, day(1)}, // main is detected, so develop's work counts too
+		{[]string{"develop"}, day(5)},
+	} {
+		branches, err := findHotfixBranches(r.path, "hotfix/*", tc.patterns)
+		if err != nil {
+			t.Fatalf("findHotfixBranches(%v): %v", tc.patterns, err)
+		}
+		if len(branches) != 1 {
+			t.Fatalf("findHotfixBranches(%v): %d branches, want 1", tc.patterns, len(branches))
+		}
+		start, ok := oldestCommit(r.path, branches[0].revs...)
+		if !ok || start != tc.start.Unix() {
+			t.Errorf("main branches %v: hotfix started %v, want %v", tc.patterns, time.Unix(start, 0).UTC(), tc.start)
+		}
+	}
+}
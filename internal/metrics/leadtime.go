@@ -3,91 +3,504 @@ package metrics
 import (
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+)
+
+// Lead time estimation methods, recorded on every sample.
+const (
+	LeadMethodMerge    = "merge"     // merge commit: first branch commit to the merge
+	LeadMethodTrailer  = "trailer"   // Change-Id/Reviewed-on: first patch set to submit
+	LeadMethodPRNumber = "pr-number" // "(#123)" squash subject: first commit on the PR's ref
+	LeadMethodPatchID  = "patch-id"  // same patch as a branch or commit in local refs or reflog
 )
 
-// LeadTime holds the result of a lead time analysis across merge commits.
+// LeadTime holds the result of a lead time analysis across the changes that
+// landed on the main branch.
 type LeadTime struct {
-	MedianDays float64 `json:"median_days"`
-	Samples    int     `json:"samples"`
-	MainBranch string  `json:"main_branch"`
+	MedianDays float64          `json:"median_days"`
+	Samples    int              `json:"samples"`
+	MainBranch string           `json:"main_branch"`
+	Changes    []LeadTimeSample `json:"changes,omitempty"`
+	Unmatched  int              `json:"unmatched,omitempty"` // commits on main whose branch could not be found
+}
+
+// LeadTimeSample is the lead time of one change, and how it was estimated.
+type LeadTimeSample struct {
+	Commit string  `json:"commit"`
+	Days   float64 `json:"days"`
+	Method string  `json:"method"`
+}
+
+// MethodCounts returns the number of samples produced by each method.
+func (l LeadTime) MethodCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, c := range l.Changes {
+		counts[c.Method]++
+	}
+	return counts
+}
+
+// setChanges records the samples and recomputes Samples and MedianDays.
+func (l *LeadTime) setChanges(changes []LeadTimeSample) {
+	l.Changes = changes
+	days := make([]float64, len(changes))
+	for i, c := range changes {
+		days[i] = c.Days
+	}
+	l.Samples = len(days)
+	l.MedianDays = median(days)
 }
 
-// ComputeLeadTime calculates the median lead time (in days) for merge commits
-// authored by the given author on the main branch within the specified window.
-func ComputeLeadTime(repoPath, author string, since, until time.Time) (LeadTime, error) {
-	main := detectMainBranch(repoPath)
-	if main == "" {
+// ComputeLeadTime calculates the median lead time (in days) of the author's
+// changes on the main branches within the specified window: from the first
+// commit of the change's branch to the commit that landed it. Merge commits
+// carry their branch; for squash- and rebase-merged commits the branch is
+// looked up through Change-Id/Reviewed-on trailers, a "(#123)" pull request
+// number in the subject, or a branch or commit with the same patch-id still
+// in local refs or the reflog, in that order; only branch commits from
+// BranchLookback before since up to until are read for those. branches and
+// excludeCommits are as for ComputeCadence.
+func ComputeLeadTime(repoPath, author string, since, until time.Time, branches, excludeCommits []string) (LeadTime, error) {
+	trunk := mainBranches(repoPath, branches)
+	if len(trunk) == 0 {
 		return LeadTime{MainBranch: ""}, nil
 	}
 
+	commits, err := trunkCommits(repoPath, author, since, until, trunk, git.ExcludedCommits(repoPath, excludeCommits))
+	if err != nil {
+		return LeadTime{}, err
+	}
+
+	lt := LeadTime{MainBranch: strings.Join(trunk, ", ")}
+	est := &leadEstimator{repoPath: repoPath, trunk: trunk, since: since.Add(-BranchLookback), until: until}
+	var changes []LeadTimeSample
+	add := func(c trunkCommit, start int64, method string) {
+		leadDays := float64(c.committed-start) / 86400.0
+		if leadDays < 0 {
+			leadDays = 0
+		}
+		changes = append(changes, LeadTimeSample{Commit: c.hash, Days: leadDays, Method: method})
+	}
+	var unmatched []trunkCommit
+	for _, c := range commits {
+		if start, method, ok := est.branchStart(c); ok {
+			add(c, start, method)
+		} else if len(c.parents) < 2 {
+			unmatched = append(unmatched, c)
+		} else {
+			lt.Unmatched++
+		}
+	}
+	// The rest are matched by patch-id, all at once.
+	starts := est.patchIDStarts(unmatched)
+	for _, c := range unmatched {
+		if start, ok := starts[c.hash]; ok {
+			add(c, start, LeadMethodPatchID)
+		} else {
+			lt.Unmatched++
+		}
+	}
+	lt.setChanges(changes)
+	return lt, nil
+}
+
+// trunkCommit is a commit on the first-parent history of a main branch.
+type trunkCommit struct {
+	hash      string
+	parents   []string
+	committed int64 // Unix seconds
+	authored  int64
+	subject   string
+	body      string
+}
+
+func trunkCommits(repoPath, author string, since, until time.Time, trunk []string, excluded func(hash string) bool) ([]trunkCommit, error) {
 	args := []string{
 		"-C", repoPath,
-		"log", main,
-		"--merges",
+		"log",
 		"--first-parent",
 		"--author=" + author,
-		"--since=" + since.Format("2006-01-02"),
-		"--until=" + until.Format("2006-01-02"),
-		"--format=%H %P %ct",
+		"--since=" + git.LogDate(since),
+		"--until=" + git.LogDate(until),
+		"--format=%H%x1f%P%x1f%ct%x1f%at%x1f%s%x1f%b%x1e",
 	}
+	args = append(append(args, trunk...), "--")
 	out, err := exec.Command("git", args...).Output()
 	if err != nil {
-		return LeadTime{}, fmt.Errorf("git log: %w", err)
+		return nil, fmt.Errorf("git log: %w", err)
 	}
 
-	var days []float64
-	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
+	var commits []trunkCommit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) != 6 || excluded(fields[0]) {
 			continue
 		}
-		tokens := strings.Fields(line)
-		// tokens: [mergeSHA, parent1, parent2, ..., commitTimestamp]
-		if len(tokens) < 4 {
-			// need at least merge SHA + 2 parents + timestamp
+		committed, err1 := strconv.ParseInt(fields[2], 10, 64)
+		authored, err2 := strconv.ParseInt(fields[3], 10, 64)
+		if err1 != nil || err2 != nil {
 			continue
 		}
-		mergeCTStr := tokens[len(tokens)-1]
-		parents := tokens[1 : len(tokens)-1]
-		if len(parents) < 2 {
-			continue
+		commits = append(commits, trunkCommit{
+			hash:      fields[0],
+			parents:   strings.Fields(fields[1]),
+			committed: committed,
+			authored:  authored,
+			subject:   fields[4],
+			body:      fields[5],
+		})
+	}
+	return commits, nil
+}
+
+var (
+	prNumberRe  = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+	changeIDRe  = regexp.MustCompile(`(?m)^Change-Id:\s*(\S+)\s*$`)
+	reviewedRe  = regexp.MustCompile(`(?m)^Reviewed-on:\s*\S+`)
+	patchLineRe = regexp.MustCompile(`^([0-9a-f]{40}) ([0-9a-f]{40})$`)
+	pullRefRe   = regexp.MustCompile(`^refs/(?:pull/(\d+)/head|remotes/[^/]+/(?:pr/(\d+)|pull/(\d+)/head))$`)
+)
+
+// BranchLookback is how long before a lead time window its changes' branches
+// may have started. Older branch commits aren't read, so a branch started
+// earlier is measured from the lookback, or left unmatched if it was
+// squashed.
+const BranchLookback = 90 * 24 * time.Hour
+
+// leadEstimator finds where the branch behind a main-branch commit started.
+// Its index covers the branch commits made between since and until.
+type leadEstimator struct {
+	repoPath     string
+	trunk        []string
+	since, until time.Time
+	index        *leadIndex
+}
+
+// leadIndex holds what the estimator looks up about the commits off the main
+// branches, those reachable only from other refs or the reflog.
+type leadIndex struct {
+	commits      map[string]*offTrunkCommit
+	branchStarts map[string]int64    // patch-id of a whole branch → when it started
+	commitStarts map[string]int64    // patch-id of a branch commit → when its branch started
+	changeStarts map[string]int64    // Change-Id → its earliest patch set
+	pullHeads    map[string][]string // pull request number → its fetched heads
+}
+
+// offTrunkCommit is a commit off the main branches. Its branch is the chain
+// of first parents that stays off them: start is the earliest commit time
+// along it, and base the main-branch commit it forked from.
+type offTrunkCommit struct {
+	parent    string
+	committed int64
+	start     int64
+	base      string
+	hasChild  bool
+}
+
+// branchStart returns the Unix time the change landed by c was started, and
+// the method that found it. Patch-id matches are left to patchIDStarts.
+func (e *leadEstimator) branchStart(c trunkCommit) (int64, string, bool) {
+	if len(c.parents) >= 2 {
+		// Oldest commit unique to the merged branch (second-parent side).
+		if start, ok := e.oldest(c.parents[0] + ".." + c.parents[1]); ok {
+			return start, LeadMethodMerge, true
+		}
+		return 0, "", false
+	}
+	if start, ok := e.trailerStart(c); ok {
+		return start, LeadMethodTrailer, true
+	}
+	if start, ok := e.pullRequestStart(c); ok {
+		return start, LeadMethodPRNumber, true
+	}
+	return 0, "", false
+}
+
+// trailerStart handles Gerrit-style changes. Every patch set of a change
+// keeps its Change-Id, so the change started at the earliest of the commit's
+// author date and any other patch set still in local refs or reflog.
+func (e *leadEstimator) trailerStart(c trunkCommit) (int64, bool) {
+	m := changeIDRe.FindStringSubmatch(c.body)
+	if m == nil {
+		if !reviewedRe.MatchString(c.body) {
+			return 0, false
 		}
-		p1 := parents[0]
-		p2 := parents[1]
+		return c.authored, true
+	}
+	start := c.authored
+	if ts, ok := e.leadIndex().changeStarts[m[1]]; ok && ts < start {
+		start = ts
+	}
+	return start, true
+}
 
-		mergeCT, err := strconv.ParseInt(mergeCTStr, 10, 64)
-		if err != nil {
-			continue
+// pullRequestStart handles "Title (#123)" squash commits whose pull request
+// head was fetched, e.g. as refs/pull/123/head or origin/pr/123.
+func (e *leadEstimator) pullRequestStart(c trunkCommit) (int64, bool) {
+	m := prNumberRe.FindStringSubmatch(c.subject)
+	if m == nil {
+		return 0, false
+	}
+	idx := e.leadIndex()
+	for _, head := range idx.pullHeads[m[1]] {
+		if oc, ok := idx.commits[head]; ok {
+			return oc.start, true
+		}
+		// The head is on a main branch too, e.g. it was also merged.
+		rng := head
+		if len(c.parents) > 0 {
+			rng = c.parents[0] + ".." + head
+		}
+		if start, ok := e.oldest(rng); ok {
+			return start, true
 		}
+	}
+	return 0, false
+}
 
-		// Find the oldest commit unique to the feature branch (p2 side).
-		branchOut, err := exec.Command("git", "-C", repoPath, "log",
-			p1+".."+p2, "--format=%ct", "--reverse").Output()
-		if err != nil {
-			continue
+// patchIDStarts matches the commits' patch-ids against branches (the
+// combined diff from their fork point) and single commits that never reached
+// a main branch, found in local refs and the reflog. A branch matched by one
+// of its commits, as rebase merges are, started at its first commit.
+func (e *leadEstimator) patchIDStarts(commits []trunkCommit) map[string]int64 {
+	idx := e.leadIndex()
+	starts := make(map[string]int64)
+	if len(commits) == 0 || (len(idx.branchStarts) == 0 && len(idx.commitStarts) == 0) {
+		return starts
+	}
+	var in strings.Builder
+	for _, c := range commits {
+		in.WriteString(c.hash + "\n")
+	}
+	for hash, pid := range diffPatchIDs(e.repoPath, in.String()) {
+		if start, ok := idx.branchStarts[pid]; ok {
+			starts[hash] = start
+		} else if start, ok := idx.commitStarts[pid]; ok {
+			starts[hash] = start
+		}
+	}
+	return starts
+}
+
+// leadIndex returns the estimator's index, building it on first use: only
+// commits that aren't merges or pull requests need it.
+func (e *leadEstimator) leadIndex() *leadIndex {
+	if e.index == nil {
+		e.index = buildLeadIndex(e.repoPath, e.trunk, e.since, e.until)
+	}
+	return e.index
+}
+
+// buildLeadIndex reads the commits off the main branches made between since
+// and until, with their patches, in one git log pass piped into one git
+// patch-id. Branch tips (refs, and reflog entries nothing was built on) are
+// diffed against their fork point in one git diff-tree pass.
+func buildLeadIndex(repoPath string, trunk []string, since, until time.Time) *leadIndex {
+	idx := &leadIndex{
+		commits:      make(map[string]*offTrunkCommit),
+		branchStarts: make(map[string]int64),
+		commitStarts: make(map[string]int64),
+		changeStarts: make(map[string]int64),
+		pullHeads:    make(map[string][]string),
+	}
+
+	var refTips []string
+	if out, err := exec.Command("git", "-C", repoPath, "for-each-ref", "--format=%(objectname) %(refname)",
+		"refs/heads", "refs/remotes", "refs/pull").Output(); err == nil {
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			hash, ref, ok := strings.Cut(line, " ")
+			if !ok {
+				continue
+			}
+			refTips = append(refTips, hash)
+			if m := pullRefRe.FindStringSubmatch(ref); m != nil {
+				n := m[1] + m[2] + m[3]
+				idx.pullHeads[n] = append(idx.pullHeads[n], hash)
+			}
 		}
-		lines := strings.Split(strings.TrimSpace(string(branchOut)), "\n")
-		if len(lines) == 0 || lines[0] == "" {
+	}
+
+	args := []string{"-C", repoPath, "log", "--all", "--reflog", "-p",
+		"--since=" + git.LogDate(since), "--until=" + git.LogDate(until),
+		"--format=commit %H %P %ct%n%(trailers:key=Change-Id,valueonly,separator=%x2C)", "--not"}
+	patches, err := exec.Command("git", append(append(args, trunk...), "--")...).Output()
+	if err != nil || len(patches) == 0 {
+		return idx
+	}
+	var order []string
+	var pendingChange bool
+	var current string
+	for _, line := range strings.Split(string(patches), "\n") {
+		if rest, ok := strings.CutPrefix(line, "commit "); ok {
+			fields := strings.Fields(rest)
+			if len(fields) < 2 {
+				continue
+			}
+			ts, err := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+			if err != nil {
+				continue
+			}
+			oc := &offTrunkCommit{committed: ts}
+			if len(fields) > 2 {
+				oc.parent = fields[1]
+			}
+			current = fields[0]
+			idx.commits[current] = oc
+			order = append(order, current)
+			pendingChange = true
 			continue
 		}
-		oldestCT, err := strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64)
-		if err != nil {
+		if pendingChange {
+			// The line after the header holds the Change-Id trailers.
+			pendingChange = false
+			for _, id := range strings.Split(line, ",") {
+				if id = strings.TrimSpace(id); id == "" {
+					continue
+				}
+				ts := idx.commits[current].committed
+				if s, ok := idx.changeStarts[id]; !ok || ts < s {
+					idx.changeStarts[id] = ts
+				}
+			}
+		}
+	}
+	for _, hash := range order {
+		if p, ok := idx.commits[idx.commits[hash].parent]; ok {
+			p.hasChild = true
+		}
+	}
+	for _, hash := range order {
+		idx.branchOf(hash)
+	}
+
+	// Each branch commit, for rebase merges.
+	for hash, pid := range patchIDs(repoPath, patches) {
+		if oc, ok := idx.commits[hash]; ok {
+			if s, ok := idx.commitStarts[pid]; !ok || oc.start < s {
+				idx.commitStarts[pid] = oc.start
+			}
+		}
+	}
+
+	// Whole branches, for squash merges.
+	var in strings.Builder
+	seen := make(map[string]bool)
+	addTip := func(hash string) {
+		oc, ok := idx.commits[hash]
+		if !ok || oc.base == "" || seen[hash] {
+			return
+		}
+		seen[hash] = true
+		in.WriteString(hash + " " + oc.base + "\n")
+	}
+	for _, hash := range refTips {
+		addTip(hash)
+	}
+	for _, hash := range order {
+		if !idx.commits[hash].hasChild {
+			addTip(hash)
+		}
+	}
+	if in.Len() > 0 {
+		for tip, pid := range diffPatchIDs(repoPath, in.String()) {
+			start := idx.commits[tip].start
+			if s, ok := idx.branchStarts[pid]; !ok || start < s {
+				idx.branchStarts[pid] = start
+			}
+		}
+	}
+	return idx
+}
+
+// branchOf fills in the start and base of hash's branch, walking first
+// parents until a commit already resolved or one on a main branch.
+func (idx *leadIndex) branchOf(hash string) {
+	var chain []string
+	for h := hash; ; {
+		oc, ok := idx.commits[h]
+		if !ok {
+			break
+		}
+		if oc.start != 0 {
+			chain = append(chain, h)
+			break
+		}
+		chain = append(chain, h)
+		h = oc.parent
+	}
+	// Resolve from the oldest commit of the chain back to hash.
+	var start int64
+	var base string
+	for i := len(chain) - 1; i >= 0; i-- {
+		oc := idx.commits[chain[i]]
+		if oc.start != 0 {
+			start, base = oc.start, oc.base
 			continue
 		}
+		if i == len(chain)-1 {
+			start, base = oc.committed, oc.parent
+		}
+		if oc.committed < start {
+			start = oc.committed
+		}
+		oc.start, oc.base = start, base
+	}
+}
 
-		leadDays := float64(mergeCT-oldestCT) / 86400.0
-		if leadDays < 0 {
-			leadDays = 0
+// oldest returns the earliest commit time in the revision range.
+func (e *leadEstimator) oldest(rng string) (int64, bool) {
+	return oldestCommit(e.repoPath, rng)
+}
+
+// oldestCommit returns the earliest commit time among the given revisions
+// (a range, or tips followed by --not and exclusions).
+func oldestCommit(repoPath string, revs ...string) (int64, bool) {
+	args := append(append([]string{"-C", repoPath, "log", "--format=%ct"}, revs...), "--")
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return 0, false
+	}
+	var first int64
+	for _, f := range strings.Fields(string(out)) {
+		if t, err := strconv.ParseInt(f, 10, 64); err == nil && (first == 0 || t < first) {
+			first = t
 		}
-		days = append(days, leadDays)
 	}
+	return first, first != 0
+}
+
+// diffPatchIDs diffs each line of git diff-tree --stdin input (a commit, or
+// a commit and the base to diff it against) and maps each commit to the
+// patch-id of its diff.
+func diffPatchIDs(repoPath, input string) map[string]string {
+	cmd := exec.Command("git", "-C", repoPath, "diff-tree", "--stdin", "-p")
+	cmd.Stdin = strings.NewReader(input)
+	diffs, err := cmd.Output()
+	if err != nil {
+		return map[string]string{}
+	}
+	return patchIDs(repoPath, diffs)
+}
 
-	return LeadTime{
-		MedianDays: median(days),
-		Samples:    len(days),
-		MainBranch: main,
-	}, nil
+// patchIDs runs git patch-id over a stream of patches and maps each commit
+// to the stable patch-id of its patch. Commits without a patch are left out.
+func patchIDs(repoPath string, patches []byte) map[string]string {
+	cmd := exec.Command("git", "-C", repoPath, "patch-id", "--stable")
+	cmd.Stdin = strings.NewReader(string(patches))
+	out, err := cmd.Output()
+	ids := make(map[string]string)
+	if err != nil {
+		return ids
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if m := patchLineRe.FindStringSubmatch(line); m != nil {
+			ids[m[2]] = m[1]
+		}
+	}
+	return ids
 }
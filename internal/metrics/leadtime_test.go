@@ -3,6 +3,7 @@ package metrics
 import (
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
 	"time"
 )
@@ -57,7 +58,7 @@ func TestLeadTime(t *testing.T) {
 	since := now.Add(-30 * 24 * time.Hour)
 	until := now.Add(24 * time.Hour)
 
-	lt, err := ComputeLeadTime(r.path, "dev@example.com", since, until)
+	lt, err := ComputeLeadTime(r.path, "dev@example.com", since, until, nil, nil)
 	if err != nil {
 		t.Fatalf("ComputeLeadTime: %v", err)
 	}
@@ -72,3 +73,198 @@ func TestLeadTime(t *testing.T) {
 		t.Errorf("expected MainBranch=main, got %q", lt.MainBranch)
 	}
 }
+
+func TestLeadTimeSquashAndTrailers(t *testing.T) {
+	r := newTestRepo(t)
+	author := "Dev User <dev@example.com>"
+	base := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	r.writeFile("README.md", "init")
+	r.commit("init", author, base)
+
+	// Squash merge of pull request #12, whose head was fetched: started on
+	// day 1, landed on day 4.
+	run(t, r.path, "git", "checkout", "-q", "-b", "pr12")
+	r.writeFile("a.go", "package a")
+	r.commit("a: start", author, base.Add(day))
+	r.writeFile("a.go", "package a\n// more")
+	r.commit("a: finish", author, base.Add(2*day))
+	run(t, r.path, "git", "update-ref", "refs/pull/12/head", "pr12")
+	run(t, r.path, "git", "checkout", "-q", "main")
+	run(t, r.path, "git", "merge", "-q", "--squash", "pr12")
+	r.commit("Add a (#12)", author, base.Add(4*day))
+
+	// Squash merge without a number; the branch is still around: started on
+	// day 5, landed on day 7.
+	run(t, r.path, "git", "checkout", "-q", "-b", "topic")
+	r.writeFile("b.go", "package b")
+	r.commit("b: start", author, base.Add(5*day))
+	r.writeFile("b.go", "package b\n// more")
+	r.commit("b: finish", author, base.Add(6*day))
+	run(t, r.path, "git", "checkout", "-q", "main")
+	run(t, r.path, "git", "merge", "-q", "--squash", "topic")
+	r.commit("Add b", author, base.Add(7*day))
+
+	// Gerrit change: written on day 8, submitted on day 9.8.
+	r.writeFile("c.go", "package c")
+	cmd := exec.Command("git", "-C", r.path, "commit", "-q", "-m", "Add c\n\nChange-Id: I0123456789abcdef")
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Dev User", "GIT_AUTHOR_EMAIL=dev@example.com",
+		"GIT_COMMITTER_NAME=Dev User", "GIT_COMMITTER_EMAIL=dev@example.com",
+		"GIT_AUTHOR_DATE="+base.Add(8*day).Format(time.RFC3339),
+		"GIT_COMMITTER_DATE="+base.Add(9*day+19*time.Hour).Format(time.RFC3339))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("commit: %v\n%s", err, out)
+	}
+
+	lt, err := ComputeLeadTime(r.path, "dev@example.com", base.Add(-day), base.Add(14*day), nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeLeadTime: %v", err)
+	}
+
+	want := map[string]float64{LeadMethodPRNumber: 3, LeadMethodPatchID: 2, LeadMethodTrailer: 1.8}
+	if lt.Samples != len(want) {
+		t.Fatalf("Samples=%d, want %d: %+v", lt.Samples, len(want), lt.Changes)
+	}
+	for _, c := range lt.Changes {
+		days, ok := want[c.Method]
+		if !ok {
+			t.Errorf("unexpected method %q for %s", c.Method, c.Commit)
+			continue
+		}
+		if c.Days < days-0.01 || c.Days > days+0.01 {
+			t.Errorf("%s: %.2f days, want %.1f", c.Method, c.Days, days)
+		}
+	}
+	// The initial commit on main has no branch behind it.
+	if lt.Unmatched != 1 {
+		t.Errorf("Unmatched=%d, want 1", lt.Unmatched)
+	}
+}
+
+func TestLeadTimeRebaseMerge(t *testing.T) {
+	r := newTestRepo(t)
+	author := "Dev User <dev@example.com>"
+	base := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	r.writeFile("README.md", "init")
+	r.commit("init", author, base)
+
+	// A branch started on day 1 and rebased onto main on day 4: every
+	// rebased commit's change started with the branch's first commit.
+	run(t, r.path, "git", "checkout", "-q", "-b", "feature")
+	r.writeFile("a.go", "package a")
+	r.commit("a: start", author, base.Add(day))
+	r.writeFile("b.go", "package b")
+	r.commit("b: more", author, base.Add(3*day))
+	run(t, r.path, "git", "checkout", "-q", "main")
+	r.writeFile("README.md", "moved on")
+	r.commit("docs", author, base.Add(2*day))
+	env := []string{
+		"GIT_COMMITTER_NAME=Dev User", "GIT_COMMITTER_EMAIL=dev@example.com",
+		"GIT_COMMITTER_DATE=" + base.Add(4*day).Format(time.RFC3339),
+	}
+	runEnv(t, env, "git", "-C", r.path, "cherry-pick", "main..feature")
+
+	lt, err := ComputeLeadTime(r.path, "dev@example.com", base.Add(-day), base.Add(14*day), nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeLeadTime: %v", err)
+	}
+	if lt.Samples != 2 {
+		t.Fatalf("Samples=%d, want 2: %+v", lt.Samples, lt.Changes)
+	}
+	for _, c := range lt.Changes {
+		if c.Method != LeadMethodPatchID || c.Days < 2.99 || c.Days > 3.01 {
+			t.Errorf("%s: %s %.2f days, want patch-id 3", c.Commit, c.Method, c.Days)
+		}
+	}
+}
+
+func TestLeadTimeExcludeCommits(t *testing.T) {
+	r := newTestRepo(t)
+	author := "Dev User <dev@example.com>"
+	base := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	r.writeFile("README.md", "init")
+	r.commit("init", author, base)
+	run(t, r.path, "git", "checkout", "-q", "-b", "feature")
+	r.writeFile("a.go", "package a")
+	r.commit("a: start", author, base.Add(day))
+	run(t, r.path, "git", "checkout", "-q", "main")
+	env := []string{
+		"GIT_AUTHOR_NAME=Dev User", "GIT_AUTHOR_EMAIL=dev@example.com",
+		"GIT_COMMITTER_NAME=Dev User", "GIT_COMMITTER_EMAIL=dev@example.com",
+		"GIT_AUTHOR_DATE=" + base.Add(3*day).Format(time.RFC3339),
+		"GIT_COMMITTER_DATE=" + base.Add(3*day).Format(time.RFC3339),
+	}
+	runEnv(t, env, "git", "-C", r.path, "merge", "--no-ff", "-m", "Merge feature", "feature")
+
+	since, until := base.Add(-day), base.Add(14*day)
+	lt, err := ComputeLeadTime(r.path, "dev@example.com", since, until, nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeLeadTime: %v", err)
+	}
+	if lt.Samples != 1 || lt.Unmatched != 1 {
+		t.Fatalf("Samples=%d Unmatched=%d, want 1 and 1", lt.Samples, lt.Unmatched)
+	}
+
+	// Leaving out the merge and the initial commit leaves nothing.
+	lt, err = ComputeLeadTime(r.path, "dev@example.com", since, until, nil, []string{revParse(t, r, "HEAD"), revParse(t, r, "HEAD~1")[:7]})
+	if err != nil {
+		t.Fatalf("ComputeLeadTime: %v", err)
+	}
+	if lt.Samples != 0 || lt.Unmatched != 0 {
+		t.Errorf("excluded: Samples=%d Unmatched=%d, want 0 and 0", lt.Samples, lt.Unmatched)
+	}
+}
+
+func TestLeadTimeBranchWindow(t *testing.T) {
+	r := newTestRepo(t)
+	author := "Dev User <dev@example.com>"
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	squash := func(branch, file string, started, landed time.Duration, more ...time.Duration) {
+		run(t, r.path, "git", "checkout", "-q", "-b", branch, "main")
+		r.writeFile(file, "package x")
+		r.commit(branch+": start", author, base.Add(started))
+		for i, at := range more {
+			r.writeFile(file, "package x\n"+strings.Repeat("// more\n", i+1))
+			r.commit(branch+": more", author, base.Add(at))
+		}
+		run(t, r.path, "git", "checkout", "-q", "main")
+		run(t, r.path, "git", "merge", "-q", "--squash", branch)
+		r.commit("Add "+branch, author, base.Add(landed))
+	}
+	leadTime := func(since, until time.Duration) LeadTime {
+		t.Helper()
+		lt, err := ComputeLeadTime(r.path, "dev@example.com", base.Add(since), base.Add(until), nil, nil)
+		if err != nil {
+			t.Fatalf("ComputeLeadTime: %v", err)
+		}
+		return lt
+	}
+
+	r.writeFile("README.md", "init")
+	r.commit("init", author, base)
+	// A branch started on day 5, worked on again on day 100 and squashed on
+	// day 110.
+	squash("slow", "slow.go", 5*day, 110*day, 100*day)
+
+	// Found when the window's lookback reaches its start...
+	if lt := leadTime(95*day, 120*day); lt.Samples != 1 || lt.Changes[0].Days < 104.99 || lt.Changes[0].Days > 105.01 {
+		t.Errorf("lookback to day 5: %+v, want one change of 105 days", lt)
+	}
+	// ...and not when it doesn't: the branch isn't read back to its fork.
+	if lt := leadTime(105*day, 120*day); lt.Samples != 0 || lt.Unmatched != 1 {
+		t.Errorf("lookback to day 15: %+v, want one unmatched change", lt)
+	}
+
+	// Each call reads the branches as they are now.
+	squash("fast", "fast.go", 111*day, 113*day)
+	if lt := leadTime(105*day, 120*day); lt.Samples != 1 || lt.Unmatched != 1 || lt.Changes[0].Days < 1.99 || lt.Changes[0].Days > 2.01 {
+		t.Errorf("after a new branch: %+v, want a 2-day change and an unmatched one", lt)
+	}
+}
@@ -0,0 +1,211 @@
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RepoBundle is the opt-in metrics of a single repository.
+type RepoBundle struct {
+	Path   string
+	Bundle Bundle
+}
+
+// MergeBundles combines per-repo metric bundles into one: commit size
+// distributions are summed, cadence and lead time medians are recomputed
+// from the pooled samples, deploys are counted across repos, churn pools
+// added and churned lines, and reverts pool commits and samples.
+// Diagnostics are concatenated; commit sizes bucketed differently can't be
+// pooled and are left out with an error diagnostic. A metric
+// is present in the result when it is present in any input. Selection,
+// window and legacy flag come from the first bundle.
+func MergeBundles(bundles []Bundle) Bundle {
+	if len(bundles) == 0 {
+		return Bundle{}
+	}
+	out := Bundle{
+		Selection:       bundles[0].Selection,
+		Since:           bundles[0].Since,
+		Until:           bundles[0].Until,
+		LegacyBenchmark: bundles[0].LegacyBenchmark,
+	}
+	var sizes []CommitSizeDistribution
+	var cadences []Cadence
+	var leads []LeadTime
+	var deploys []DeployLeadTime
+	var churns []Churn
+	var reverts []Reverts
+	for _, b := range bundles {
+		out.Diagnostics = append(out.Diagnostics, b.Diagnostics...)
+		if b.CommitSize != nil {
+			sizes = append(sizes, *b.CommitSize)
+		}
+		if b.Cadence != nil {
+			cadences = append(cadences, *b.Cadence)
+		}
+		if b.LeadTime != nil {
+			leads = append(leads, *b.LeadTime)
+		}
+		if b.Deploy != nil {
+			deploys = append(deploys, *b.Deploy)
+		}
+		if b.Churn != nil {
+			churns = append(churns, *b.Churn)
+		}
+		if b.Reverts != nil {
+			reverts = append(reverts, *b.Reverts)
+		}
+	}
+	if len(sizes) > 0 {
+		if d, err := MergeCommitSize(sizes); err == nil {
+			out.CommitSize = &d
+		} else {
+			out.Diagnostics = append(out.Diagnostics, Diagnostic{Metric: MetricCommitSize, Status: StatusError, Reason: err.Error()})
+		}
+	}
+	if len(cadences) > 0 {
+		c := MergeCadence(cadences)
+		out.Cadence = &c
+	}
+	if len(leads) > 0 {
+		lt := MergeLeadTime(leads)
+		out.LeadTime = &lt
+	}
+	if len(deploys) > 0 {
+		d := MergeDeployLeadTime(deploys)
+		out.Deploy = &d
+	}
+	if len(churns) > 0 {
+		ch := MergeChurn(churns)
+		out.Churn = &ch
+	}
+	if len(reverts) > 0 {
+		r := MergeReverts(reverts)
+		out.Reverts = &r
+	}
+	return out
+}
+
+// MergeCommitSize pools the commits of several distributions, so
+// percentiles are over every commit. The distributions must share their
+// bucket edges: it fails when repos set different SizeBucketsConfigKey.
+func MergeCommitSize(ds []CommitSizeDistribution) (CommitSizeDistribution, error) {
+	if len(ds) == 0 {
+		return CommitSizeDistribution{}, nil
+	}
+	var commits []SizedCommit
+	for _, d := range ds {
+		if !slices.Equal(d.Edges, ds[0].Edges) {
+			return CommitSizeDistribution{}, fmt.Errorf("repos bucket commit sizes differently (%s vs %s); set --size-buckets to pool them", joinInts(ds[0].Edges), joinInts(d.Edges))
+		}
+		commits = append(commits, d.commits...)
+	}
+	return newCommitSize(ds[0].Edges, commits), nil
+}
+
+// joinInts formats edges the way SizeBucketsConfigKey takes them.
+func joinInts(edges []int) string {
+	parts := make([]string, len(edges))
+	for i, e := range edges {
+		parts[i] = fmt.Sprint(e)
+	}
+	return strings.Join(parts, ",")
+}
+
+// MergeCadence pools the intervals of several cadences and recomputes the
+// median. Intervals are only measured within a repo, never across repos.
+func MergeCadence(cs []Cadence) Cadence {
+	var out Cadence
+	var branches []string
+	for _, c := range cs {
+		out.intervals = append(out.intervals, c.intervals...)
+		branches = append(branches, c.MainBranch)
+	}
+	out.Samples = len(out.intervals)
+	out.MedianDaysBetween = median(out.intervals)
+	out.MainBranch = mergeBranchNames(branches)
+	return out
+}
+
+// MergeLeadTime pools the lead times of several repos and recomputes the
+// median.
+func MergeLeadTime(ls []LeadTime) LeadTime {
+	var out LeadTime
+	var branches []string
+	var changes []LeadTimeSample
+	for _, l := range ls {
+		changes = append(changes, l.Changes...)
+		out.Unmatched += l.Unmatched
+		branches = append(branches, l.MainBranch)
+	}
+	out.setChanges(changes)
+	out.MainBranch = mergeBranchNames(branches)
+	return out
+}
+
+// MergeDeployLeadTime pools the commit-to-deploy lead times of several repos
+// and adds up their deploys over the shared period.
+func MergeDeployLeadTime(ds []DeployLeadTime) DeployLeadTime {
+	out := DeployLeadTime{Source: ds[0].Source, Weeks: ds[0].Weeks}
+	for _, d := range ds {
+		out.days = append(out.days, d.days...)
+		out.Deploys += d.Deploys
+	}
+	out.Samples = len(out.days)
+	out.MedianDays = median(out.days)
+	if out.Weeks > 0 {
+		out.PerWeek = float64(out.Deploys) / out.Weeks
+	}
+	return out
+}
+
+// MergeChurn pools added and churned lines; the ratio is recomputed from the
+// totals, so large repos weigh more than small ones.
+func MergeChurn(cs []Churn) Churn {
+	var out Churn
+	for _, c := range cs {
+		if c.WindowDays > out.WindowDays {
+			out.WindowDays = c.WindowDays
+		}
+		out.AddedLines += c.AddedLines
+		out.ChurnedLines += c.ChurnedLines
+	}
+	if out.AddedLines > 0 {
+		out.Ratio = float64(out.ChurnedLines) / float64(out.AddedLines)
+	}
+	return out
+}
+
+// MergeReverts pools the revert and fix samples of several repos.
+func MergeReverts(rs []Reverts) Reverts {
+	var out Reverts
+	for _, r := range rs {
+		if r.WindowDays > out.WindowDays {
+			out.WindowDays = r.WindowDays
+		}
+		out.Commits += r.Commits
+		out.revertDays = append(out.revertDays, r.revertDays...)
+		out.fixDays = append(out.fixDays, r.fixDays...)
+	}
+	out.setRates()
+	return out
+}
+
+// mergeBranchNames joins the distinct non-empty main branch names, e.g.
+// "main" or "main, master". Each name may itself be a joined list from a
+// repo with several main branches. Empty when no repo has a main branch.
+func mergeBranchNames(names []string) string {
+	var distinct []string
+	seen := make(map[string]bool)
+	for _, joined := range names {
+		for _, n := range strings.Split(joined, ", ") {
+			if n == "" || seen[n] {
+				continue
+			}
+			seen[n] = true
+			distinct = append(distinct, n)
+		}
+	}
+	return strings.Join(distinct, ", ")
+}
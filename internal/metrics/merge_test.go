@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMergeBundles(t *testing.T) {
+	api := Bundle{
+		CommitSize: sized(5, 20, 30, 600),
+		Cadence:    &Cadence{intervals: []float64{1, 1, 1}, Samples: 3, MedianDaysBetween: 1, MainBranch: "main"},
+		LeadTime:   &LeadTime{Changes: []LeadTimeSample{{Commit: "a1", Days: 2, Method: LeadMethodMerge}}, Samples: 1, MedianDays: 2, MainBranch: "main"},
+		Churn:      &Churn{WindowDays: 30, AddedLines: 100, ChurnedLines: 10, Ratio: 0.1},
+	}
+	web := Bundle{
+		CommitSize: sized(50, 100, 200, 300),
+		Cadence:    &Cadence{intervals: []float64{5, 6}, Samples: 2, MedianDaysBetween: 5.5, MainBranch: "master"},
+		Churn:      &Churn{WindowDays: 30, AddedLines: 900, ChurnedLines: 450, Ratio: 0.5},
+	}
+
+	got := MergeBundles([]Bundle{api, web})
+
+	if got.CommitSize == nil || got.CommitSize.Total != 8 || !slices.Equal(got.CommitSize.Counts, []int{1, 3, 3, 1}) {
+		t.Errorf("CommitSize=%+v, want counts summed", got.CommitSize)
+	} else if got.CommitSize.P50 != 50 || got.CommitSize.Largest[0].Lines() != 600 {
+		t.Errorf("CommitSize P50=%d largest=%+v, want 50 and the 600-line commit", got.CommitSize.P50, got.CommitSize.Largest[0])
+	}
+	// Pooled intervals {1,1,1,5,6}: the median is 1, not the mean of the
+	// per-repo medians (3.25).
+	if got.Cadence == nil || got.Cadence.Samples != 5 || got.Cadence.MedianDaysBetween != 1 {
+		t.Errorf("Cadence=%+v, want median 1 over 5 pooled samples", got.Cadence)
+	}
+	if got.Cadence.MainBranch != "main, master" {
+		t.Errorf("MainBranch=%q, want %q", got.Cadence.MainBranch, "main, master")
+	}
+	// Only api has lead time; it must still come through.
+	if got.LeadTime == nil || got.LeadTime.Samples != 1 || got.LeadTime.MedianDays != 2 {
+		t.Errorf("LeadTime=%+v, want api's value", got.LeadTime)
+	}
+	// Pooled churn: 460 / 1000, not the mean of ratios (0.3).
+	if got.Churn == nil || math.Abs(got.Churn.Ratio-0.46) > 1e-9 {
+		t.Errorf("Churn=%+v, want ratio 0.46", got.Churn)
+	}
+}
+
+func TestMergeBundlesEmpty(t *testing.T) {
+	got := MergeBundles([]Bundle{{}, {}})
+	if got.CommitSize != nil || got.Cadence != nil || got.LeadTime != nil || got.Churn != nil {
+		t.Errorf("metrics absent from every repo must stay nil: %+v", got)
+	}
+}
+
+func TestMergeCommitSizeDifferentEdges(t *testing.T) {
+	web := newCommitSize([]int{5, 50}, []SizedCommit{{Added: 3}, {Added: 40}})
+	got := MergeBundles([]Bundle{{CommitSize: sized(20, 30)}, {CommitSize: &web}})
+	if got.CommitSize != nil {
+		t.Errorf("CommitSize=%+v, want nil: the repos' buckets differ", got.CommitSize)
+	}
+	if errs := Errors(got.Diagnostics); len(errs) != 1 || errs[0].Metric != MetricCommitSize || !strings.Contains(errs[0].Reason, "10,100,500 vs 5,50") {
+		t.Errorf("Diagnostics=%+v, want one commit-size error naming both edges", got.Diagnostics)
+	}
+
+	// Same edges pool as before.
+	if _, err := MergeCommitSize([]CommitSizeDistribution{*sized(1), *sized(2)}); err != nil {
+		t.Errorf("MergeCommitSize: unexpected error %v", err)
+	}
+}
+
+// sized builds a default-bucketed distribution of commits adding n lines each.
+func sized(ns ...int) *CommitSizeDistribution {
+	var commits []SizedCommit
+	for _, n := range ns {
+		commits = append(commits, SizedCommit{Added: n})
+	}
+	d := newCommitSize(DefaultSizeEdges, commits)
+	return &d
+}
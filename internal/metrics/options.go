@@ -10,9 +10,25 @@ type Selection struct {
 	Cadence    bool
 	LeadTime   bool
 	Churn      bool
+	Reverts    bool
+	Deploy     bool // lead time to deploy; set with LeadTime when a deploy source is given
 }
 
-var validMetricNames = []string{"commit-size", "cadence", "lead-time", "churn"}
+// Metric names as used by --metrics and in diagnostics.
+const (
+	MetricCommitSize = "commit-size"
+	MetricCadence    = "cadence"
+	MetricLeadTime   = "lead-time"
+	MetricChurn      = "churn"
+	MetricReverts    = "reverts"
+	MetricDeploy     = "deploy"
+	MetricDORA       = "dora"
+	MetricBaseline   = "baseline"
+	MetricAnalyze    = "analyze"
+	MetricExplain    = "explain"
+)
+
+var validMetricNames = []string{MetricCommitSize, MetricCadence, MetricLeadTime, MetricChurn, MetricReverts}
 
 func ParseSelection(raw string) (Selection, error) {
 	var s Selection
@@ -21,19 +37,21 @@ func ParseSelection(raw string) (Selection, error) {
 		return s, nil
 	}
 	if raw == "all" {
-		return Selection{CommitSize: true, Cadence: true, LeadTime: true, Churn: true}, nil
+		return Selection{CommitSize: true, Cadence: true, LeadTime: true, Churn: true, Reverts: true}, nil
 	}
 	for _, part := range strings.Split(raw, ",") {
 		name := strings.TrimSpace(part)
 		switch name {
-		case "commit-size":
+		case MetricCommitSize:
 			s.CommitSize = true
-		case "cadence":
+		case MetricCadence:
 			s.Cadence = true
-		case "lead-time":
+		case MetricLeadTime:
 			s.LeadTime = true
-		case "churn":
+		case MetricChurn:
 			s.Churn = true
+		case MetricReverts:
+			s.Reverts = true
 		default:
 			return Selection{}, fmt.Errorf("unknown metric %q (valid: %s, or 'all')", name, strings.Join(validMetricNames, ", "))
 		}
@@ -42,5 +60,5 @@ func ParseSelection(raw string) (Selection, error) {
 }
 
 func (s Selection) Any() bool {
-	return s.CommitSize || s.Cadence || s.LeadTime || s.Churn
+	return s.CommitSize || s.Cadence || s.LeadTime || s.Deploy || s.Churn || s.Reverts
 }
@@ -12,15 +12,17 @@ func TestParseSelection(t *testing.T) {
 		wantCad      bool
 		wantLT       bool
 		wantChurn    bool
+		wantReverts  bool
 		wantErr      bool
 		wantErrMatch string
 	}{
 		{raw: ""},
-		{raw: "all", wantCS: true, wantCad: true, wantLT: true, wantChurn: true},
+		{raw: "all", wantCS: true, wantCad: true, wantLT: true, wantChurn: true, wantReverts: true},
 		{raw: "churn", wantChurn: true},
 		{raw: "commit-size,cadence", wantCS: true, wantCad: true},
 		{raw: "lead-time,churn,cadence,commit-size", wantCS: true, wantCad: true, wantLT: true, wantChurn: true},
 		{raw: " churn , lead-time ", wantChurn: true, wantLT: true},
+		{raw: "reverts", wantReverts: true},
 		{raw: "foo", wantErr: true, wantErrMatch: "foo"},
 		{raw: "churn,bogus", wantErr: true, wantErrMatch: "bogus"},
 	}
@@ -51,6 +53,9 @@ func TestParseSelection(t *testing.T) {
 			if sel.Churn != tc.wantChurn {
 				t.Errorf("Churn: got %v want %v", sel.Churn, tc.wantChurn)
 			}
+			if sel.Reverts != tc.wantReverts {
+				t.Errorf("Reverts: got %v want %v", sel.Reverts, tc.wantReverts)
+			}
 		})
 	}
 }
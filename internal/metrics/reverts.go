@@ -0,0 +1,299 @@
+package metrics
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+)
+
+// Reverts measures how often the author's commits in a period had to be
+// undone: reverted outright, or fixed within the window by a fix-type commit
+// or a hotfix branch changing lines they added. A commit both reverted and
+// fixed counts as reverted.
+type Reverts struct {
+	Commits            int     `json:"commits"`  // the author's commits in the period
+	Reverted           int     `json:"reverted"` // of those, later reverted
+	Fixed              int     `json:"fixed"`    // of those, fixed within the window
+	RevertRate         float64 `json:"revert_rate"`
+	FixRate            float64 `json:"fix_rate"`
+	MedianDaysToRevert float64 `json:"median_days_to_revert"`
+	MedianDaysToFix    float64 `json:"median_days_to_fix"`
+	WindowDays         int     `json:"window_days"`
+
+	revertDays []float64 // raw samples, kept so repos can be pooled
+	fixDays    []float64
+}
+
+// setRates derives the rates and medians from the counts and samples.
+func (r *Reverts) setRates() {
+	r.Reverted, r.Fixed = len(r.revertDays), len(r.fixDays)
+	r.MedianDaysToRevert = median(r.revertDays)
+	r.MedianDaysToFix = median(r.fixDays)
+	r.RevertRate, r.FixRate = 0, 0
+	if r.Commits > 0 {
+		r.RevertRate = float64(r.Reverted) / float64(r.Commits)
+		r.FixRate = float64(r.Fixed) / float64(r.Commits)
+	}
+}
+
+var (
+	revertSubjectRe = regexp.MustCompile(`^Revert "(.+)"$`)
+	fixSubjectRe    = regexp.MustCompile(`(?i)^(fix|bugfix|hotfix)(\([^)]*\))?!?:`)
+	hunkRe          = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+`)
+	blameRe         = regexp.MustCompile(`^([0-9a-f]{40}) \d+ \d+`)
+)
+
+// ownCommit is one of the author's commits in the period.
+type ownCommit struct {
+	at      int64
+	subject string
+}
+
+// ComputeReverts finds which of the author's commits in [since, until] were
+// reverted, by a `Revert "..."` commit or one whose body says "This reverts
+// commit <sha>", at any later time; and which were fixed within window by a
+// commit with a fix:, bugfix: or hotfix: subject, or on a branch matching
+// hotfixPattern, that changed lines they added (found with git blame).
+// branches are the --main-branch patterns hotfix branches forked from.
+// Commits git.Analyze leaves out (excludeCommits and the repo's
+// git.ExcludeCommitConfigKey) are not counted.
+func ComputeReverts(repoPath, author string, since, until time.Time, window time.Duration, hotfixPattern string, branches, excludeCommits []string) (Reverts, error) {
+	out := Reverts{WindowDays: int(window.Hours() / 24)}
+	own, err := authorCommits(repoPath, author, since, until, git.ExcludedCommits(repoPath, excludeCommits))
+	if err != nil {
+		return out, err
+	}
+	out.Commits = len(own)
+	if len(own) == 0 {
+		return out, nil
+	}
+
+	reverted, err := findReverted(repoPath, own)
+	if err != nil {
+		return out, err
+	}
+	for sha, at := range reverted {
+		out.revertDays = append(out.revertDays, float64(at-own[sha].at)/86400)
+	}
+
+	fixes, err := fixCommits(repoPath, since, until.Add(window), hotfixPattern, branches)
+	if err != nil {
+		return out, err
+	}
+	fixed := make(map[string]int64)
+	for sha, at := range fixes {
+		for _, origin := range blameChanged(repoPath, sha) {
+			c, ok := own[origin]
+			if _, gone := reverted[origin]; !ok || gone || at <= c.at || at-c.at > int64(window.Seconds()) {
+				continue
+			}
+			if prev, ok := fixed[origin]; !ok || at < prev {
+				fixed[origin] = at
+			}
+		}
+	}
+	for sha, at := range fixed {
+		out.fixDays = append(out.fixDays, float64(at-own[sha].at)/86400)
+	}
+
+	out.setRates()
+	return out, nil
+}
+
+func authorCommits(repoPath, author string, since, until time.Time, excluded func(hash string) bool) (map[string]ownCommit, error) {
+	logOut, err := exec.Command("git", "-C", repoPath, "log", "--no-merges",
+		"--author="+author,
+		"--since="+git.LogDate(since),
+		"--until="+git.LogDate(until),
+		"--format=%H%x1f%ct%x1f%s").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	own := make(map[string]ownCommit)
+	for _, line := range strings.Split(strings.TrimSpace(string(logOut)), "\n") {
+		parts := strings.SplitN(line, "\x1f", 3)
+		if len(parts) < 3 || excluded(parts[0]) {
+			continue
+		}
+		at, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		own[parts[0]] = ownCommit{at: at, subject: parts[2]}
+	}
+	return own, nil
+}
+
+// findReverted maps each of own's commits that was reverted to the time of
+// its first revert. Reverts naming the commit in their body are matched by
+// hash, others by the reverted subject.
+func findReverted(repoPath string, own map[string]ownCommit) (map[string]int64, error) {
+	logOut, err := exec.Command("git", "-C", repoPath, "log", "--all",
+		"--grep=This reverts commit", "--grep=^Revert \"",
+		"--format=%H%x1f%ct%x1f%s%x1f%b%x1e").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	reverted := make(map[string]int64)
+	for _, rec := range strings.Split(string(logOut), "\x1e") {
+		parts := strings.SplitN(strings.TrimSpace(rec), "\x1f", 4)
+		if len(parts) < 4 {
+			continue
+		}
+		at, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		target := ""
+		if m := revertRe.FindStringSubmatch(parts[3]); m != nil {
+			target = resolveCommit(repoPath, m[1])
+		} else if m := revertSubjectRe.FindStringSubmatch(parts[2]); m != nil {
+			// The latest of own's commits with that subject before the revert.
+			var latest int64
+			for sha, c := range own {
+				if c.subject == m[1] && c.at <= at && c.at > latest {
+					target, latest = sha, c.at
+				}
+			}
+		}
+		c, ok := own[target]
+		if !ok || at < c.at {
+			continue
+		}
+		if prev, ok := reverted[target]; !ok || at < prev {
+			reverted[target] = at
+		}
+	}
+	return reverted, nil
+}
+
+// fixCommits returns the non-merge commits in [since, until] with a fix-type
+// subject, and every commit of a hotfix branch, with their commit times.
+func fixCommits(repoPath string, since, until time.Time, hotfixPattern string, mainPatterns []string) (map[string]int64, error) {
+	fixes := make(map[string]int64)
+	// subjects, when set, keeps only commits whose subject matches it.
+	collect := func(subjects *regexp.Regexp, args ...string) error {
+		args = append(append([]string{"-C", repoPath, "log", "--no-merges", "--format=%H%x1f%ct%x1f%s"}, args...), "--")
+		logOut, err := exec.Command("git", args...).Output()
+		if err != nil {
+			return fmt.Errorf("git log: %w", err)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(logOut)), "\n") {
+			parts := strings.SplitN(line, "\x1f", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			if subjects != nil && !subjects.MatchString(parts[2]) {
+				continue
+			}
+			if at, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+				fixes[parts[0]] = at
+			}
+		}
+		return nil
+	}
+
+	// --grep matches any line of the message, so subjects are checked again.
+	if err := collect(fixSubjectRe, "--all", "--since="+git.LogDate(since), "--until="+git.LogDate(until),
+		"--extended-regexp", "--regexp-ignore-case", "--grep=^(fix|bugfix|hotfix)(\\([^)]*\\))?!?:"); err != nil {
+		return nil, err
+	}
+
+	if hotfixPattern != "" {
+		branches, err := findHotfixBranches(repoPath, hotfixPattern, mainPatterns)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range branches {
+			if err := collect(nil, b.revs...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return fixes, nil
+}
+
+// blameChanged returns the commits that last touched the lines commit
+// deleted or rewrote, found by blaming its parent.
+func blameChanged(repoPath, commit string) []string {
+	diff, err := exec.Command("git", "-C", repoPath, "-c", "core.quotePath=false",
+		"diff", "-U0", "--no-color", "--no-renames", commit+"^", commit).Output()
+	if err != nil {
+		return nil // root commit
+	}
+	ranges := make(map[string][]string) // file → -L ranges
+	var order []string
+	file := ""
+	for _, line := range strings.Split(string(diff), "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			file = diffPath(strings.TrimPrefix(line, "--- "))
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			m := hunkRe.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			count := 1
+			if m[2] != "" {
+				count, _ = strconv.Atoi(m[2])
+			}
+			if count == 0 {
+				continue // pure addition
+			}
+			if _, ok := ranges[file]; !ok {
+				order = append(order, file)
+			}
+			ranges[file] = append(ranges[file], "-L", m[1]+",+"+strconv.Itoa(count))
+		}
+	}
+
+	var origins []string
+	for _, f := range order {
+		args := append(append([]string{"-C", repoPath, "blame", "--porcelain"}, ranges[f]...), commit+"^", "--", f)
+		blame, err := exec.Command("git", args...).Output()
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(blame), "\n") {
+			if m := blameRe.FindStringSubmatch(line); m != nil {
+				origins = append(origins, m[1])
+			}
+		}
+	}
+	return origins
+}
+
+// diffPath returns the file a diff's "--- a/path" line names, or "" for
+// /dev/null. Git quotes names holding quotes, backslashes or control
+// characters C-style, and ends names holding spaces with a tab.
+func diffPath(p string) string {
+	p = strings.TrimSuffix(p, "\t")
+	if strings.HasPrefix(p, `"`) {
+		unquoted, err := strconv.Unquote(p)
+		if err != nil {
+			return ""
+		}
+		p = unquoted
+	}
+	if p == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(p, "a/")
+}
+
+// resolveCommit expands an abbreviated hash; "" when it is unknown.
+func resolveCommit(repoPath, rev string) string {
+	if len(rev) == 40 {
+		return rev
+	}
+	out, err := exec.Command("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", rev+"^{commit}").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReverts(t *testing.T) {
+	r := newTestRepo(t)
+	dev := "Dev <dev@example.com>"
+	other := "Other <other@example.com>"
+	day := func(n int) time.Time { return time.Date(2026, 3, 2+n, 12, 0, 0, 0, time.UTC) }
+	at := func(n int) []string {
+		ts := day(n).Format(time.RFC3339)
+		return []string{"GIT_AUTHOR_DATE=" + ts, "GIT_COMMITTER_DATE=" + ts,
+			"GIT_AUTHOR_NAME=Other", "GIT_AUTHOR_EMAIL=other@example.com",
+			"GIT_COMMITTER_NAME=Other", "GIT_COMMITTER_EMAIL=other@example.com"}
+	}
+
+	r.writeFile("base.txt", "base\n")
+	r.commit("init", other, day(0))
+
+	// Four commits by dev, one file each.
+	for i, name := range []string{"a", "b", "c", "d"} {
+		r.writeFile(name+".txt", name+"\n")
+		r.commit(name, dev, day(1+i))
+	}
+	a := revParse(t, r, "HEAD~3")
+
+	// a is reverted with git revert; c by hand, named only in the subject.
+	runEnv(t, at(5), "git", "-C", r.path, "revert", "--no-edit", a)
+	r.writeFile("c.txt", "")
+	r.commit(`Revert "c"`, other, day(6))
+
+	// b gets a fix: commit; d a hotfix branch, merged.
+	r.writeFile("b.txt", "b fixed\n")
+	r.commit("fix(b): handle empty input", other, day(7))
+	run(t, r.path, "git", "checkout", "-q", "-b", "hotfix/d")
+	r.writeFile("d.txt", "d fixed\n")
+	r.commit("patch d", other, day(8))
+	run(t, r.path, "git", "checkout", "-q", "main")
+	runEnv(t, at(8), "git", "-C", r.path, "merge", "--no-ff", "--no-edit", "hotfix/d")
+
+	// A fix: commit that only adds lines doesn't fix anyone's commit.
+	r.writeFile("e.txt", "new\n")
+	r.commit("fix: add missing file", other, day(9))
+
+	since, until := day(0), day(5)
+	got, err := ComputeReverts(r.path, "dev@example.com", since, until, 30*24*time.Hour, "hotfix/*", nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeReverts: %v", err)
+	}
+	if got.Commits != 4 || got.Reverted != 2 || got.Fixed != 2 {
+		t.Fatalf("Commits=%d Reverted=%d Fixed=%d, want 4, 2 and 2", got.Commits, got.Reverted, got.Fixed)
+	}
+	if math.Abs(got.RevertRate-0.5) > 0.001 || math.Abs(got.FixRate-0.5) > 0.001 {
+		t.Errorf("RevertRate=%.2f FixRate=%.2f, want 0.5 each", got.RevertRate, got.FixRate)
+	}
+	// a reverted after 4 days, c after 3; b fixed after 5 days, d after 4.
+	if math.Abs(got.MedianDaysToRevert-3.5) > 0.01 || math.Abs(got.MedianDaysToFix-4.5) > 0.01 {
+		t.Errorf("MedianDaysToRevert=%.2f MedianDaysToFix=%.2f, want 3.5 and 4.5", got.MedianDaysToRevert, got.MedianDaysToFix)
+	}
+
+	// With a 3-day window neither fix is recent enough.
+	short, err := ComputeReverts(r.path, "dev@example.com", since, until, 3*24*time.Hour, "hotfix/*", nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeReverts: %v", err)
+	}
+	if short.Fixed != 0 || short.Reverted != 2 {
+		t.Errorf("3-day window: Fixed=%d Reverted=%d, want 0 and 2", short.Fixed, short.Reverted)
+	}
+
+	merged := MergeReverts([]Reverts{got, {Commits: 4, WindowDays: 30}})
+	if merged.Commits != 8 || math.Abs(merged.RevertRate-0.25) > 0.001 {
+		t.Errorf("merged: Commits=%d RevertRate=%.2f, want 8 and 0.25", merged.Commits, merged.RevertRate)
+	}
+}
+
+func TestRevertsExcludeCommits(t *testing.T) {
+	r := newTestRepo(t)
+	dev := "Dev <dev@example.com>"
+	day := func(n int) time.Time { return time.Date(2026, 3, 2+n, 12, 0, 0, 0, time.UTC) }
+
+	r.writeFile("a.txt", "a\n")
+	r.commit("a", dev, day(0))
+	r.writeFile("b.txt", "b\n")
+	r.commit("b", dev, day(1))
+	b := revParse(t, r, "HEAD")
+	r.writeFile("b.txt", "")
+	r.commit(`Revert "b"`, dev, day(2))
+
+	since, until := day(-1), day(1)
+	got, err := ComputeReverts(r.path, "dev@example.com", since, until, 30*24*time.Hour, "", nil, nil)
+	if err != nil {
+		t.Fatalf("ComputeReverts: %v", err)
+	}
+	if got.Commits != 2 || got.Reverted != 1 {
+		t.Fatalf("Commits=%d Reverted=%d, want 2 and 1", got.Commits, got.Reverted)
+	}
+
+	got, err = ComputeReverts(r.path, "dev@example.com", since, until, 30*24*time.Hour, "", nil, []string{b[:10]})
+	if err != nil {
+		t.Fatalf("ComputeReverts: %v", err)
+	}
+	if got.Commits != 1 || got.Reverted != 0 {
+		t.Errorf("excluded: Commits=%d Reverted=%d, want 1 and 0", got.Commits, got.Reverted)
+	}
+}
+
+func TestBlameChangedQuotedPaths(t *testing.T) {
+	r := newTestRepo(t)
+	dev := "Dev <dev@example.com>"
+	names := []string{"dir/naïve.go", "with space.txt", `quote"and\backslash.txt`, "tab\there.txt"}
+	if err := os.Mkdir(filepath.Join(r.path, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		r.writeFile(name, "one\n")
+	}
+	r.commit("add", dev, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	added := revParse(t, r, "HEAD")
+	for _, name := range names {
+		r.writeFile(name, "two\n")
+	}
+	r.commit("rewrite", dev, time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC))
+
+	// Each rewritten line was last touched by the first commit.
+	got := blameChanged(r.path, revParse(t, r, "HEAD"))
+	if len(got) != len(names) {
+		t.Fatalf("blameChanged = %v, want %s once per file", got, added)
+	}
+	for _, origin := range got {
+		if origin != added {
+			t.Errorf("origin %s, want %s", origin, added)
+		}
+	}
+}
+
+func TestDiffPath(t *testing.T) {
+	for line, want := range map[string]string{
+		"a/plain.go":                    "plain.go",
+		"a/with space.txt\t":            "with space.txt",
+		`"a/dir/na\303\257ve.go"`:       "dir/naïve.go",
+		`"a/quote\"and\\backslash.txt"`: `quote"and\backslash.txt`,
+		`"a/tab\there.txt"`:             "tab\there.txt",
+		"/dev/null":                     "",
+	} {
+		if got := diffPath(line); got != want {
+			t.Errorf("diffPath(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package metrics
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+)
+
+// MinSignificanceWeeks is the number of weekly samples each period needs
+// before the significance test and confidence interval are worth reporting.
+const MinSignificanceWeeks = 6
+
+const (
+	bootstrapIterations = 2000
+	bootstrapConfidence = 0.95
+)
+
+// Significance describes how clearly a change in daily output between two
+// periods stands out from normal week-to-week variation.
+type Significance struct {
+	BeforeWeeks  int     `json:"before_weeks"`
+	AfterWeeks   int     `json:"after_weeks"`
+	Ratio        float64 `json:"ratio"`
+	RatioLow     float64 `json:"ratio_ci_low"`
+	RatioHigh    float64 `json:"ratio_ci_high"`
+	Confidence   float64 `json:"confidence"`
+	U            float64 `json:"mann_whitney_u"`
+	PValue       float64 `json:"p_value"`
+	EffectSize   float64 `json:"effect_size"`
+	Effect       string  `json:"effect"`
+	Insufficient bool    `json:"insufficient_data"`
+}
+
+// Significant reports whether the difference is significant at the 5% level.
+// Always false when there is not enough data.
+func (s Significance) Significant() bool {
+	return !s.Insufficient && s.PValue < 0.05
+}
+
+// WeeklySamples returns one net-lines-per-working-day sample for every ISO
+// week overlapping [stats.Since, stats.Until], including weeks without
+// commits. Weeks whose overlap has no working days are skipped.
+func WeeklySamples(stats git.RepoStats) []float64 {
+	if stats.Since.IsZero() || !stats.Until.After(stats.Since) {
+		return nil
+	}
+	var samples []float64
+	start := startOfWeek(stats.Since)
+	for ws := start; ws.Before(stats.Until); ws = ws.AddDate(0, 0, 7) {
+		from, to := ws, ws.AddDate(0, 0, 7)
+		if from.Before(stats.Since) {
+			from = stats.Since
+		}
+		if to.After(stats.Until) {
+			to = stats.Until
+		}
+		days := weekdaysBetween(from, to)
+		if days == 0 {
+			continue
+		}
+		net := stats.Weekly[git.WeekKey(ws)].Net
+		samples = append(samples, float64(net)/float64(days))
+	}
+	return samples
+}
+
+// CompareSamples tests whether the after samples differ from the before
+// samples. It reports the ratio of mean daily output with a bootstrap
+// confidence interval, a two-sided Mann-Whitney U test, and Cliff's delta
+// as the effect size. The bootstrap uses a fixed seed so reports are
+// reproducible.
+func CompareSamples(before, after []float64) Significance {
+	s := Significance{
+		BeforeWeeks: len(before),
+		AfterWeeks:  len(after),
+		Confidence:  bootstrapConfidence,
+		PValue:      1,
+	}
+	if len(before) == 0 || len(after) == 0 {
+		s.Insufficient = true
+		s.Effect = "unknown"
+		return s
+	}
+	s.Insufficient = len(before) < MinSignificanceWeeks || len(after) < MinSignificanceWeeks
+
+	if mb := mean(before); mb > 0 {
+		s.Ratio = mean(after) / mb
+	}
+	s.RatioLow, s.RatioHigh = bootstrapRatioCI(before, after)
+
+	s.U, s.PValue = mannWhitney(before, after)
+	s.EffectSize = 2*s.U/float64(len(before)*len(after)) - 1
+	s.Effect = effectLabel(s.EffectSize)
+	return s
+}
+
+// bootstrapRatioCI resamples both periods with replacement and returns the
+// percentile interval of mean(after)/mean(before). Resamples whose before
+// mean is not positive are discarded; (0, 0) means no usable resample.
+func bootstrapRatioCI(before, after []float64) (float64, float64) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	ratios := make([]float64, 0, bootstrapIterations)
+	for i := 0; i < bootstrapIterations; i++ {
+		mb := resampleMean(rng, before)
+		if mb <= 0 {
+			continue
+		}
+		ratios = append(ratios, resampleMean(rng, after)/mb)
+	}
+	if len(ratios) == 0 {
+		return 0, 0
+	}
+	sort.Float64s(ratios)
+	alpha := (1 - bootstrapConfidence) / 2
+	return percentile(ratios, alpha), percentile(ratios, 1-alpha)
+}
+
+func resampleMean(rng *rand.Rand, xs []float64) float64 {
+	sum := 0.0
+	for range xs {
+		sum += xs[rng.IntN(len(xs))]
+	}
+	return sum / float64(len(xs))
+}
+
+// mannWhitney returns the U statistic for the after sample and the two-sided
+// p-value from the normal approximation with tie and continuity correction.
+func mannWhitney(before, after []float64) (float64, float64) {
+	type obs struct {
+		v     float64
+		after bool
+	}
+	all := make([]obs, 0, len(before)+len(after))
+	for _, v := range before {
+		all = append(all, obs{v, false})
+	}
+	for _, v := range after {
+		all = append(all, obs{v, true})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	n1, n2 := float64(len(after)), float64(len(before))
+	n := n1 + n2
+	rankSum, tieTerm := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		// Tied values share the average of ranks i+1..j.
+		rank := float64(i+1+j) / 2
+		for k := i; k < j; k++ {
+			if all[k].after {
+				rankSum += rank
+			}
+		}
+		t := float64(j - i)
+		tieTerm += t*t*t - t
+		i = j
+	}
+
+	u := rankSum - n1*(n1+1)/2
+	mu := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return u, 1
+	}
+	diff := math.Abs(u-mu) - 0.5
+	if diff < 0 {
+		diff = 0
+	}
+	z := diff / math.Sqrt(variance)
+	return u, math.Erfc(z / math.Sqrt2)
+}
+
+// effectLabel classifies Cliff's delta using the Romano et al. thresholds.
+func effectLabel(delta float64) string {
+	d := math.Abs(delta)
+	switch {
+	case d < 0.147:
+		return "negligible"
+	case d < 0.33:
+		return "small"
+	case d < 0.474:
+		return "medium"
+	default:
+		return "large"
+	}
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// percentile returns the linearly interpolated p-quantile of sorted xs.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+// startOfWeek returns midnight on the Monday of t's ISO week.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	d := t.AddDate(0, 0, -offset)
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// weekdaysBetween counts Monday-Friday calendar days touched by [from, to).
+func weekdaysBetween(from, to time.Time) int {
+	count := 0
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if wd := day.Weekday(); wd != time.Saturday && wd != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
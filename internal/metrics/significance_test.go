@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+)
+
+func TestCompareSamplesClearIncrease(t *testing.T) {
+	before := []float64{10, 12, 9, 11, 10, 13, 8, 12}
+	after := []float64{40, 38, 45, 42, 39, 41, 44, 37}
+
+	s := CompareSamples(before, after)
+	if s.Insufficient {
+		t.Fatal("8 vs 8 weeks should be enough data")
+	}
+	if !s.Significant() {
+		t.Errorf("PValue=%v, want < 0.05", s.PValue)
+	}
+	if s.Effect != "large" || s.EffectSize != 1 {
+		t.Errorf("Effect=%s (%.2f), want large (1.00)", s.Effect, s.EffectSize)
+	}
+	if s.Ratio < 3.5 || s.Ratio > 4 {
+		t.Errorf("Ratio=%.2f, want ~3.7", s.Ratio)
+	}
+	if !(s.RatioLow <= s.Ratio && s.Ratio <= s.RatioHigh) {
+		t.Errorf("CI [%.2f, %.2f] does not contain ratio %.2f", s.RatioLow, s.RatioHigh, s.Ratio)
+	}
+}
+
+func TestCompareSamplesNoDifference(t *testing.T) {
+	before := []float64{10, 50, 0, 30, 20, 40, 5, 25}
+	after := []float64{25, 5, 40, 20, 30, 0, 50, 10}
+
+	s := CompareSamples(before, after)
+	if s.Significant() {
+		t.Errorf("identical distributions flagged significant (p=%v)", s.PValue)
+	}
+	if s.Effect != "negligible" {
+		t.Errorf("Effect=%s, want negligible", s.Effect)
+	}
+	if math.Abs(s.Ratio-1) > 1e-9 {
+		t.Errorf("Ratio=%v, want 1", s.Ratio)
+	}
+}
+
+func TestCompareSamplesInsufficient(t *testing.T) {
+	s := CompareSamples([]float64{10, 20}, []float64{100, 200, 300})
+	if !s.Insufficient {
+		t.Error("expected Insufficient for 2 vs 3 weeks")
+	}
+	if s.Significant() {
+		t.Error("insufficient data must never be reported significant")
+	}
+
+	s = CompareSamples(nil, []float64{1})
+	if !s.Insufficient || s.PValue != 1 {
+		t.Errorf("empty before: Insufficient=%v PValue=%v", s.Insufficient, s.PValue)
+	}
+}
+
+func TestCompareSamplesDeterministic(t *testing.T) {
+	before := []float64{3, 8, 1, 9, 4, 7}
+	after := []float64{6, 12, 2, 15, 9, 11}
+	a := CompareSamples(before, after)
+	b := CompareSamples(before, after)
+	if a != b {
+		t.Errorf("bootstrap not reproducible: %+v vs %+v", a, b)
+	}
+}
+
+func TestWeeklySamples(t *testing.T) {
+	// Mon 2025-03-03 through Sun 2025-03-16: two full ISO weeks.
+	since := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)
+	stats := git.RepoStats{
+		Since: since,
+		Until: until,
+		Weekly: map[string]git.WeekStats{
+			git.WeekKey(since): {Net: 50},
+		},
+	}
+	got := WeeklySamples(stats)
+	if len(got) != 2 {
+		t.Fatalf("len=%d, want 2 (empty weeks must still be sampled)", len(got))
+	}
+	if got[0] != 10 || got[1] != 0 {
+		t.Errorf("samples=%v, want [10 0]", got)
+	}
+}
@@ -0,0 +1,111 @@
+// gitrespect site: the search box of the navigation bar, over the pages
+// listed in search.js, and sorting for the index tables.
+(function () {
+    var MAX_RESULTS = 20;
+
+    function setupSearch(nav) {
+        var root = nav.getAttribute('data-root') || '';
+        var input = nav.querySelector('.site-search input');
+        var results = nav.querySelector('.site-results');
+        var pages = window.gitrespectSearch || [];
+        var active = -1;
+        if (!input || !results) { return; }
+
+        function close() {
+            results.hidden = true;
+            active = -1;
+        }
+
+        function links() {
+            return results.querySelectorAll('a');
+        }
+
+        function highlight(i) {
+            var all = links();
+            if (!all.length) { return; }
+            active = (i + all.length) % all.length;
+            all.forEach(function (a, j) { a.className = j === active ? 'active' : ''; });
+        }
+
+        function search() {
+            var query = input.value.trim().toLowerCase();
+            results.textContent = '';
+            active = -1;
+            if (!query) { close(); return; }
+            var found = pages.filter(function (p) {
+                return p.name.toLowerCase().indexOf(query) >= 0;
+            }).slice(0, MAX_RESULTS);
+            found.forEach(function (p) {
+                var a = document.createElement('a');
+                a.href = root + p.href;
+                var name = document.createElement('span');
+                name.textContent = p.name;
+                var kind = document.createElement('span');
+                kind.className = 'kind';
+                kind.textContent = p.kind;
+                a.appendChild(name);
+                a.appendChild(kind);
+                results.appendChild(a);
+            });
+            if (!found.length) {
+                var empty = document.createElement('div');
+                empty.className = 'empty';
+                empty.textContent = 'No matches';
+                results.appendChild(empty);
+            }
+            results.hidden = false;
+        }
+
+        input.addEventListener('input', search);
+        input.addEventListener('focus', search);
+        input.addEventListener('keydown', function (e) {
+            if (e.key === 'ArrowDown' || e.key === 'ArrowUp') {
+                e.preventDefault();
+                highlight(active + (e.key === 'ArrowDown' ? 1 : -1));
+            } else if (e.key === 'Enter') {
+                var all = links();
+                if (all.length) { window.location.href = all[Math.max(active, 0)].href; }
+            } else if (e.key === 'Escape') {
+                input.blur();
+                close();
+            }
+        });
+        document.addEventListener('click', function (e) {
+            if (!nav.querySelector('.site-search').contains(e.target)) { close(); }
+        });
+        document.addEventListener('keydown', function (e) {
+            var tag = document.activeElement ? document.activeElement.tagName : '';
+            if (e.key === '/' && tag !== 'INPUT' && tag !== 'TEXTAREA') {
+                e.preventDefault();
+                input.focus();
+            }
+        });
+    }
+
+    // sortTable sorts a table by the clicked column: numbers by value, the
+    // rest by text, honouring a cell's data-value.
+    function sortTable(table) {
+        var heads = table.querySelectorAll('thead th');
+        heads.forEach(function (th, i) {
+            th.addEventListener('click', function () {
+                var desc = th.getAttribute('data-sort') !== 'desc';
+                heads.forEach(function (h) { h.removeAttribute('data-sort'); });
+                th.setAttribute('data-sort', desc ? 'desc' : 'asc');
+                var body = table.tBodies[0], rows = Array.prototype.slice.call(body.rows);
+                var cell = function (r) {
+                    var c = r.cells[i], text = c ? c.getAttribute('data-value') || c.textContent.trim() : '';
+                    var n = parseFloat(text.replace(/[+,]/g, ''));
+                    return isNaN(n) ? text.toLowerCase() : n;
+                };
+                rows.sort(function (a, b) {
+                    var va = cell(a), vb = cell(b), d = va < vb ? -1 : va > vb ? 1 : 0;
+                    return desc ? -d : d;
+                });
+                rows.forEach(function (r) { body.appendChild(r); });
+            });
+        });
+    }
+
+    document.querySelectorAll('nav.site-nav').forEach(setupSearch);
+    document.querySelectorAll('table.site-table').forEach(sortTable);
+})();
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// unitLines is the unit of commit size percentiles.
+const unitLines = "lines"
+
+// defaultBucketNames name the buckets of metrics.DefaultSizeEdges.
+var defaultBucketNames = []string{"Micro", "Small", "Medium", "Large"}
+
+// defaultBucketKeys keep the trend keys of the default buckets stable.
+var defaultBucketKeys = []string{keyCommitMicro, keyCommitSmall, keyCommitMedium, keyCommitLarge}
+
+// sizeBucketName labels bucket i, e.g. "Micro (<10)" with the default edges
+// or "16-63 lines" with configured ones.
+func sizeBucketName(d *metrics.CommitSizeDistribution, i int) string {
+	if slices.Equal(d.Edges, metrics.DefaultSizeEdges) {
+		return fmt.Sprintf("%s (%s)", defaultBucketNames[i], d.BucketLabel(i))
+	}
+	return d.BucketLabel(i) + " lines"
+}
+
+// sizeBucketKey is bucket i's key in trends, JSON comparisons and the
+// "your normal" notes, e.g. commit_size_16_63_pct.
+func sizeBucketKey(d *metrics.CommitSizeDistribution, i int) string {
+	if slices.Equal(d.Edges, metrics.DefaultSizeEdges) {
+		return defaultBucketKeys[i]
+	}
+	label := strings.NewReplacer("<", "lt", "+", "plus", "-", "_").Replace(d.BucketLabel(i))
+	return "commit_size_" + label + "_pct"
+}
+
+// sizeBucketTrends returns a trend metric per bucket of the first commit
+// size distribution among bundles. Bundles bucketed by other edges have no
+// value for them.
+func sizeBucketTrends(bundles []metrics.Bundle) []trendMetric {
+	var ref *metrics.CommitSizeDistribution
+	for _, b := range bundles {
+		if b.CommitSize != nil {
+			ref = b.CommitSize
+			break
+		}
+	}
+	if ref == nil {
+		return nil
+	}
+	var out []trendMetric
+	for i := range ref.Counts {
+		label := "Commits of " + sizeBucketName(ref, i)
+		if slices.Equal(ref.Edges, metrics.DefaultSizeEdges) {
+			label = fmt.Sprintf("%s commits (%s)", defaultBucketNames[i], ref.BucketLabel(i))
+		}
+		edges := ref.Edges
+		out = append(out, trendMetric{sizeBucketKey(ref, i), label, "%", func(b metrics.Bundle) (float64, bool) {
+			if b.CommitSize == nil || b.CommitSize.Total == 0 || !slices.Equal(b.CommitSize.Edges, edges) {
+				return 0, false
+			}
+			return b.CommitSize.Percent(i), true
+		}})
+	}
+	return out
+}
+
+// commitSizePercentile reads a percentile of total lines changed.
+func commitSizePercentile(p func(*metrics.CommitSizeDistribution) int) func(metrics.Bundle) (float64, bool) {
+	return func(b metrics.Bundle) (float64, bool) {
+		if b.CommitSize == nil || b.CommitSize.Total == 0 {
+			return 0, false
+		}
+		return float64(p(b.CommitSize)), true
+	}
+}
+
+// shortHash abbreviates a commit hash for display.
+func shortHash(h string) string {
+	if len(h) > 7 {
+		return h[:7]
+	}
+	return h
+}
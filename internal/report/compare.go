@@ -0,0 +1,195 @@
+package report
+
+import (
+	"path/filepath"
+
+	"github.com/juangracia/gitrespect/internal/benchmark"
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// CompareDetails carries everything a comparison report shows beyond the
+// per-period totals. Slices indexed by period line up with
+// git.CompareStats.Periods.
+type CompareDetails struct {
+	Significance []metrics.Significance // each period vs the first; entry 0 is unused
+	Metrics      []metrics.Bundle       // one per period, empty without --metrics
+	Repos        []RepoComparison       // set with --per-repo
+	Members      []MemberComparison     // set with --team
+	Diagnostics  []metrics.Diagnostic   // failures and gaps, scoped by period label
+}
+
+// SignificanceAt returns the test of period i against the first period.
+func (d CompareDetails) SignificanceAt(i int) metrics.Significance {
+	if i < len(d.Significance) {
+		return d.Significance[i]
+	}
+	return metrics.Significance{Insufficient: true, PValue: 1}
+}
+
+// RepoComparison holds one repository's stats for each period.
+type RepoComparison struct {
+	Path    string
+	Periods []git.RepoStats
+}
+
+// MemberComparison holds one team member's stats and metrics for each period.
+type MemberComparison struct {
+	Email   string
+	Periods []git.RepoStats
+	Metrics []metrics.Bundle
+}
+
+// metricSeries is one opt-in metric tracked across all periods.
+type metricSeries struct {
+	Key    string
+	Label  string
+	Unit   string // "%", "days", unitLines or unitPerWeek
+	Values []float64
+	Has    []bool
+}
+
+// Delta returns the change of period i against the first period. ok is
+// false when either side lacks data.
+func (m metricSeries) Delta(i int) (delta float64, ok bool) {
+	if !m.Has[0] || !m.Has[i] {
+		return 0, false
+	}
+	return m.Values[i] - m.Values[0], true
+}
+
+// unitPerWeek is the unit of rates such as deploy frequency.
+const unitPerWeek = "/week"
+
+// trendMetric is a metric that can be compared across periods and how to
+// read it from a bundle.
+type trendMetric struct {
+	key   string
+	label string
+	unit  string
+	value func(metrics.Bundle) (float64, bool)
+}
+
+// trendMetrics lists the metrics every bundle shares; commit size buckets
+// depend on the configured edges and come from sizeBucketTrends.
+var trendMetrics = []trendMetric{
+	{keyCommitP50, "Median commit size", unitLines, commitSizePercentile(func(d *metrics.CommitSizeDistribution) int { return d.P50 })},
+	{keyCommitP90, "p90 commit size", unitLines, commitSizePercentile(func(d *metrics.CommitSizeDistribution) int { return d.P90 })},
+	{keyCadence, "Integration cadence", "days", func(b metrics.Bundle) (float64, bool) {
+		if b.Cadence == nil || b.Cadence.Samples == 0 {
+			return 0, false
+		}
+		return b.Cadence.MedianDaysBetween, true
+	}},
+	{keyLeadTime, "Lead time (branch → main)", "days", func(b metrics.Bundle) (float64, bool) {
+		if b.LeadTime == nil || b.LeadTime.Samples == 0 {
+			return 0, false
+		}
+		return b.LeadTime.MedianDays, true
+	}},
+	{keyDeployLead, "Lead time to deploy", "days", func(b metrics.Bundle) (float64, bool) {
+		if b.Deploy == nil || b.Deploy.Samples == 0 {
+			return 0, false
+		}
+		return b.Deploy.MedianDays, true
+	}},
+	{keyDeployFreq, "Deploy frequency", unitPerWeek, func(b metrics.Bundle) (float64, bool) {
+		if b.Deploy == nil || b.Deploy.Weeks == 0 {
+			return 0, false
+		}
+		return b.Deploy.PerWeek, true
+	}},
+	{keyChurn, "Churn rate", "%", func(b metrics.Bundle) (float64, bool) {
+		if b.Churn == nil || b.Churn.AddedLines == 0 {
+			return 0, false
+		}
+		return b.Churn.Ratio * 100, true
+	}},
+	{keyRevertRate, "Revert rate", "%", func(b metrics.Bundle) (float64, bool) {
+		if b.Reverts == nil || b.Reverts.Commits == 0 {
+			return 0, false
+		}
+		return b.Reverts.RevertRate * 100, true
+	}},
+	{keyFixRate, "Fix rate", "%", func(b metrics.Bundle) (float64, bool) {
+		if b.Reverts == nil || b.Reverts.Commits == 0 {
+			return 0, false
+		}
+		return b.Reverts.FixRate * 100, true
+	}},
+}
+
+// metricTrend lines up the opt-in metrics of one bundle per period. A metric
+// is included when at least one period has enough data for it; periods
+// without data are marked missing rather than reported as zero.
+func metricTrend(bundles []metrics.Bundle) []metricSeries {
+	var out []metricSeries
+	for _, tm := range append(sizeBucketTrends(bundles), trendMetrics...) {
+		s := metricSeries{
+			Key:    tm.key,
+			Label:  tm.label,
+			Unit:   tm.unit,
+			Values: make([]float64, len(bundles)),
+			Has:    make([]bool, len(bundles)),
+		}
+		found := false
+		for i, b := range bundles {
+			s.Values[i], s.Has[i] = tm.value(b)
+			found = found || s.Has[i]
+		}
+		if found {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// repoMetricTrend lines up the per-repo opt-in metrics of a bundle, one
+// column per repo.
+func repoMetricTrend(b metrics.Bundle) ([]string, []metricSeries) {
+	labels := make([]string, len(b.Repos))
+	bundles := make([]metrics.Bundle, len(b.Repos))
+	for i, r := range b.Repos {
+		labels[i] = filepath.Base(r.Path)
+		bundles[i] = r.Bundle
+	}
+	return labels, metricTrend(bundles)
+}
+
+// perDay returns net lines per working day over the stats' date range.
+func perDay(stats git.RepoStats) float64 {
+	return float64(stats.Net) / float64(git.WorkingDays(stats.Since, stats.Until))
+}
+
+// multiplierVsFirst returns the lines/day ratio of periods[i] to periods[0],
+// or 0 when the first period's rate is not positive.
+func multiplierVsFirst(periods []git.RepoStats, i int) float64 {
+	return benchmark.CalculateMultiplier(perDay(periods[0]), perDay(periods[i]))
+}
+
+// changeVsFirst returns the headline change of periods[i] against
+// periods[0]. When a confidence interval was bootstrapped it is the ratio of
+// weekly means the interval surrounds, so the two are never quoted from
+// different estimators; otherwise it is multiplierVsFirst.
+func changeVsFirst(periods []git.RepoStats, sig metrics.Significance, i int) float64 {
+	if sig.Ratio > 0 && (sig.RatioLow != 0 || sig.RatioHigh != 0) {
+		return sig.Ratio
+	}
+	return multiplierVsFirst(periods, i)
+}
+
+func periodStats(periods []git.LabeledStats) []git.RepoStats {
+	out := make([]git.RepoStats, len(periods))
+	for i, p := range periods {
+		out[i] = p.Stats
+	}
+	return out
+}
+
+func periodLabels(periods []git.LabeledStats) []string {
+	out := make([]string, len(periods))
+	for i, p := range periods {
+		out[i] = p.Label
+	}
+	return out
+}
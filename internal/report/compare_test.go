@@ -0,0 +1,97 @@
+package report
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// weeklyPeriod returns stats for whole weeks from the Monday start, with
+// perDay[i] net lines on each weekday of week i.
+func weeklyPeriod(start time.Time, perDay ...int) git.RepoStats {
+	s := git.RepoStats{
+		Since:  start,
+		Until:  start.AddDate(0, 0, 7*len(perDay)).Add(-time.Second),
+		Weekly: make(map[string]git.WeekStats),
+	}
+	for i, n := range perDay {
+		s.Weekly[git.WeekKey(start.AddDate(0, 0, 7*i))] = git.WeekStats{Net: 5 * n, Added: 5 * n}
+		s.Net += 5 * n
+		s.Added += 5 * n
+	}
+	return s
+}
+
+// captureStdout returns what fn prints.
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	done := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- out
+	}()
+	ferr := fn()
+	w.Close()
+	out := <-done
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	return string(out)
+}
+
+var (
+	ansiRe     = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	headlineRe = regexp.MustCompile(`Change:\**\s+\+?([0-9.]+)x lines/day \(95% CI ([0-9.]+)x–([0-9.]+)x\)`)
+)
+
+func TestCompareHeadlineInsideCI(t *testing.T) {
+	// Six weeks at 10 lines/day, then eight at 100. Working days are
+	// estimated from the calendar, so lines/day over the whole period puts
+	// the change at 9.9x while every weekly sample says exactly 10x.
+	before := weeklyPeriod(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), 10, 10, 10, 10, 10, 10)
+	after := weeklyPeriod(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), 100, 100, 100, 100, 100, 100, 100, 100)
+	noisy := weeklyPeriod(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), 60, 140, 90, 20, 200, 75, 130, 95)
+
+	for name, periods := range map[string][]git.RepoStats{"steady": {before, after}, "noisy": {before, noisy}} {
+		comparison := git.CompareStats{Periods: []git.LabeledStats{{Label: "before", Stats: periods[0]}, {Label: "after", Stats: periods[1]}}}
+		details := CompareDetails{Significance: []metrics.Significance{{}, metrics.CompareSamples(metrics.WeeklySamples(periods[0]), metrics.WeeklySamples(periods[1]))}}
+
+		terminal := captureStdout(t, func() error { return CompareTerminal(comparison, details) })
+		md := filepath.Join(t.TempDir(), "compare.md")
+		if err := CompareMarkdown(comparison, md, details); err != nil {
+			t.Fatal(err)
+		}
+		markdown, err := os.ReadFile(md)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for format, out := range map[string]string{"terminal": ansiRe.ReplaceAllString(terminal, ""), "markdown": string(markdown)} {
+			m := headlineRe.FindStringSubmatch(out)
+			if m == nil {
+				t.Errorf("%s %s: no headline with a CI in\n%s", name, format, out)
+				continue
+			}
+			headline, _ := strconv.ParseFloat(m[1], 64)
+			low, _ := strconv.ParseFloat(m[2], 64)
+			high, _ := strconv.ParseFloat(m[3], 64)
+			if headline < low || headline > high {
+				t.Errorf("%s %s: headline %.1fx outside its CI %.1fx–%.1fx", name, format, headline, low, high)
+			}
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// csvReport writes a report as CSV sections: a "# name" row, a header row
+// and the data, separated by blank rows, so that each section pastes into a
+// spreadsheet as its own table.
+type csvReport struct {
+	buf bytes.Buffer
+	w   *csv.Writer
+	n   int
+}
+
+func newCSVReport() *csvReport {
+	r := &csvReport{}
+	r.w = csv.NewWriter(&r.buf)
+	return r
+}
+
+// section writes one table; it is skipped when it has no rows.
+func (r *csvReport) section(name string, header []string, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	if r.n > 0 {
+		r.w.Write(nil)
+	}
+	r.n++
+	r.w.Write([]string{"# " + name})
+	r.w.Write(header)
+	r.w.WriteAll(rows)
+}
+
+// save writes the report to filename, or stdout when it is empty.
+func (r *csvReport) save(filename string) error {
+	r.w.Flush()
+	if err := r.w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	if filename == "" {
+		fmt.Print(r.buf.String())
+		return nil
+	}
+	if err := os.WriteFile(filename, r.buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	fmt.Printf("✓ Report saved to %s\n", filename)
+	return nil
+}
+
+// CSV writes the single-author report as CSV sections: the summary, the
+// baseline, opt-in metrics, outliers, per-repo totals when repos is given,
+// the monthly or weekly breakdown and diagnostics.
+func CSV(stats git.RepoStats, repos []git.RepoStats, filename string, breakdown string, bundle metrics.Bundle) error {
+	workingDays := git.WorkingDays(stats.Since, stats.Until)
+	r := newCSVReport()
+	r.section("summary",
+		[]string{"author", "since", "until", "working_days", "added", "deleted", "net", "commits", "files_changed", "net_per_day", "excluded_commits"},
+		[][]string{{stats.Author, stats.Since.Format("2006-01-02"), stats.Until.Format("2006-01-02"), strconv.Itoa(workingDays),
+			strconv.Itoa(stats.Added), strconv.Itoa(stats.Deleted), strconv.Itoa(stats.Net), strconv.Itoa(stats.Commits),
+			strconv.Itoa(stats.FilesChanged), csvFloat(float64(stats.Net) / float64(workingDays)), strconv.Itoa(len(stats.Excluded))}})
+	if !bundle.LegacyBenchmark {
+		r.section("baseline", baselineCSVHeader, baselineCSVRows("", bundle.Baseline))
+	}
+	r.section("metrics", []string{"metric", "label", "unit", "value", "normal"}, metricCSVRows(bundle))
+	r.section("repo metrics", []string{"repo", "metric", "label", "unit", "value"}, repoMetricCSVRows(bundle))
+	r.section("commit size buckets", []string{"bucket", "commits", "pct"}, sizeBucketCSVRows(bundle.CommitSize))
+	r.section("outliers", []string{"repo", "sha", "date", "subject", "added", "deleted", "kinds", "reasons"}, outlierCSVRows(bundle.Outliers))
+	if len(repos) > 1 {
+		var rows [][]string
+		for _, repo := range repos {
+			rows = append(rows, []string{repo.Path, strconv.Itoa(repo.Added), strconv.Itoa(repo.Deleted), strconv.Itoa(repo.Net), strconv.Itoa(repo.Commits)})
+		}
+		r.section("repositories", []string{"repo", "added", "deleted", "net", "commits"}, rows)
+	}
+	switch breakdown {
+	case "monthly":
+		r.section("monthly", monthCSVHeader, monthCSVRows(stats.Monthly))
+	case "weekly":
+		r.section("weekly", []string{"week", "added", "deleted", "net", "commits"}, weekCSVRows(stats.Weekly))
+	}
+	r.section("diagnostics", diagnosticCSVHeader, diagnosticCSVRows(bundle.Diagnostics))
+	return r.save(filename)
+}
+
+// TeamCSV writes the team report as CSV sections: team totals, the team
+// baseline, one row per member, members' opt-in metrics, the monthly
+// breakdown and diagnostics.
+func TeamCSV(stats git.TeamStats, filename string, breakdown string, team metrics.Bundle, bundles map[string]metrics.Bundle) error {
+	workingDays := git.WorkingDays(stats.Since, stats.Until)
+	r := newCSVReport()
+	r.section("team summary",
+		[]string{"since", "until", "working_days", "members", "added", "deleted", "net", "commits", "net_per_day"},
+		[][]string{{stats.Since.Format("2006-01-02"), stats.Until.Format("2006-01-02"), strconv.Itoa(workingDays), strconv.Itoa(len(stats.Members)),
+			strconv.Itoa(stats.TotalAdded), strconv.Itoa(stats.TotalDeleted), strconv.Itoa(stats.TotalNet), strconv.Itoa(stats.TotalCommits),
+			csvFloat(float64(stats.TotalNet) / float64(workingDays))}})
+	if !team.LegacyBenchmark {
+		r.section("team baseline", baselineCSVHeader, baselineCSVRows("team", team.Baseline))
+	}
+	r.section("team metrics", []string{"metric", "label", "unit", "value", "normal"}, metricCSVRows(team))
+
+	emails := sortedMembers(stats)
+
+	var members, baselines, memberMetrics [][]string
+	for _, email := range emails {
+		m := stats.Members[email]
+		members = append(members, []string{email, strconv.Itoa(m.Added), strconv.Itoa(m.Deleted), strconv.Itoa(m.Net),
+			strconv.Itoa(m.Commits), csvFloat(float64(m.Net) / float64(workingDays))})
+		b := bundles[email]
+		if !team.LegacyBenchmark {
+			baselines = append(baselines, baselineCSVRows(email, b.Baseline)...)
+		}
+		for _, row := range metricCSVRows(b) {
+			memberMetrics = append(memberMetrics, append([]string{email}, row...))
+		}
+	}
+	r.section("members", []string{"email", "added", "deleted", "net", "commits", "net_per_day"}, members)
+	r.section("member baselines", baselineCSVHeader, baselines)
+	r.section("member metrics", []string{"email", "metric", "label", "unit", "value", "normal"}, memberMetrics)
+	if breakdown == "monthly" {
+		r.section("monthly", monthCSVHeader, monthCSVRows(stats.Monthly))
+	}
+	r.section("diagnostics", diagnosticCSVHeader, diagnosticCSVRows(team.Diagnostics))
+	return r.save(filename)
+}
+
+// CompareCSV writes a comparison as CSV sections: one row per period with
+// its change and significance against the first, the opt-in metric trend,
+// and lines/day per repository and per member for each period.
+func CompareCSV(comparison git.CompareStats, filename string, details CompareDetails) error {
+	periods := periodStats(comparison.Periods)
+	labels := periodLabels(comparison.Periods)
+	if len(periods) == 0 {
+		return fmt.Errorf("no periods to compare")
+	}
+	r := newCSVReport()
+
+	var rows [][]string
+	for i, p := range periods {
+		row := []string{labels[i], p.Since.Format("2006-01-02"), p.Until.Format("2006-01-02"), strconv.Itoa(git.WorkingDays(p.Since, p.Until)),
+			strconv.Itoa(p.Added), strconv.Itoa(p.Deleted), strconv.Itoa(p.Net), strconv.Itoa(p.Commits), csvFloat(perDay(p))}
+		if i == 0 {
+			// The first period's weekly samples are every test's "before".
+			row = append(row, "", "", "", strconv.Itoa(details.SignificanceAt(1).BeforeWeeks), "", "", "", "")
+		} else {
+			sig := details.SignificanceAt(i)
+			row = append(row, csvFloat(changeVsFirst(periods, sig, i)), csvFloat(sig.RatioLow), csvFloat(sig.RatioHigh),
+				strconv.Itoa(sig.AfterWeeks), strconv.FormatBool(sig.Insufficient))
+			if sig.Insufficient {
+				row = append(row, "", "", "")
+			} else {
+				row = append(row, strconv.FormatFloat(sig.PValue, 'f', 3, 64), strconv.FormatBool(sig.Significant()), sig.Effect)
+			}
+		}
+		rows = append(rows, row)
+	}
+	r.section("periods", []string{"period", "since", "until", "working_days", "added", "deleted", "net", "commits", "net_per_day",
+		"vs_first", "ci_low", "ci_high", "weeks", "insufficient_data", "p_value", "significant", "effect"}, rows)
+
+	trendHeader := append([]string{"metric", "label", "unit"}, labels...)
+	r.section("metrics", trendHeader, trendCSVRows(nil, metricTrend(details.Metrics)))
+
+	rowHeader := append([]string{"name"}, labels...)
+	rows = nil
+	for _, repo := range details.Repos {
+		rows = append(rows, perDayCSVRow(repo.Path, repo.Periods))
+	}
+	r.section("repositories (net lines/day)", rowHeader, rows)
+	rows = nil
+	var memberMetrics [][]string
+	for _, m := range details.Members {
+		rows = append(rows, perDayCSVRow(m.Email, m.Periods))
+		memberMetrics = append(memberMetrics, trendCSVRows([]string{m.Email}, metricTrend(m.Metrics))...)
+	}
+	r.section("members (net lines/day)", rowHeader, rows)
+	r.section("member metrics", append([]string{"email"}, trendHeader...), memberMetrics)
+	r.section("diagnostics", diagnosticCSVHeader, diagnosticCSVRows(details.Diagnostics))
+	return r.save(filename)
+}
+
+// csvFloat renders a float for CSV with two decimals.
+func csvFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+var baselineCSVHeader = []string{"who", "window_start", "window_end", "normal_per_day", "p25_per_day", "p75_per_day", "period_per_day", "position", "last_year_per_day", "last_year_position"}
+
+// baselineCSVRows returns a baseline as one row, or none when it was not
+// computed or the history is too thin.
+func baselineCSVRows(who string, b *metrics.Baseline) [][]string {
+	if b == nil || b.InsufficientHistory {
+		return nil
+	}
+	row := []string{who, b.WindowStart.Format("2006-01-02"), b.WindowEnd.Format("2006-01-02"),
+		csvFloat(b.LOCPerDay), csvFloat(b.P25), csvFloat(b.P75), csvFloat(b.PeriodLOCPerDay), b.Position, "", ""}
+	if s := b.Seasonal; s != nil && !s.Insufficient {
+		row[8], row[9] = csvFloat(s.Median), s.Position
+	}
+	return [][]string{row}
+}
+
+// metricCSVRows lists a bundle's opt-in metrics with their trend keys and,
+// when computed, the baseline-window value.
+func metricCSVRows(b metrics.Bundle) [][]string {
+	var rows [][]string
+	for _, s := range metricTrend([]metrics.Bundle{b}) {
+		normal := ""
+		if v, _, ok := normalValue(b, s.Key); ok {
+			normal = csvFloat(v)
+		}
+		rows = append(rows, []string{s.Key, s.Label, s.Unit, csvMetricValue(s, 0), normal})
+	}
+	return rows
+}
+
+// repoMetricCSVRows lists each repository's opt-in metrics (--per-repo).
+func repoMetricCSVRows(b metrics.Bundle) [][]string {
+	var rows [][]string
+	for _, repo := range b.Repos {
+		for _, s := range metricTrend([]metrics.Bundle{repo.Bundle}) {
+			rows = append(rows, []string{repo.Path, s.Key, s.Label, s.Unit, csvMetricValue(s, 0)})
+		}
+	}
+	return rows
+}
+
+// trendCSVRows lists each metric with a column per period, prefixed by
+// lead.
+func trendCSVRows(lead []string, series []metricSeries) [][]string {
+	var rows [][]string
+	for _, s := range series {
+		row := append(append([]string{}, lead...), s.Key, s.Label, s.Unit)
+		for i := range s.Values {
+			row = append(row, csvMetricValue(s, i))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// csvMetricValue is period i's value of a metric, empty without data.
+func csvMetricValue(s metricSeries, i int) string {
+	if !s.Has[i] {
+		return ""
+	}
+	return csvFloat(s.Values[i])
+}
+
+func sizeBucketCSVRows(d *metrics.CommitSizeDistribution) [][]string {
+	if d == nil {
+		return nil
+	}
+	var rows [][]string
+	for i, n := range d.Counts {
+		rows = append(rows, []string{d.BucketLabel(i), strconv.Itoa(n), csvFloat(d.Percent(i))})
+	}
+	return rows
+}
+
+func outlierCSVRows(outliers []git.Outlier) [][]string {
+	var rows [][]string
+	for _, o := range outliers {
+		c := o.Commit
+		rows = append(rows, []string{c.Repo, c.Hash, c.Date.Format("2006-01-02"), c.Subject, strconv.Itoa(c.Added), strconv.Itoa(c.Deleted),
+			strings.Join(o.Kinds, " "), strings.Join(o.Reasons, "; ")})
+	}
+	return rows
+}
+
+var monthCSVHeader = []string{"month", "added", "deleted", "net", "commits"}
+
+func monthCSVRows(monthly map[string]git.MonthStats) [][]string {
+	keys := make([]string, 0, len(monthly))
+	for k := range monthly {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var rows [][]string
+	for _, k := range keys {
+		m := monthly[k]
+		rows = append(rows, []string{fmt.Sprintf("%d-%02d", m.Year, m.Month), strconv.Itoa(m.Added), strconv.Itoa(m.Deleted), strconv.Itoa(m.Net), strconv.Itoa(m.Commits)})
+	}
+	return rows
+}
+
+func weekCSVRows(weekly map[string]git.WeekStats) [][]string {
+	keys := make([]string, 0, len(weekly))
+	for k := range weekly {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var rows [][]string
+	for _, k := range keys {
+		w := weekly[k]
+		rows = append(rows, []string{k, strconv.Itoa(w.Added), strconv.Itoa(w.Deleted), strconv.Itoa(w.Net), strconv.Itoa(w.Commits)})
+	}
+	return rows
+}
+
+// perDayCSVRow is a repository's or member's net lines/day in each period.
+func perDayCSVRow(name string, periods []git.RepoStats) []string {
+	row := []string{name}
+	for _, p := range periods {
+		row = append(row, csvFloat(perDay(p)))
+	}
+	return row
+}
+
+var diagnosticCSVHeader = []string{"metric", "repo", "author", "scope", "status", "reason"}
+
+// diagnosticCSVRows lists every diagnostic that is not OK; like JSON, CSV
+// carries them all rather than only the notable ones.
+func diagnosticCSVRows(diags []metrics.Diagnostic) [][]string {
+	var rows [][]string
+	for _, d := range diags {
+		if d.Status == metrics.StatusOK {
+			continue
+		}
+		rows = append(rows, []string{d.Metric, d.Repo, d.Author, d.Scope, string(d.Status), d.Reason})
+	}
+	return rows
+}
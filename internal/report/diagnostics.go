@@ -0,0 +1,41 @@
+package report
+
+import (
+	"path/filepath"
+
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// notableDiagnostics picks the diagnostics worth showing next to a report.
+// Errors, skipped analyses and what Merge left out always are; a metric
+// skipped or short of data
+// only when the report pools several repos, since its section otherwise
+// already says why. The baseline section always explains a thin history
+// itself. JSON reports carry every diagnostic regardless.
+func notableDiagnostics(diags []metrics.Diagnostic) []metrics.Diagnostic {
+	repos := make(map[string]bool)
+	for _, d := range diags {
+		if d.Repo != "" {
+			repos[d.Repo] = true
+		}
+	}
+	var out []metrics.Diagnostic
+	for _, d := range diags {
+		switch {
+		case d.Status == metrics.StatusOK:
+		case d.Status == metrics.StatusError, d.Metric == metrics.MetricAnalyze, d.Scope == MergedScope:
+			out = append(out, d)
+		case d.Metric != metrics.MetricBaseline && len(repos) > 1:
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// shortDiagnostic is d's String form with the repo path cut to its base name.
+func shortDiagnostic(d metrics.Diagnostic) string {
+	if d.Repo != "" {
+		d.Repo = filepath.Base(d.Repo)
+	}
+	return d.String()
+}
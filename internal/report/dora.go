@@ -0,0 +1,114 @@
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// DORADetails carries a dora run for the renderers. DORA is pooled over
+// Repos; Repo holds each repository's own metrics when there are several.
+type DORADetails struct {
+	Repos       []string
+	Authors     []string // whose commits lead time counts; empty for everyone
+	Since       time.Time
+	Until       time.Time
+	DORA        metrics.DORA
+	Repo        map[string]metrics.DORA
+	Diagnostics []metrics.Diagnostic
+}
+
+// repoNames returns the base names of the analyzed repositories.
+func (d DORADetails) repoNames() string {
+	names := make([]string, len(d.Repos))
+	for i, r := range d.Repos {
+		names[i] = filepath.Base(r)
+	}
+	return strings.Join(names, ", ")
+}
+
+// who names the authors lead time was computed for.
+func (d DORADetails) who() string {
+	if len(d.Authors) == 0 {
+		return "everyone"
+	}
+	return strings.Join(d.Authors, ", ")
+}
+
+// sortedRepos returns the keys of Repo in order.
+func (d DORADetails) sortedRepos() []string {
+	repos := make([]string, 0, len(d.Repo))
+	for r := range d.Repo {
+		repos = append(repos, r)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// doraRow is one of the four DORA metrics, formatted.
+type doraRow struct {
+	Label string
+	Value string
+	Note  string
+}
+
+// doraRows formats the four metrics; values without data read "n/a".
+func doraRows(d metrics.DORA) []doraRow {
+	lead := doraRow{Label: "Lead time for changes", Value: "n/a", Note: "no deployed commits in period"}
+	if d.LeadTime.Samples > 0 {
+		lead.Value = fmt.Sprintf("%.1f days", d.LeadTime.MedianDays)
+		lead.Note = fmt.Sprintf("median of %d commits", d.LeadTime.Samples)
+	}
+	cfr := doraRow{Label: "Change failure rate", Value: "n/a", Note: "no deploys in period"}
+	if d.Deploys > 0 {
+		cfr.Value = fmt.Sprintf("%.0f%%", d.ChangeFailureRate*100)
+		cfr.Note = fmt.Sprintf("%d of %d deploys", d.FailedDeploys, d.Deploys)
+	}
+	restore := doraRow{Label: "Time to restore", Value: "n/a", Note: "no restored failures"}
+	if d.Restores > 0 {
+		restore.Value = formatHours(d.RestoreMedianHours)
+		restore.Note = fmt.Sprintf("median of %d", d.Restores)
+		if open := len(d.Failures) - d.Restores; open > 0 {
+			restore.Note += fmt.Sprintf(", %d still open", open)
+		}
+	}
+	return []doraRow{
+		{Label: "Deployment frequency", Value: fmt.Sprintf("%.2f/week", d.PerWeek), Note: fmt.Sprintf("%d deploys", d.Deploys)},
+		lead,
+		cfr,
+		restore,
+	}
+}
+
+// describeFailure renders a failure as e.g. `v1.3 (Mar 2): revert —
+// Revert "Add cache" → restored by v1.4 after 5.0 hours`.
+func describeFailure(f metrics.Failure) string {
+	s := fmt.Sprintf("%s (%s): %s", f.Deploy, f.DeployedAt.Format("Jan 2"), f.Kind)
+	if f.Detail != "" {
+		s += " — " + f.Detail
+	}
+	switch {
+	case !f.Restored:
+		s += " → not restored yet"
+	case f.RestoredBy != "":
+		s += fmt.Sprintf(" → restored by %s after %s", f.RestoredBy, formatHours(f.RestoreHours))
+	default:
+		s += " → restored after " + formatHours(f.RestoreHours)
+	}
+	return s
+}
+
+// formatHours renders a duration in hours as minutes, hours or days.
+func formatHours(h float64) string {
+	switch {
+	case h < 1:
+		return fmt.Sprintf("%.0f min", h*60)
+	case h < 48:
+		return fmt.Sprintf("%.1f hours", h)
+	}
+	return fmt.Sprintf("%.1f days", h/24)
+}
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+)
+
+// explainTitle names an --explain bucket, e.g. "Mar 2026" or "week 2026-W07".
+func explainTitle(bucket string) string {
+	if bucket == git.ExplainTotal {
+		return "total"
+	}
+	if strings.Contains(bucket, "-W") {
+		return "week " + bucket
+	}
+	if t, err := time.Parse("2006-01", bucket); err == nil {
+		return t.Format("Jan 2006")
+	}
+	return bucket
+}
+
+// sharePct renders part's share of whole, e.g. "42%".
+func sharePct(part, whole int) string {
+	if whole == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(part)*100/float64(whole))
+}
+
+// plural returns word, with an "s" unless n is 1.
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+)
+
+// Export formats.
+const (
+	ExportCSV     = "csv"
+	ExportJSONL   = "jsonl"
+	ExportParquet = "parquet"
+)
+
+// ExportFormat picks the format for an export: format if given, else the
+// file's extension, else JSON Lines.
+func ExportFormat(format, filename string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".csv":
+			return ExportCSV, nil
+		case ".parquet":
+			return ExportParquet, nil
+		default:
+			return ExportJSONL, nil
+		}
+	}
+	switch format {
+	case ExportCSV, ExportJSONL, ExportParquet:
+		return format, nil
+	case "ndjson":
+		return ExportJSONL, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (use csv, jsonl or parquet)", format)
+}
+
+// CommitExportJSON is one exported commit. In CSV and Parquet, Files is a
+// JSON-encoded column.
+type CommitExportJSON struct {
+	Repo          string           `json:"repo"`
+	SHA           string           `json:"sha"`
+	Parents       []string         `json:"parents"`
+	AuthorName    string           `json:"author_name"`
+	AuthorEmail   string           `json:"author_email"`
+	AuthorDate    string           `json:"author_date"`
+	CommitterDate string           `json:"committer_date"`
+	Subject       string           `json:"subject"`
+	Added         int              `json:"added"`
+	Deleted       int              `json:"deleted"`
+	FilesChanged  int              `json:"files_changed"`
+	Files         []FileExportJSON `json:"files"`
+	Excluded      bool             `json:"excluded"`
+	AIAssisted    bool             `json:"ai_assisted"`
+}
+
+type FileExportJSON struct {
+	Path     string `json:"path"`
+	Added    int    `json:"added"`
+	Deleted  int    `json:"deleted"`
+	Binary   bool   `json:"binary,omitempty"`
+	Excluded bool   `json:"excluded,omitempty"`
+}
+
+// exportColumns are the CSV header and Parquet column names, in order.
+var exportColumns = []string{
+	"repo", "sha", "parents", "author_name", "author_email", "author_date", "committer_date",
+	"subject", "added", "deleted", "files_changed", "files", "excluded", "ai_assisted",
+}
+
+func commitExportJSON(r git.CommitRecord) CommitExportJSON {
+	out := CommitExportJSON{
+		Repo:          r.Repo,
+		SHA:           r.Hash,
+		Parents:       r.Parents,
+		AuthorName:    r.AuthorName,
+		AuthorEmail:   r.AuthorEmail,
+		AuthorDate:    r.AuthorDate.Format(time.RFC3339),
+		CommitterDate: r.CommitDate.Format(time.RFC3339),
+		Subject:       r.Subject,
+		Added:         r.Added,
+		Deleted:       r.Deleted,
+		FilesChanged:  len(r.Files),
+		Files:         []FileExportJSON{},
+		Excluded:      r.Excluded,
+		AIAssisted:    r.AIAssisted,
+	}
+	if out.Parents == nil {
+		out.Parents = []string{}
+	}
+	for _, f := range r.Files {
+		out.Files = append(out.Files, FileExportJSON{Path: f.Path, Added: f.Added, Deleted: f.Deleted, Binary: f.Binary, Excluded: f.Excluded})
+	}
+	return out
+}
+
+// Export writes one record per commit in format to filename, or to stdout
+// when filename is empty (not for Parquet).
+func Export(records []git.CommitRecord, format, filename string) error {
+	if format == ExportParquet && filename == "" {
+		return fmt.Errorf("parquet export needs --file")
+	}
+	var w io.Writer = os.Stdout
+	if filename != "" {
+		f, err := os.Create(filename)
+		if err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	var err error
+	switch format {
+	case ExportCSV:
+		err = exportCSV(bw, records)
+	case ExportParquet:
+		err = exportParquet(bw, records)
+	default:
+		err = exportJSONL(bw, records)
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	if filename != "" {
+		fmt.Fprintf(os.Stderr, "✓ Exported %d commits to %s\n", len(records), filename)
+	}
+	return nil
+}
+
+func exportJSONL(w io.Writer, records []git.CommitRecord) error {
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(commitExportJSON(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportCSV(w io.Writer, records []git.CommitRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportColumns); err != nil {
+		return err
+	}
+	for _, r := range records {
+		c := commitExportJSON(r)
+		files, err := json.Marshal(c.Files)
+		if err != nil {
+			return err
+		}
+		if err := cw.Write([]string{
+			c.Repo, c.SHA, strings.Join(c.Parents, " "), c.AuthorName, c.AuthorEmail, c.AuthorDate, c.CommitterDate,
+			c.Subject, strconv.Itoa(c.Added), strconv.Itoa(c.Deleted), strconv.Itoa(c.FilesChanged), string(files),
+			strconv.FormatBool(c.Excluded), strconv.FormatBool(c.AIAssisted),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
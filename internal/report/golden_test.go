@@ -0,0 +1,118 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares got with testdata/name, or rewrites it with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s differs from the golden file:\n--- got\n%s\n--- want\n%s", name, got, want)
+	}
+}
+
+// awkwardReport is a report whose text fields hold the characters CSV has
+// to quote and Markdown tables have to escape.
+func awkwardReport() (git.RepoStats, metrics.Bundle) {
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 3, 13, 23, 59, 59, 0, time.UTC)
+	stats := git.RepoStats{
+		Path:    "/src/api, v2",
+		Author:  "dev@example.com",
+		Since:   since,
+		Until:   until,
+		Added:   1200,
+		Deleted: 200,
+		Net:     1000,
+		Commits: 4,
+	}
+	change := func(hash, subject string) git.CommitChange {
+		return git.CommitChange{Repo: stats.Path, Hash: hash, Date: since.Add(48 * time.Hour), Subject: subject, Added: 500, Deleted: 10}
+	}
+	bundle := metrics.Bundle{
+		Since: since,
+		Until: until,
+		Outliers: []git.Outlier{
+			{Commit: change("1111111111111111111111111111111111111111", `Say "hello", world`), Kinds: []string{"huge"}, Reasons: []string{"3× the usual size"}},
+			{Commit: change("2222222222222222222222222222222222222222", "Pipe a | b || c"), Kinds: []string{"dump"}, Reasons: []string{"likely generated; vendor|gen"}},
+			{Commit: change("3333333333333333333333333333333333333333", "First line\nsecond line\r\nthird\rfourth"), Kinds: []string{"huge", "dump"}, Reasons: []string{"a, b", `"c"`}},
+		},
+		Diagnostics: []metrics.Diagnostic{
+			{Metric: metrics.MetricLeadTime, Repo: stats.Path, Status: metrics.StatusError, Reason: "git log: exit status 128\nfatal: bad | revision"},
+		},
+	}
+	return stats, bundle
+}
+
+func TestCSVQuotingGolden(t *testing.T) {
+	stats, bundle := awkwardReport()
+	out := filepath.Join(t.TempDir(), "report.csv")
+	if err := CSV(stats, nil, out, "", bundle); err != nil {
+		t.Fatalf("CSV: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "quoting.csv", got)
+
+	// Every awkward value reads back intact.
+	cr := csv.NewReader(bytes.NewReader(got))
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		t.Fatalf("reading the CSV back: %v", err)
+	}
+	var subjects []string
+	for _, row := range rows {
+		if len(row) == 8 && row[0] == stats.Path {
+			subjects = append(subjects, row[3])
+		}
+	}
+	if len(subjects) != len(bundle.Outliers) {
+		t.Fatalf("read back %d outliers, want %d", len(subjects), len(bundle.Outliers))
+	}
+	for i, o := range bundle.Outliers {
+		want := strings.ReplaceAll(o.Commit.Subject, "\r\n", "\n") // csv.Reader normalises line breaks
+		if subjects[i] != want {
+			t.Errorf("outlier %d subject = %q, want %q", i, subjects[i], want)
+		}
+	}
+}
+
+func TestMarkdownEscapingGolden(t *testing.T) {
+	stats, bundle := awkwardReport()
+	out := filepath.Join(t.TempDir(), "report.md")
+	if err := Markdown(stats, nil, out, "", bundle); err != nil {
+		t.Fatalf("Markdown: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "escaping.md", got)
+}
@@ -2,15 +2,16 @@ package report
 
 import (
 	"fmt"
-	"html/template"
-	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 
-	"github.com/juangracia/gitrespect/internal/benchmark"
 	"github.com/juangracia/gitrespect/internal/git"
 	"github.com/juangracia/gitrespect/internal/metrics"
 )
 
+// HTMLData feeds the report.html template. Sections without data are nil
+// or empty; docs/templates.md describes every field.
 type HTMLData struct {
 	Author      string
 	Since       string
@@ -23,44 +24,150 @@ type HTMLData struct {
 	PerDay      float64
 	Monthly     []MonthlyHTMLData
 	HasMonthly  bool
-	Theme       string
-	IsDark      bool
+	HTMLPage
 	Baseline    *BaselineHTMLData
 	CommitSize  *CommitSizeHTMLData
 	Cadence     *CadenceHTMLData
 	LeadTime    *LeadTimeHTMLData
+	Deploy      *DeployHTMLData
 	Churn       *ChurnHTMLData
+	Reverts     *RevertsHTMLData
+	RepoLabels  []string
+	RepoMetrics []CompareMetricHTMLData
+	Excluded    int
+	Outliers    []ChangeHTMLData
+	Explain     *ExplainHTMLData
+	Diagnostics []DiagnosticHTMLData
+	Interactive *InteractiveHTMLData
+}
+
+// ChangeHTMLData is one commit in the outliers or an --explain breakdown.
+// Note is the outlier's reasons or the commit's share of the bucket.
+type ChangeHTMLData struct {
+	Hash    string
+	Date    string
+	Subject string
+	Added   int
+	Deleted int
+	Note    string
+}
+
+type ExplainHTMLData struct {
+	Title   string
+	Added   int
+	Deleted int
+	Net     int
+	Commits int
+	Top     []ChangeHTMLData
+	Files   []ExplainFileHTMLData
+}
+
+type ExplainFileHTMLData struct {
+	Path    string
+	Added   int
+	Deleted int
+	Note    string
+}
+
+// DiagnosticHTMLData is one line of the Diagnostics section.
+type DiagnosticHTMLData struct {
+	Text    string
+	IsError bool
 }
 
 type BaselineHTMLData struct {
-	WindowDays   int
-	Normal       float64
-	Period       float64
-	PercentDelta float64
-	IsAbove      bool
-	Insufficient bool
+	WindowDays    int
+	Normal        float64
+	Low           float64
+	High          float64
+	Period        float64
+	PositionText  string
+	PositionClass string
+	Insufficient  bool
+	Seasonal      *SeasonalHTMLData
+}
+
+type SeasonalHTMLData struct {
+	Normal        float64
+	Low           float64
+	High          float64
+	PositionText  string
+	PositionClass string
+	Insufficient  bool
 }
 
 type CommitSizeHTMLData struct {
-	MicroPct  float64
-	SmallPct  float64
-	MediumPct float64
-	LargePct  float64
+	Buckets   []SizeBucketHTMLData
+	P50       int
+	P90       int
+	P99       int
+	P50Normal string
+	Added     metrics.SizeHistogram
+	Deleted   metrics.SizeHistogram
+	Largest   []LargestCommitHTMLData
+}
+
+// SizeBucketHTMLData is one histogram column. Heights are percentages of
+// the tallest column's count, so the bars share one scale.
+type SizeBucketHTMLData struct {
+	Label         string
+	Count         int
+	Pct           float64
+	Height        float64
+	AddedHeight   float64
+	DeletedHeight float64
+	Normal        string
+}
+
+type LargestCommitHTMLData struct {
+	Hash    string
+	Subject string
+	Added   int
+	Deleted int
 }
 
 type CadenceHTMLData struct {
 	MedianDays float64
 	Samples    int
+	Branch     string
+	Normal     string
 }
 
 type LeadTimeHTMLData struct {
 	MedianDays float64
 	Samples    int
+	Branch     string
+	Methods    string
+	Normal     string
+}
+
+type DeployHTMLData struct {
+	Source     string
+	MedianDays float64
+	Samples    int
+	Deploys    int
+	PerWeek    float64
+	Normal     string
+	RateNormal string
+}
+
+type RevertsHTMLData struct {
+	Commits      int
+	Reverted     int
+	Fixed        int
+	RevertPct    float64
+	FixPct       float64
+	DaysToRevert float64
+	DaysToFix    float64
+	WindowDays   int
+	Normal       string
+	FixNormal    string
 }
 
 type ChurnHTMLData struct {
 	Ratio      float64
 	WindowDays int
+	Normal     string
 }
 
 type MonthlyHTMLData struct {
@@ -72,593 +179,62 @@ type MonthlyHTMLData struct {
 	IsMax   bool
 }
 
+// CompareHTMLData feeds the compare.html template.
 type CompareHTMLData struct {
-	BeforeLabel  string
-	AfterLabel   string
-	BeforeNet    int
-	AfterNet     int
-	BeforeDays   int
-	AfterDays    int
-	BeforePerDay float64
-	AfterPerDay  float64
-	Multiplier   float64
-	ChangeEmoji  string
-	Theme        string
-	IsDark       bool
+	Periods     []ComparePeriodHTMLData
+	FirstLabel  string
+	LastLabel   string
+	Multiplier  float64 // last period vs first
+	ChangeEmoji string
+	HTMLPage
+	Significance  metrics.Significance // last period vs first
+	HasCI         bool
+	ConfidencePct float64
+	MinWeeks      int
+	Summary       string
+	Labels        []string
+	Metrics       []CompareMetricHTMLData
+	Repos         []CompareRowHTMLData
+	Members       []CompareRowHTMLData
+	Diagnostics   []DiagnosticHTMLData
 }
 
-const htmlTemplate = `<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>gitrespect - {{.Author}}</title>
-    <style>
-        :root {
-            {{if .IsDark}}
-            --bg-primary: #0d1117;
-            --bg-secondary: #161b22;
-            --bg-tertiary: #21262d;
-            --border: #30363d;
-            --text-primary: #c9d1d9;
-            --text-secondary: #8b949e;
-            --text-muted: #484f58;
-            --accent: #58a6ff;
-            --accent-secondary: #238636;
-            --success: #3fb950;
-            --warning: #d29922;
-            {{else}}
-            --bg-primary: #ffffff;
-            --bg-secondary: #f6f8fa;
-            --bg-tertiary: #eaeef2;
-            --border: #d0d7de;
-            --text-primary: #1f2328;
-            --text-secondary: #656d76;
-            --text-muted: #8c959f;
-            --accent: #0969da;
-            --accent-secondary: #1a7f37;
-            --success: #1a7f37;
-            --warning: #9a6700;
-            {{end}}
-        }
-
-        * {
-            margin: 0;
-            padding: 0;
-            box-sizing: border-box;
-        }
-
-        body {
-            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', 'Noto Sans', Helvetica, Arial, sans-serif;
-            background: var(--bg-primary);
-            color: var(--text-primary);
-            line-height: 1.5;
-            min-height: 100vh;
-        }
-
-        .container {
-            max-width: 900px;
-            margin: 0 auto;
-            padding: 32px 24px;
-        }
-
-        header {
-            margin-bottom: 32px;
-            padding-bottom: 16px;
-            border-bottom: 1px solid var(--border);
-        }
-
-        .logo {
-            font-size: 14px;
-            font-weight: 600;
-            color: var(--text-secondary);
-            margin-bottom: 8px;
-            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace;
-        }
-
-        h1 {
-            font-size: 24px;
-            font-weight: 600;
-            color: var(--text-primary);
-        }
-
-        .period {
-            font-size: 14px;
-            color: var(--text-secondary);
-            margin-top: 4px;
-        }
-
-        .stats-grid {
-            display: grid;
-            grid-template-columns: repeat(4, 1fr);
-            gap: 16px;
-            margin-bottom: 32px;
-        }
-
-        @media (max-width: 640px) {
-            .stats-grid {
-                grid-template-columns: repeat(2, 1fr);
-            }
-        }
-
-        .stat-card {
-            background: var(--bg-secondary);
-            border: 1px solid var(--border);
-            border-radius: 6px;
-            padding: 16px;
-        }
-
-        .stat-label {
-            font-size: 12px;
-            color: var(--text-secondary);
-            text-transform: uppercase;
-            letter-spacing: 0.5px;
-            margin-bottom: 4px;
-        }
-
-        .stat-value {
-            font-size: 28px;
-            font-weight: 600;
-            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace;
-        }
-
-        .stat-value.added { color: var(--success); }
-        .stat-value.deleted { color: var(--warning); }
-        .stat-value.net { color: var(--accent); }
-
-        .section {
-            background: var(--bg-secondary);
-            border: 1px solid var(--border);
-            border-radius: 6px;
-            padding: 20px;
-            margin-bottom: 24px;
-        }
-
-        .section-title {
-            font-size: 14px;
-            font-weight: 600;
-            color: var(--text-secondary);
-            margin-bottom: 16px;
-            text-transform: uppercase;
-            letter-spacing: 0.5px;
-        }
-
-        .daily-stat {
-            font-size: 32px;
-            font-weight: 600;
-            color: var(--accent);
-            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace;
-        }
-
-        .daily-label {
-            color: var(--text-secondary);
-            font-size: 14px;
-        }
-
-        .metric-row {
-            display: flex;
-            align-items: center;
-            justify-content: space-between;
-            padding: 10px 0;
-            border-bottom: 1px solid var(--border);
-        }
-
-        .metric-row:last-child {
-            border-bottom: none;
-        }
-
-        .metric-label {
-            font-size: 14px;
-            color: var(--text-secondary);
-        }
-
-        .metric-value {
-            font-size: 15px;
-            font-weight: 600;
-            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace;
-            color: var(--accent);
-        }
-
-        .metric-value.delta-up { color: var(--success); }
-        .metric-value.delta-down { color: var(--warning); }
-
-        .bar-row {
-            display: flex;
-            align-items: center;
-            padding: 6px 0;
-        }
-
-        .bar-label {
-            width: 90px;
-            font-size: 13px;
-            color: var(--text-secondary);
-        }
-
-        .bar-track {
-            flex: 1;
-            height: 8px;
-            background: var(--bg-tertiary);
-            border-radius: 4px;
-            overflow: hidden;
-            margin: 0 12px;
-        }
-
-        .bar-fill {
-            height: 100%;
-            background: linear-gradient(90deg, var(--accent), var(--success));
-            border-radius: 4px;
-        }
-
-        .bar-pct {
-            width: 42px;
-            text-align: right;
-            font-size: 13px;
-            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace;
-            color: var(--text-secondary);
-        }
-
-        table {
-            width: 100%;
-            border-collapse: collapse;
-            font-size: 14px;
-        }
-
-        th {
-            text-align: left;
-            padding: 10px 12px;
-            font-size: 12px;
-            font-weight: 600;
-            color: var(--text-secondary);
-            text-transform: uppercase;
-            letter-spacing: 0.5px;
-            border-bottom: 1px solid var(--border);
-        }
-
-        th:not(:first-child) {
-            text-align: right;
-        }
-
-        td {
-            padding: 10px 12px;
-            border-bottom: 1px solid var(--border);
-            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace;
-        }
-
-        td:not(:first-child) {
-            text-align: right;
-        }
-
-        tr:hover {
-            background: var(--bg-tertiary);
-        }
-
-        .max-row td {
-            color: var(--success);
-            font-weight: 600;
-        }
-
-        footer {
-            text-align: center;
-            padding: 24px;
-            color: var(--text-muted);
-            font-size: 12px;
-        }
-
-        footer a {
-            color: var(--accent);
-            text-decoration: none;
-        }
-
-        footer a:hover {
-            text-decoration: underline;
-        }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <header>
-            <div class="logo">$ gitrespect</div>
-            <h1>{{.Author}}</h1>
-            <div class="period">{{.Since}} — {{.Until}}</div>
-        </header>
-
-        <div class="stats-grid">
-            <div class="stat-card">
-                <div class="stat-label">Added</div>
-                <div class="stat-value added">+{{.Added}}</div>
-            </div>
-            <div class="stat-card">
-                <div class="stat-label">Deleted</div>
-                <div class="stat-value deleted">-{{.Deleted}}</div>
-            </div>
-            <div class="stat-card">
-                <div class="stat-label">Net</div>
-                <div class="stat-value net">{{.Net}}</div>
-            </div>
-            <div class="stat-card">
-                <div class="stat-label">Commits</div>
-                <div class="stat-value">{{.Commits}}</div>
-            </div>
-        </div>
-
-        {{if .Baseline}}
-        <div class="section">
-            <div class="section-title">Personal Baseline</div>
-            {{if .Baseline.Insufficient}}
-            <div class="metric-row">
-                <div class="metric-label">Baseline (prior {{.Baseline.WindowDays}} days)</div>
-                <div class="metric-value">insufficient history</div>
-            </div>
-            {{else}}
-            <div class="metric-row">
-                <div class="metric-label">Your normal ({{.Baseline.WindowDays}}d prior)</div>
-                <div class="metric-value">{{printf "%.0f" .Baseline.Normal}} lines/day</div>
-            </div>
-            <div class="metric-row">
-                <div class="metric-label">This period</div>
-                <div class="metric-value">{{printf "%.0f" .Baseline.Period}} lines/day</div>
-            </div>
-            <div class="metric-row">
-                <div class="metric-label">Change vs baseline</div>
-                <div class="metric-value {{if .Baseline.IsAbove}}delta-up{{else}}delta-down{{end}}">{{if .Baseline.IsAbove}}+{{end}}{{printf "%.0f" .Baseline.PercentDelta}}% {{if .Baseline.IsAbove}}&#8593;{{else}}&#8595;{{end}}</div>
-            </div>
-            {{end}}
-        </div>
-        {{end}}
-
-        {{if .CommitSize}}
-        <div class="section">
-            <div class="section-title">Commit Size Distribution</div>
-            <div class="bar-row">
-                <div class="bar-label">Micro (&lt;10)</div>
-                <div class="bar-track"><div class="bar-fill" style="width: {{printf "%.0f" .CommitSize.MicroPct}}%"></div></div>
-                <div class="bar-pct">{{printf "%.0f" .CommitSize.MicroPct}}%</div>
-            </div>
-            <div class="bar-row">
-                <div class="bar-label">Small (10-99)</div>
-                <div class="bar-track"><div class="bar-fill" style="width: {{printf "%.0f" .CommitSize.SmallPct}}%"></div></div>
-                <div class="bar-pct">{{printf "%.0f" .CommitSize.SmallPct}}%</div>
-            </div>
-            <div class="bar-row">
-                <div class="bar-label">Medium (100-499)</div>
-                <div class="bar-track"><div class="bar-fill" style="width: {{printf "%.0f" .CommitSize.MediumPct}}%"></div></div>
-                <div class="bar-pct">{{printf "%.0f" .CommitSize.MediumPct}}%</div>
-            </div>
-            <div class="bar-row">
-                <div class="bar-label">Large (500+)</div>
-                <div class="bar-track"><div class="bar-fill" style="width: {{printf "%.0f" .CommitSize.LargePct}}%"></div></div>
-                <div class="bar-pct">{{printf "%.0f" .CommitSize.LargePct}}%</div>
-            </div>
-        </div>
-        {{end}}
-
-        {{if or .Cadence .LeadTime .Churn}}
-        <div class="section">
-            <div class="section-title">Flow &amp; Quality Metrics</div>
-            {{if .Cadence}}
-            <div class="metric-row">
-                <div class="metric-label">Integration cadence (median)</div>
-                <div class="metric-value">{{printf "%.1f" .Cadence.MedianDays}} days</div>
-            </div>
-            {{end}}
-            {{if .LeadTime}}
-            <div class="metric-row">
-                <div class="metric-label">Lead time branch &#8594; main (median)</div>
-                <div class="metric-value">{{printf "%.1f" .LeadTime.MedianDays}} days</div>
-            </div>
-            {{end}}
-            {{if .Churn}}
-            <div class="metric-row">
-                <div class="metric-label">Churn ({{.Churn.WindowDays}}d rewrite rate)</div>
-                <div class="metric-value">{{printf "%.0f" .Churn.Ratio}}%</div>
-            </div>
-            {{end}}
-        </div>
-        {{end}}
-
-        <div class="section">
-            <div class="section-title">Daily Output</div>
-            <div class="daily-stat">{{printf "%.0f" .PerDay}}</div>
-            <div class="daily-label">lines/day ({{.WorkingDays}} working days)</div>
-        </div>
-
-
-        {{if .HasMonthly}}
-        <div class="section">
-            <div class="section-title">Monthly Breakdown</div>
-            <table>
-                <thead>
-                    <tr>
-                        <th>Month</th>
-                        <th>Added</th>
-                        <th>Deleted</th>
-                        <th>Net</th>
-                    </tr>
-                </thead>
-                <tbody>
-                    {{range .Monthly}}
-                    <tr{{if .IsMax}} class="max-row"{{end}}>
-                        <td>{{.Month}} {{.Year}}</td>
-                        <td>+{{.Added}}</td>
-                        <td>-{{.Deleted}}</td>
-                        <td>{{.Net}}</td>
-                    </tr>
-                    {{end}}
-                </tbody>
-            </table>
-        </div>
-        {{end}}
-
-        <footer>
-            Generated by <a href="https://github.com/juangracia/gitrespect">gitrespect</a>
-        </footer>
-    </div>
-</body>
-</html>`
-
-const compareHtmlTemplate = `<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>gitrespect - Period Comparison</title>
-    <style>
-        :root {
-            {{if .IsDark}}
-            --bg-primary: #0d1117;
-            --bg-secondary: #161b22;
-            --border: #30363d;
-            --text-primary: #c9d1d9;
-            --text-secondary: #8b949e;
-            --accent: #58a6ff;
-            --success: #3fb950;
-            {{else}}
-            --bg-primary: #ffffff;
-            --bg-secondary: #f6f8fa;
-            --border: #d0d7de;
-            --text-primary: #1f2328;
-            --text-secondary: #656d76;
-            --accent: #0969da;
-            --success: #1a7f37;
-            {{end}}
-        }
-
-        * { margin: 0; padding: 0; box-sizing: border-box; }
-
-        body {
-            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', 'Noto Sans', Helvetica, Arial, sans-serif;
-            background: var(--bg-primary);
-            color: var(--text-primary);
-            min-height: 100vh;
-            display: flex;
-            align-items: center;
-            justify-content: center;
-            padding: 24px;
-        }
-
-        .card {
-            background: var(--bg-secondary);
-            border: 1px solid var(--border);
-            border-radius: 12px;
-            padding: 32px;
-            max-width: 500px;
-            width: 100%;
-        }
-
-        .logo {
-            font-size: 14px;
-            font-weight: 600;
-            color: var(--text-secondary);
-            margin-bottom: 24px;
-            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace;
-        }
-
-        h1 {
-            font-size: 20px;
-            margin-bottom: 24px;
-        }
-
-        .comparison {
-            display: grid;
-            grid-template-columns: 1fr 1fr;
-            gap: 24px;
-            margin-bottom: 32px;
-        }
-
-        .period-card {
-            padding: 16px;
-            border-radius: 8px;
-            background: var(--bg-primary);
-        }
-
-        .period-label {
-            font-size: 12px;
-            color: var(--text-secondary);
-            margin-bottom: 8px;
-        }
-
-        .period-value {
-            font-size: 28px;
-            font-weight: 600;
-            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace;
-        }
-
-        .period-value.after { color: var(--success); }
-
-        .period-perday {
-            font-size: 14px;
-            color: var(--text-secondary);
-            margin-top: 4px;
-        }
-
-        .result {
-            text-align: center;
-            padding: 24px;
-            background: linear-gradient(135deg, rgba(56, 139, 253, 0.1), rgba(63, 185, 80, 0.1));
-            border-radius: 8px;
-        }
-
-        .multiplier {
-            font-size: 48px;
-            font-weight: 700;
-            color: var(--success);
-            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace;
-        }
-
-        .result-label {
-            color: var(--text-secondary);
-            font-size: 14px;
-            margin-top: 8px;
-        }
-
-        footer {
-            text-align: center;
-            margin-top: 24px;
-            font-size: 12px;
-            color: var(--text-secondary);
-        }
-
-        footer a {
-            color: var(--accent);
-            text-decoration: none;
-        }
-    </style>
-</head>
-<body>
-    <div class="card">
-        <div class="logo">$ gitrespect compare</div>
-        <h1>Productivity Comparison</h1>
-
-        <div class="comparison">
-            <div class="period-card">
-                <div class="period-label">{{.BeforeLabel}}</div>
-                <div class="period-value">{{.BeforeNet}}</div>
-                <div class="period-perday">{{printf "%.0f" .BeforePerDay}} lines/day</div>
-            </div>
-            <div class="period-card">
-                <div class="period-label">{{.AfterLabel}}</div>
-                <div class="period-value after">{{.AfterNet}}</div>
-                <div class="period-perday">{{printf "%.0f" .AfterPerDay}} lines/day</div>
-            </div>
-        </div>
+// ComparePeriodHTMLData is one period of the comparison table and chart.
+type ComparePeriodHTMLData struct {
+	Label         string
+	Net           int
+	WorkingDays   int
+	PerDay        float64
+	BarPct        float64
+	Multiplier    float64
+	HasMultiplier bool
+	IsFirst       bool
+	PValue        float64
+	Significant   bool
+	Insufficient  bool
+}
 
-        <div class="result">
-            <div class="multiplier">{{printf "%.1f" .Multiplier}}x {{.ChangeEmoji}}</div>
-            <div class="result-label">productivity increase</div>
-        </div>
+// CompareMetricHTMLData is one metric row with a formatted cell per period.
+type CompareMetricHTMLData struct {
+	Label string
+	Cells []string
+}
 
-        <footer>
-            Generated by <a href="https://github.com/juangracia/gitrespect">gitrespect</a>
-        </footer>
-    </div>
-</body>
-</html>`
+// CompareRowHTMLData is a per-repo or per-member line of a comparison.
+type CompareRowHTMLData struct {
+	Name    string
+	Cells   []string
+	Metrics []CompareMetricHTMLData
+}
 
-func HTML(stats git.RepoStats, filename string, breakdown string, theme string, bundle metrics.Bundle) error {
+func HTML(stats git.RepoStats, filename string, breakdown string, style HTMLStyle, bundle metrics.Bundle) error {
 	workingDays := git.WorkingDays(stats.Since, stats.Until)
 	locPerDay := float64(stats.Net) / float64(workingDays)
 
-	isDark := theme != "light"
+	page, err := style.page()
+	if err != nil {
+		return err
+	}
 
 	data := HTMLData{
 		Author:      stats.Author,
@@ -671,46 +247,43 @@ func HTML(stats git.RepoStats, filename string, breakdown string, theme string,
 		WorkingDays: workingDays,
 		PerDay:      locPerDay,
 		HasMonthly:  breakdown == "monthly" && len(stats.Monthly) > 0,
-		Theme:       theme,
-		IsDark:      isDark,
+		HTMLPage:    page,
 	}
 
-	if bundle.Baseline != nil {
-		b := bundle.Baseline
-		data.Baseline = &BaselineHTMLData{
-			WindowDays:   int(b.WindowEnd.Sub(b.WindowStart).Hours() / 24),
-			Normal:       b.LOCPerDay,
-			Period:       b.PeriodLOCPerDay,
-			PercentDelta: b.PercentDelta,
-			IsAbove:      b.PercentDelta >= 0,
-			Insufficient: b.InsufficientHistory,
-		}
-	}
-	if bundle.CommitSize != nil && bundle.CommitSize.Total > 0 {
-		d := bundle.CommitSize
-		data.CommitSize = &CommitSizeHTMLData{
-			MicroPct:  d.Percent(metrics.BucketMicro),
-			SmallPct:  d.Percent(metrics.BucketSmall),
-			MediumPct: d.Percent(metrics.BucketMedium),
-			LargePct:  d.Percent(metrics.BucketLarge),
-		}
+	data.Baseline = baselineHTML(bundle.Baseline, "your normal range")
+	data.RepoLabels, data.RepoMetrics = repoMetricsHTML(bundle)
+	data.Diagnostics = diagnosticsHTML(bundle.Diagnostics)
+	data.Excluded = len(stats.Excluded)
+	for _, o := range bundle.Outliers {
+		data.Outliers = append(data.Outliers, changeHTML(o.Commit, strings.Join(o.Reasons, "; ")))
 	}
+	data.Explain = explainHTML(bundle.Explain)
+	data.Interactive = reportInteractiveHTML(stats, bundle)
+	data.CommitSize = commitSizeHTML(bundle)
 	if bundle.Cadence != nil && bundle.Cadence.Samples >= 2 {
 		data.Cadence = &CadenceHTMLData{
 			MedianDays: bundle.Cadence.MedianDaysBetween,
 			Samples:    bundle.Cadence.Samples,
+			Branch:     bundle.Cadence.MainBranch,
+			Normal:     normalNote(bundle, keyCadence),
 		}
 	}
 	if bundle.LeadTime != nil && bundle.LeadTime.Samples > 0 {
 		data.LeadTime = &LeadTimeHTMLData{
 			MedianDays: bundle.LeadTime.MedianDays,
 			Samples:    bundle.LeadTime.Samples,
+			Branch:     bundle.LeadTime.MainBranch,
+			Methods:    leadMethods(bundle.LeadTime),
+			Normal:     normalNote(bundle, keyLeadTime),
 		}
 	}
+	data.Deploy = deployHTML(bundle)
+	data.Reverts = revertsHTML(bundle)
 	if bundle.Churn != nil && bundle.Churn.AddedLines > 0 {
 		data.Churn = &ChurnHTMLData{
 			Ratio:      bundle.Churn.Ratio * 100,
 			WindowDays: bundle.Churn.WindowDays,
+			Normal:     normalNote(bundle, keyChurn),
 		}
 	}
 
@@ -741,85 +314,287 @@ func HTML(stats git.RepoStats, filename string, breakdown string, theme string,
 		}
 	}
 
-	tmpl, err := template.New("report").Parse(htmlTemplate)
-	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
-	}
-
 	if filename == "" {
 		filename = "gitrespect-report.html"
 	}
+	return style.write("report.html", filename, data)
+}
 
-	f, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+// baselineHTML converts a baseline for the templates; nil stays nil.
+func baselineHTML(b *metrics.Baseline, normalRange string) *BaselineHTMLData {
+	if b == nil {
+		return nil
+	}
+	data := &BaselineHTMLData{
+		WindowDays:   int(b.WindowEnd.Sub(b.WindowStart).Hours() / 24),
+		Normal:       b.LOCPerDay,
+		Low:          b.P25,
+		High:         b.P75,
+		Period:       b.PeriodLOCPerDay,
+		Insufficient: b.InsufficientHistory,
+	}
+	data.PositionText, data.PositionClass = positionHTML(b.Position, normalRange)
+	if s := b.Seasonal; s != nil {
+		data.Seasonal = &SeasonalHTMLData{
+			Normal:       s.Median,
+			Low:          s.P25,
+			High:         s.P75,
+			Insufficient: s.Insufficient,
+		}
+		data.Seasonal.PositionText, data.Seasonal.PositionClass = positionHTML(s.Position, "last year's range")
 	}
-	defer f.Close()
+	return data
+}
+
+// deployHTML returns the commit-to-deploy section of a bundle, or nil when
+// no deploy shipped its commits.
+func deployHTML(b metrics.Bundle) *DeployHTMLData {
+	d := b.Deploy
+	if d == nil || d.Samples == 0 {
+		return nil
+	}
+	return &DeployHTMLData{
+		Source:     d.Source,
+		MedianDays: d.MedianDays,
+		Samples:    d.Samples,
+		Deploys:    d.Deploys,
+		PerWeek:    d.PerWeek,
+		Normal:     normalNote(b, keyDeployLead),
+		RateNormal: normalNote(b, keyDeployFreq),
+	}
+}
 
-	if err := tmpl.Execute(f, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+// diagnosticsHTML converts the notable diagnostics for the Diagnostics section.
+func diagnosticsHTML(diags []metrics.Diagnostic) []DiagnosticHTMLData {
+	var out []DiagnosticHTMLData
+	for _, d := range notableDiagnostics(diags) {
+		out = append(out, DiagnosticHTMLData{Text: shortDiagnostic(d), IsError: d.Status == metrics.StatusError})
 	}
+	return out
+}
 
-	fmt.Printf("✓ Report saved to %s\n", filename)
-	return nil
+func changeHTML(c git.CommitChange, note string) ChangeHTMLData {
+	return ChangeHTMLData{Hash: shortHash(c.Hash), Date: c.Date.Format("Jan 2"), Subject: c.Subject, Added: c.Added, Deleted: c.Deleted, Note: note}
 }
 
-func CompareHTML(comparison git.CompareStats, filename string, theme string) error {
-	beforeDays := git.WorkingDays(comparison.Before.Since, comparison.Before.Until)
-	afterDays := git.WorkingDays(comparison.After.Since, comparison.After.Until)
+// explainHTML returns the --explain section, or nil without one.
+func explainHTML(ex *git.Explanation) *ExplainHTMLData {
+	if ex == nil {
+		return nil
+	}
+	out := &ExplainHTMLData{Title: explainTitle(ex.Bucket), Added: ex.Added, Deleted: ex.Deleted, Net: ex.Net, Commits: ex.Commits}
+	changed := ex.Added + ex.Deleted
+	for _, c := range ex.Top {
+		out.Top = append(out.Top, changeHTML(c, sharePct(c.Lines(), changed)))
+	}
+	for _, f := range ex.Files {
+		out.Files = append(out.Files, ExplainFileHTMLData{
+			Path: f.Path, Added: f.Added, Deleted: f.Deleted,
+			Note: fmt.Sprintf("%d %s, %s", f.Commits, plural(f.Commits, "commit"), sharePct(f.Lines(), changed)),
+		})
+	}
+	return out
+}
 
-	beforePerDay := float64(comparison.Before.Net) / float64(beforeDays)
-	afterPerDay := float64(comparison.After.Net) / float64(afterDays)
+// commitSizeHTML returns the commit size histogram of a bundle, or nil when
+// it has no commits.
+func commitSizeHTML(b metrics.Bundle) *CommitSizeHTMLData {
+	d := b.CommitSize
+	if d == nil || d.Total == 0 {
+		return nil
+	}
+	tallest := 1
+	for i := range d.Counts {
+		tallest = max(tallest, d.Counts[i], d.Added.Counts[i], d.Deleted.Counts[i])
+	}
+	height := func(n int) float64 { return float64(n) * 100 / float64(tallest) }
+	out := &CommitSizeHTMLData{
+		P50: d.P50, P90: d.P90, P99: d.P99,
+		P50Normal: normalNote(b, keyCommitP50),
+		Added:     d.Added,
+		Deleted:   d.Deleted,
+	}
+	for i, n := range d.Counts {
+		out.Buckets = append(out.Buckets, SizeBucketHTMLData{
+			Label:         sizeBucketName(d, i),
+			Count:         n,
+			Pct:           d.Percent(i),
+			Height:        height(n),
+			AddedHeight:   height(d.Added.Counts[i]),
+			DeletedHeight: height(d.Deleted.Counts[i]),
+			Normal:        normalNote(b, sizeBucketKey(d, i)),
+		})
+	}
+	for _, c := range d.Largest {
+		out.Largest = append(out.Largest, LargestCommitHTMLData{Hash: shortHash(c.Hash), Subject: c.Subject, Added: c.Added, Deleted: c.Deleted})
+	}
+	return out
+}
 
-	multiplier := benchmark.CalculateMultiplier(beforePerDay, afterPerDay)
+// revertsHTML returns the reverts section of a bundle, or nil when it has
+// no commits.
+func revertsHTML(b metrics.Bundle) *RevertsHTMLData {
+	r := b.Reverts
+	if r == nil || r.Commits == 0 {
+		return nil
+	}
+	return &RevertsHTMLData{
+		Commits:      r.Commits,
+		Reverted:     r.Reverted,
+		Fixed:        r.Fixed,
+		RevertPct:    r.RevertRate * 100,
+		FixPct:       r.FixRate * 100,
+		DaysToRevert: r.MedianDaysToRevert,
+		DaysToFix:    r.MedianDaysToFix,
+		WindowDays:   r.WindowDays,
+		Normal:       normalNote(b, keyRevertRate),
+		FixNormal:    normalNote(b, keyFixRate),
+	}
+}
 
+// positionHTML returns the label and CSS class for a baseline band position.
+func positionHTML(position, band string) (string, string) {
+	switch position {
+	case metrics.PositionAbove:
+		return "above " + band + " ↑", "delta-up"
+	case metrics.PositionBelow:
+		return "below " + band + " ↓", "delta-down"
+	default:
+		return "within " + band, ""
+	}
+}
+
+func CompareHTML(comparison git.CompareStats, filename string, style HTMLStyle, details CompareDetails) error {
+	periods := periodStats(comparison.Periods)
+	labels := periodLabels(comparison.Periods)
+	if len(periods) == 0 {
+		return fmt.Errorf("no periods to compare")
+	}
+	last := len(periods) - 1
+	sig := details.SignificanceAt(last)
+	multiplier := changeVsFirst(periods, sig, last)
+
+	// Only celebrate changes that stand out from week-to-week noise.
 	emoji := ""
-	if multiplier >= 5 {
-		emoji = "🚀"
-	} else if multiplier >= 2 {
-		emoji = "📈"
+	if sig.Significant() {
+		if multiplier >= 5 {
+			emoji = "🚀"
+		} else if multiplier >= 2 {
+			emoji = "📈"
+		}
 	}
 
-	isDark := theme != "light"
+	page, err := style.page()
+	if err != nil {
+		return err
+	}
 
 	data := CompareHTMLData{
-		BeforeLabel:  comparison.BeforeLabel,
-		AfterLabel:   comparison.AfterLabel,
-		BeforeNet:    comparison.Before.Net,
-		AfterNet:     comparison.After.Net,
-		BeforeDays:   beforeDays,
-		AfterDays:    afterDays,
-		BeforePerDay: beforePerDay,
-		AfterPerDay:  afterPerDay,
-		Multiplier:   multiplier,
-		ChangeEmoji:  emoji,
-		Theme:        theme,
-		IsDark:       isDark,
+		FirstLabel:    labels[0],
+		LastLabel:     labels[last],
+		Multiplier:    multiplier,
+		ChangeEmoji:   emoji,
+		HTMLPage:      page,
+		Significance:  sig,
+		HasCI:         sig.RatioLow != 0 || sig.RatioHigh != 0,
+		ConfidencePct: sig.Confidence * 100,
+		MinWeeks:      metrics.MinSignificanceWeeks,
+		Summary:       fmt.Sprintf("Not statistically significant (p=%.3f): this change is within normal week-to-week variation.", sig.PValue),
+		Labels:        labels,
+		Metrics:       compareMetricsHTML(metricTrend(details.Metrics)),
+		Diagnostics:   diagnosticsHTML(details.Diagnostics),
 	}
 
-	tmpl, err := template.New("compare").Parse(compareHtmlTemplate)
-	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+	peak := 0.0
+	for _, p := range periods {
+		if v := perDay(p); v > peak {
+			peak = v
+		}
+	}
+	for i, p := range periods {
+		pd := ComparePeriodHTMLData{
+			Label:       labels[i],
+			Net:         p.Net,
+			WorkingDays: git.WorkingDays(p.Since, p.Until),
+			PerDay:      perDay(p),
+			IsFirst:     i == 0,
+		}
+		if peak > 0 && pd.PerDay > 0 {
+			pd.BarPct = pd.PerDay / peak * 100
+		}
+		if i > 0 {
+			s := details.SignificanceAt(i)
+			pd.Multiplier = multiplierVsFirst(periods, i)
+			pd.HasMultiplier = pd.Multiplier != 0
+			pd.PValue = s.PValue
+			pd.Significant = s.Significant()
+			pd.Insufficient = s.Insufficient
+		}
+		data.Periods = append(data.Periods, pd)
+	}
+
+	for _, r := range details.Repos {
+		data.Repos = append(data.Repos, compareRowHTML(filepath.Base(r.Path), r.Periods, nil))
+	}
+	for _, m := range details.Members {
+		data.Members = append(data.Members, compareRowHTML(m.Email, m.Periods, m.Metrics))
 	}
 
 	if filename == "" {
 		filename = "gitrespect-compare.html"
 	}
+	return style.write("compare.html", filename, data)
+}
 
-	f, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+func compareMetricsHTML(series []metricSeries) []CompareMetricHTMLData {
+	var out []CompareMetricHTMLData
+	for _, s := range series {
+		row := CompareMetricHTMLData{Label: s.Label}
+		for i := range s.Values {
+			cell := formatMetricValue(s.Values[i], s.Unit, s.Has[i])
+			if i > 0 {
+				cell += " (" + formatMetricDelta(s, i) + ")"
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		out = append(out, row)
 	}
-	defer f.Close()
+	return out
+}
 
-	if err := tmpl.Execute(f, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+// repoMetricsHTML returns the per-repo metric table of a bundle: one column
+// per repo, one row per metric. Empty without --per-repo.
+func repoMetricsHTML(b metrics.Bundle) ([]string, []CompareMetricHTMLData) {
+	labels, series := repoMetricTrend(b)
+	var rows []CompareMetricHTMLData
+	for _, s := range series {
+		row := CompareMetricHTMLData{Label: s.Label}
+		for i := range labels {
+			row.Cells = append(row.Cells, formatMetricValue(s.Values[i], s.Unit, s.Has[i]))
+		}
+		rows = append(rows, row)
 	}
+	return labels, rows
+}
 
-	fmt.Printf("✓ Report saved to %s\n", filename)
-	return nil
+func compareRowHTML(name string, periods []git.RepoStats, bundles []metrics.Bundle) CompareRowHTMLData {
+	row := CompareRowHTMLData{
+		Name:    name,
+		Metrics: compareMetricsHTML(metricTrend(bundles)),
+	}
+	for i, p := range periods {
+		cell := fmt.Sprintf("%.0f", perDay(p))
+		if i > 0 {
+			if m := multiplierVsFirst(periods, i); m != 0 {
+				cell += fmt.Sprintf(" (%.1fx)", m)
+			}
+		}
+		row.Cells = append(row.Cells, cell)
+	}
+	return row
 }
 
+// TeamHTMLData feeds the team.html template.
 type TeamHTMLData struct {
 	Since            string
 	Until            string
@@ -833,200 +608,43 @@ type TeamHTMLData struct {
 	HasMonthly       bool
 	Monthly          []MonthlyHTMLData
 	HasMemberMetrics bool
-	Theme            string
-	IsDark           bool
+	Baseline         *BaselineHTMLData
+	Deploy           *DeployHTMLData
+	HasBaselines     bool
+	Diagnostics      []DiagnosticHTMLData
+	Interactive      *InteractiveHTMLData
+	HTMLPage
 }
 
+// TeamMemberHTMLData is one member of a team report: their totals, and
+// metrics when computed.
 type TeamMemberHTMLData struct {
-	Email      string
-	Added      int
-	Deleted    int
-	Net        int
-	Commits    int
-	PerDay     float64
-	IsTop      bool
-	HasMetrics bool
-	CommitSize *CommitSizeHTMLData
-	Cadence    *CadenceHTMLData
-	LeadTime   *LeadTimeHTMLData
-	Churn      *ChurnHTMLData
+	Email       string
+	Added       int
+	Deleted     int
+	Net         int
+	Commits     int
+	PerDay      float64
+	IsTop       bool
+	Baseline    *BaselineHTMLData
+	RepoLabels  []string
+	RepoMetrics []CompareMetricHTMLData
+	HasMetrics  bool
+	CommitSize  *CommitSizeHTMLData
+	Cadence     *CadenceHTMLData
+	LeadTime    *LeadTimeHTMLData
+	Deploy      *DeployHTMLData
+	Churn       *ChurnHTMLData
+	Reverts     *RevertsHTMLData
 }
 
-const teamHtmlTemplate = `<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>gitrespect - Team Report</title>
-    <style>
-        :root {
-            {{if .IsDark}}
-            --bg-primary: #0d1117;
-            --bg-secondary: #161b22;
-            --bg-tertiary: #21262d;
-            --border: #30363d;
-            --text-primary: #c9d1d9;
-            --text-secondary: #8b949e;
-            --text-muted: #484f58;
-            --accent: #58a6ff;
-            --success: #3fb950;
-            --warning: #d29922;
-            {{else}}
-            --bg-primary: #ffffff;
-            --bg-secondary: #f6f8fa;
-            --bg-tertiary: #eaeef2;
-            --border: #d0d7de;
-            --text-primary: #1f2328;
-            --text-secondary: #656d76;
-            --text-muted: #8c959f;
-            --accent: #0969da;
-            --success: #1a7f37;
-            --warning: #9a6700;
-            {{end}}
-        }
-
-        * { margin: 0; padding: 0; box-sizing: border-box; }
-
-        body {
-            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', 'Noto Sans', Helvetica, Arial, sans-serif;
-            background: var(--bg-primary);
-            color: var(--text-primary);
-            line-height: 1.5;
-            min-height: 100vh;
-        }
-
-        .container { max-width: 900px; margin: 0 auto; padding: 32px 24px; }
-
-        header { margin-bottom: 32px; padding-bottom: 16px; border-bottom: 1px solid var(--border); }
-        .logo { font-size: 14px; font-weight: 600; color: var(--text-secondary); margin-bottom: 8px; font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace; }
-        h1 { font-size: 24px; font-weight: 600; }
-        .period { font-size: 14px; color: var(--text-secondary); margin-top: 4px; }
-
-        .stats-grid { display: grid; grid-template-columns: repeat(4, 1fr); gap: 16px; margin-bottom: 32px; }
-        @media (max-width: 640px) { .stats-grid { grid-template-columns: repeat(2, 1fr); } }
-
-        .stat-card { background: var(--bg-secondary); border: 1px solid var(--border); border-radius: 6px; padding: 16px; }
-        .stat-label { font-size: 12px; color: var(--text-secondary); text-transform: uppercase; letter-spacing: 0.5px; margin-bottom: 4px; }
-        .stat-value { font-size: 28px; font-weight: 600; font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace; }
-        .stat-value.added { color: var(--success); }
-        .stat-value.deleted { color: var(--warning); }
-        .stat-value.net { color: var(--accent); }
-
-        .section { background: var(--bg-secondary); border: 1px solid var(--border); border-radius: 6px; padding: 20px; margin-bottom: 24px; }
-        .section-title { font-size: 14px; font-weight: 600; color: var(--text-secondary); margin-bottom: 16px; text-transform: uppercase; letter-spacing: 0.5px; }
-        .daily-stat { font-size: 32px; font-weight: 600; color: var(--accent); font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace; }
-        .daily-label { color: var(--text-secondary); font-size: 14px; }
-
-        table { width: 100%; border-collapse: collapse; font-size: 14px; }
-        th { text-align: left; padding: 10px 12px; font-size: 12px; font-weight: 600; color: var(--text-secondary); text-transform: uppercase; letter-spacing: 0.5px; border-bottom: 1px solid var(--border); }
-        th:not(:first-child) { text-align: right; }
-        td { padding: 10px 12px; border-bottom: 1px solid var(--border); font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace; }
-        td:not(:first-child) { text-align: right; }
-        tr:hover { background: var(--bg-tertiary); }
-        .top-row td { color: var(--success); font-weight: 600; }
-
-        .member-card { background: var(--bg-tertiary); border: 1px solid var(--border); border-radius: 6px; padding: 16px; margin-bottom: 16px; }
-        .member-card:last-child { margin-bottom: 0; }
-        .member-card-title { font-size: 14px; font-weight: 600; color: var(--text-primary); margin-bottom: 12px; font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace; }
-        .member-subtitle { font-size: 11px; font-weight: 600; color: var(--text-secondary); text-transform: uppercase; letter-spacing: 0.5px; margin: 8px 0 4px; }
-
-        .metric-row { display: flex; align-items: center; justify-content: space-between; padding: 8px 0; border-bottom: 1px solid var(--border); }
-        .metric-row:last-child { border-bottom: none; }
-        .metric-label { font-size: 14px; color: var(--text-secondary); }
-        .metric-value { font-size: 15px; font-weight: 600; font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace; color: var(--accent); }
-
-        .bar-row { display: flex; align-items: center; padding: 5px 0; }
-        .bar-label { width: 130px; font-size: 13px; color: var(--text-secondary); }
-        .bar-track { flex: 1; height: 8px; background: var(--bg-secondary); border-radius: 4px; overflow: hidden; margin: 0 12px; }
-        .bar-fill { height: 100%; background: linear-gradient(90deg, var(--accent), var(--success)); border-radius: 4px; }
-        .bar-pct { width: 42px; text-align: right; font-size: 13px; font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, monospace; color: var(--text-secondary); }
-
-        footer { text-align: center; padding: 24px; color: var(--text-muted); font-size: 12px; }
-        footer a { color: var(--accent); text-decoration: none; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <header>
-            <div class="logo">$ gitrespect --team</div>
-            <h1>Team Report</h1>
-            <div class="period">{{.Since}} — {{.Until}}</div>
-        </header>
-
-        <div class="stats-grid">
-            <div class="stat-card"><div class="stat-label">Team Added</div><div class="stat-value added">+{{.TotalAdded}}</div></div>
-            <div class="stat-card"><div class="stat-label">Team Deleted</div><div class="stat-value deleted">-{{.TotalDeleted}}</div></div>
-            <div class="stat-card"><div class="stat-label">Team Net</div><div class="stat-value net">{{.TotalNet}}</div></div>
-            <div class="stat-card"><div class="stat-label">Team Commits</div><div class="stat-value">{{.TotalCommits}}</div></div>
-        </div>
-
-        <div class="section">
-            <div class="section-title">Team Daily Output</div>
-            <div class="daily-stat">{{printf "%.0f" .PerDay}}</div>
-            <div class="daily-label">lines/day ({{.WorkingDays}} working days)</div>
-        </div>
-
-        <div class="section">
-            <div class="section-title">Team Members</div>
-            <table>
-                <thead><tr><th>Contributor</th><th>Added</th><th>Deleted</th><th>Net</th><th>Commits</th><th>/Day</th></tr></thead>
-                <tbody>
-                    {{range .Members}}
-                    <tr{{if .IsTop}} class="top-row"{{end}}><td>{{.Email}}</td><td>+{{.Added}}</td><td>-{{.Deleted}}</td><td>{{.Net}}</td><td>{{.Commits}}</td><td>{{printf "%.0f" .PerDay}}</td></tr>
-                    {{end}}
-                </tbody>
-            </table>
-        </div>
-
-        {{if .HasMemberMetrics}}
-        <div class="section">
-            <div class="section-title">Per-Member Metrics</div>
-            {{range .Members}}
-            {{if .HasMetrics}}
-            <div class="member-card">
-                <div class="member-card-title">{{.Email}}</div>
-                {{if .CommitSize}}
-                <div class="member-subtitle">Commit Size Distribution</div>
-                <div class="bar-row"><div class="bar-label">Micro (&lt;10)</div><div class="bar-track"><div class="bar-fill" style="width: {{printf "%.0f" .CommitSize.MicroPct}}%"></div></div><div class="bar-pct">{{printf "%.0f" .CommitSize.MicroPct}}%</div></div>
-                <div class="bar-row"><div class="bar-label">Small (10-99)</div><div class="bar-track"><div class="bar-fill" style="width: {{printf "%.0f" .CommitSize.SmallPct}}%"></div></div><div class="bar-pct">{{printf "%.0f" .CommitSize.SmallPct}}%</div></div>
-                <div class="bar-row"><div class="bar-label">Medium (100-499)</div><div class="bar-track"><div class="bar-fill" style="width: {{printf "%.0f" .CommitSize.MediumPct}}%"></div></div><div class="bar-pct">{{printf "%.0f" .CommitSize.MediumPct}}%</div></div>
-                <div class="bar-row"><div class="bar-label">Large (500+)</div><div class="bar-track"><div class="bar-fill" style="width: {{printf "%.0f" .CommitSize.LargePct}}%"></div></div><div class="bar-pct">{{printf "%.0f" .CommitSize.LargePct}}%</div></div>
-                {{end}}
-                {{if or .Cadence .LeadTime .Churn}}
-                <div class="member-subtitle">Flow &amp; Quality</div>
-                {{if .Cadence}}<div class="metric-row"><div class="metric-label">Integration cadence (median)</div><div class="metric-value">{{printf "%.1f" .Cadence.MedianDays}} days</div></div>{{end}}
-                {{if .LeadTime}}<div class="metric-row"><div class="metric-label">Lead time branch &#8594; main (median)</div><div class="metric-value">{{printf "%.1f" .LeadTime.MedianDays}} days</div></div>{{end}}
-                {{if .Churn}}<div class="metric-row"><div class="metric-label">Churn ({{.Churn.WindowDays}}d rewrite rate)</div><div class="metric-value">{{printf "%.0f" .Churn.Ratio}}%</div></div>{{end}}
-                {{end}}
-            </div>
-            {{end}}
-            {{end}}
-        </div>
-        {{end}}
-
-        {{if .HasMonthly}}
-        <div class="section">
-            <div class="section-title">Team Monthly Breakdown</div>
-            <table>
-                <thead><tr><th>Month</th><th>Added</th><th>Deleted</th><th>Net</th></tr></thead>
-                <tbody>
-                    {{range .Monthly}}
-                    <tr{{if .IsMax}} class="top-row"{{end}}><td>{{.Month}} {{.Year}}</td><td>+{{.Added}}</td><td>-{{.Deleted}}</td><td>{{.Net}}</td></tr>
-                    {{end}}
-                </tbody>
-            </table>
-        </div>
-        {{end}}
-
-        <footer>Generated by <a href="https://github.com/juangracia/gitrespect">gitrespect</a></footer>
-    </div>
-</body>
-</html>`
-
-func TeamHTML(stats git.TeamStats, filename string, theme string, breakdown string, bundles map[string]metrics.Bundle) error {
+func TeamHTML(stats git.TeamStats, filename string, style HTMLStyle, breakdown string, team metrics.Bundle, bundles map[string]metrics.Bundle) error {
 	workingDays := git.WorkingDays(stats.Since, stats.Until)
 
-	isDark := theme != "light"
+	page, err := style.page()
+	if err != nil {
+		return err
+	}
 
 	data := TeamHTMLData{
 		Since:        stats.Since.Format("Jan 2, 2006"),
@@ -1037,8 +655,12 @@ func TeamHTML(stats git.TeamStats, filename string, theme string, breakdown stri
 		TotalCommits: stats.TotalCommits,
 		WorkingDays:  workingDays,
 		PerDay:       float64(stats.TotalNet) / float64(workingDays),
-		Theme:        theme,
-		IsDark:       isDark,
+		Baseline:     baselineHTML(team.Baseline, "team normal range"),
+		Deploy:       deployHTML(team),
+		HasBaselines: hasMemberBaselines(bundles),
+		Diagnostics:  diagnosticsHTML(team.Diagnostics),
+		Interactive:  teamInteractiveHTML(stats, bundles),
+		HTMLPage:     page,
 	}
 
 	// Sort members by net lines descending
@@ -1065,25 +687,21 @@ func TeamHTML(stats git.TeamStats, filename string, theme string, breakdown stri
 			IsTop:   i == 0,
 		}
 		if b, ok := bundles[m.email]; ok {
-			if b.CommitSize != nil && b.CommitSize.Total > 0 {
-				d := b.CommitSize
-				md.CommitSize = &CommitSizeHTMLData{
-					MicroPct:  d.Percent(metrics.BucketMicro),
-					SmallPct:  d.Percent(metrics.BucketSmall),
-					MediumPct: d.Percent(metrics.BucketMedium),
-					LargePct:  d.Percent(metrics.BucketLarge),
-				}
-			}
+			md.Baseline = baselineHTML(b.Baseline, "their normal range")
+			md.RepoLabels, md.RepoMetrics = repoMetricsHTML(b)
+			md.CommitSize = commitSizeHTML(b)
 			if b.Cadence != nil && b.Cadence.Samples >= 2 {
-				md.Cadence = &CadenceHTMLData{MedianDays: b.Cadence.MedianDaysBetween, Samples: b.Cadence.Samples}
+				md.Cadence = &CadenceHTMLData{MedianDays: b.Cadence.MedianDaysBetween, Samples: b.Cadence.Samples, Branch: b.Cadence.MainBranch, Normal: normalNote(b, keyCadence)}
 			}
 			if b.LeadTime != nil && b.LeadTime.Samples > 0 {
-				md.LeadTime = &LeadTimeHTMLData{MedianDays: b.LeadTime.MedianDays, Samples: b.LeadTime.Samples}
+				md.LeadTime = &LeadTimeHTMLData{MedianDays: b.LeadTime.MedianDays, Samples: b.LeadTime.Samples, Branch: b.LeadTime.MainBranch, Methods: leadMethods(b.LeadTime), Normal: normalNote(b, keyLeadTime)}
 			}
+			md.Deploy = deployHTML(b)
+			md.Reverts = revertsHTML(b)
 			if b.Churn != nil && b.Churn.AddedLines > 0 {
-				md.Churn = &ChurnHTMLData{Ratio: b.Churn.Ratio * 100, WindowDays: b.Churn.WindowDays}
+				md.Churn = &ChurnHTMLData{Ratio: b.Churn.Ratio * 100, WindowDays: b.Churn.WindowDays, Normal: normalNote(b, keyChurn)}
 			}
-			md.HasMetrics = md.CommitSize != nil || md.Cadence != nil || md.LeadTime != nil || md.Churn != nil
+			md.HasMetrics = md.CommitSize != nil || md.Cadence != nil || md.LeadTime != nil || md.Deploy != nil || md.Churn != nil || md.Reverts != nil || len(md.RepoMetrics) > 0
 			if md.HasMetrics {
 				data.HasMemberMetrics = true
 			}
@@ -1118,25 +736,61 @@ func TeamHTML(stats git.TeamStats, filename string, theme string, breakdown stri
 		data.HasMonthly = len(data.Monthly) > 0
 	}
 
-	tmpl, err := template.New("team").Parse(teamHtmlTemplate)
-	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
-	}
-
 	if filename == "" {
 		filename = "gitrespect-team.html"
 	}
+	return style.write("team.html", filename, data)
+}
 
-	f, err := os.Create(filename)
+// DORAHTMLData feeds the dora.html template.
+type DORAHTMLData struct {
+	Title       string
+	Subtitle    string
+	Rows        []doraRow
+	Failures    []string
+	Repos       []DORARepoHTMLData
+	Diagnostics []DiagnosticHTMLData
+	HTMLPage
+}
+
+// DORARepoHTMLData is one repository's row: its four formatted values.
+type DORARepoHTMLData struct {
+	Name   string
+	Values []string
+}
+
+func DORAHTML(details DORADetails, filename string, style HTMLStyle) error {
+	page, err := style.page()
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return err
 	}
-	defer f.Close()
-
-	if err := tmpl.Execute(f, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	data := DORAHTMLData{
+		Title: "DORA Metrics",
+		Subtitle: fmt.Sprintf("%s · %s to %s · deploys from %s · lead time for %s", details.repoNames(),
+			details.Since.Format("Jan 2 2006"), details.Until.Format("Jan 2 2006"), details.DORA.Source, details.who()),
+		HTMLPage:    page,
+		Rows:        doraRows(details.DORA),
+		Diagnostics: diagnosticsHTML(details.Diagnostics),
+	}
+	for _, f := range details.DORA.Failures {
+		text := describeFailure(f)
+		if len(details.Repos) > 1 {
+			text = filepath.Base(f.Repo) + " " + text
+		}
+		data.Failures = append(data.Failures, text)
+	}
+	if len(details.Repo) > 1 {
+		for _, repo := range details.sortedRepos() {
+			row := DORARepoHTMLData{Name: filepath.Base(repo)}
+			for _, r := range doraRows(details.Repo[repo]) {
+				row.Values = append(row.Values, r.Value)
+			}
+			data.Repos = append(data.Repos, row)
+		}
 	}
 
-	fmt.Printf("✓ Report saved to %s\n", filename)
-	return nil
+	if filename == "" {
+		filename = "gitrespect-dora.html"
+	}
+	return style.write("dora.html", filename, data)
 }
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// InteractiveHTMLData feeds the interactive charts of the HTML reports. It
+// is embedded in the page as JSON and drawn by the page's own script, so
+// the report stays a single file that works offline.
+type InteractiveHTMLData struct {
+	Since   string              `json:"since"` // 2006-01-02
+	Until   string              `json:"until"`
+	Repos   []string            `json:"repos"`
+	Members []string            `json:"members"`
+	Commits []InteractiveCommit `json:"commits"`
+	Sizes   *InteractiveSizes   `json:"sizes,omitempty"`
+	Columns []string            `json:"columns,omitempty"` // the bars of each of Metrics
+	Metrics []InteractiveMetric `json:"metrics,omitempty"`
+}
+
+// InteractiveCommit is one counted commit. Repo and Member index the
+// Repos and Members of the payload; keys are short since there is one per
+// commit.
+type InteractiveCommit struct {
+	Repo    int    `json:"r"`
+	Member  int    `json:"m"`
+	Hash    string `json:"h"`
+	Date    string `json:"d"`
+	Subject string `json:"s"`
+	Added   int    `json:"a"`
+	Deleted int    `json:"x"`
+}
+
+// InteractiveSizes are the commit size buckets the page sorts the commits
+// into, so the histogram follows the repo and member toggles.
+type InteractiveSizes struct {
+	Edges  []int    `json:"edges"`
+	Labels []string `json:"labels"`
+}
+
+// InteractiveMetric is one opt-in metric charted across the payload's
+// Columns, with a formatted value per column.
+type InteractiveMetric struct {
+	Label  string    `json:"label"`
+	Values []float64 `json:"values"`
+	Cells  []string  `json:"cells"`
+	Has    []bool    `json:"has"`
+}
+
+// interactiveHTML builds the charts' data from the commits of each member
+// (one, named after the author, for a single report) and the metric series
+// of each column. It returns nil without commits, e.g. for a report
+// rendered from JSON, which doesn't keep them.
+func interactiveHTML(since, until time.Time, members []string, stats []git.RepoStats, sizes *metrics.CommitSizeDistribution, columns []string, series []metricSeries) *InteractiveHTMLData {
+	data := &InteractiveHTMLData{
+		Since:   since.Format("2006-01-02"),
+		Until:   until.Format("2006-01-02"),
+		Members: members,
+	}
+	repoIndex := make(map[string]int)
+	for m, s := range stats {
+		for _, c := range s.Changes {
+			r, ok := repoIndex[c.Repo]
+			if !ok {
+				r = len(data.Repos)
+				repoIndex[c.Repo] = r
+				data.Repos = append(data.Repos, filepath.Base(c.Repo))
+			}
+			data.Commits = append(data.Commits, InteractiveCommit{
+				Repo:    r,
+				Member:  m,
+				Hash:    shortHash(c.Hash),
+				Date:    c.Date.Format("2006-01-02"),
+				Subject: c.Subject,
+				Added:   c.Added,
+				Deleted: c.Deleted,
+			})
+		}
+	}
+	if len(data.Commits) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(data.Commits, func(a, b InteractiveCommit) int {
+		return cmp.Compare(a.Date, b.Date)
+	})
+
+	if sizes != nil && len(sizes.Edges) > 0 {
+		data.Sizes = &InteractiveSizes{Edges: sizes.Edges}
+		for i := range sizes.Edges {
+			data.Sizes.Labels = append(data.Sizes.Labels, sizeBucketName(sizes, i))
+		}
+		data.Sizes.Labels = append(data.Sizes.Labels, sizeBucketName(sizes, len(sizes.Edges)))
+	}
+
+	// A metric chart compares repos or members; one bar alone says no more
+	// than the Flow & Quality section.
+	if len(columns) > 1 {
+		data.Columns = columns
+		for _, s := range series {
+			m := InteractiveMetric{Label: s.Label, Values: s.Values, Has: s.Has}
+			for i, v := range s.Values {
+				m.Cells = append(m.Cells, formatMetricValue(v, s.Unit, s.Has[i]))
+			}
+			data.Metrics = append(data.Metrics, m)
+		}
+	}
+	return data
+}
+
+// reportInteractiveHTML returns the interactive charts of a single report,
+// comparing its repos' metrics.
+func reportInteractiveHTML(stats git.RepoStats, bundle metrics.Bundle) *InteractiveHTMLData {
+	columns, series := repoMetricTrend(bundle)
+	return interactiveHTML(stats.Since, stats.Until, []string{stats.Author}, []git.RepoStats{stats}, bundle.CommitSize, columns, series)
+}
+
+// teamInteractiveHTML returns the interactive charts of a team report,
+// comparing its members' metrics. Members share the size buckets, so the
+// first member's edges are the team's.
+func teamInteractiveHTML(stats git.TeamStats, bundles map[string]metrics.Bundle) *InteractiveHTMLData {
+	members := sortedMembers(stats)
+	memberStats := make([]git.RepoStats, len(members))
+	var sizes *metrics.CommitSizeDistribution
+	var columns []string
+	var withMetrics []metrics.Bundle
+	for i, email := range members {
+		memberStats[i] = stats.Members[email]
+		b, ok := bundles[email]
+		if !ok || !hasAnyMetric(b) {
+			continue
+		}
+		if sizes == nil {
+			sizes = b.CommitSize
+		}
+		columns = append(columns, email)
+		withMetrics = append(withMetrics, b)
+	}
+	return interactiveHTML(stats.Since, stats.Until, members, memberStats, sizes, columns, metricTrend(withMetrics))
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"encoding/json"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// interactiveTeam is a two-member team working across three repositories,
+// two of them named api, with commits out of date order.
+func interactiveTeam() (git.TeamStats, map[string]metrics.Bundle) {
+	day := func(n int) time.Time { return time.Date(2026, 3, 2+n, 10, 0, 0, 0, time.UTC) }
+	alice := git.RepoStats{Author: "alice@x.com", Changes: []git.CommitChange{
+		{Repo: "/src/web", Hash: strings.Repeat("a", 40), Date: day(3), Subject: "Restyle", Added: 120, Deleted: 30},
+		{Repo: "/src/api", Hash: strings.Repeat("b", 40), Date: day(1), Subject: "Add endpoint", Added: 8, Deleted: 1},
+	}}
+	bob := git.RepoStats{Author: "bob@x.com", Changes: []git.CommitChange{
+		{Repo: "/vendor/api", Hash: strings.Repeat("c", 40), Date: day(2), Subject: "Bump", Added: 600},
+		{Repo: "/src/api", Hash: strings.Repeat("d", 40), Date: day(1), Subject: "Fix", Added: 2, Deleted: 2},
+	}}
+	stats := git.TeamStats{
+		Since: day(0), Until: day(13),
+		Members: map[string]git.RepoStats{"alice@x.com": alice, "bob@x.com": bob},
+	}
+	sizes := &metrics.CommitSizeDistribution{
+		Edges: metrics.DefaultSizeEdges, Counts: []int{1, 0, 1, 0}, Total: 2, P50: 9, P90: 150, P99: 150,
+		Added:   metrics.SizeHistogram{Counts: []int{1, 0, 1, 0}},
+		Deleted: metrics.SizeHistogram{Counts: []int{1, 1, 0, 0}},
+		Largest: []metrics.SizedCommit{
+			{Hash: alice.Changes[0].Hash, Subject: "Restyle", Added: 120, Deleted: 30},
+			{Hash: alice.Changes[1].Hash, Subject: "Add endpoint", Added: 8, Deleted: 1},
+		},
+	}
+	bundles := map[string]metrics.Bundle{
+		"alice@x.com": {CommitSize: sizes, Cadence: &metrics.Cadence{MedianDaysBetween: 2, Samples: 1}},
+		"bob@x.com":   {Cadence: &metrics.Cadence{MedianDaysBetween: 1, Samples: 1}},
+	}
+	return stats, bundles
+}
+
+func TestInteractiveHTMLGolden(t *testing.T) {
+	stats, bundles := interactiveTeam()
+	data := teamInteractiveHTML(stats, bundles)
+	if data == nil {
+		t.Fatal("no interactive data for a team with commits")
+	}
+	got, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "interactive.json", append(got, '\n'))
+
+	// Repos and members are indexed in the order they are first seen, and
+	// the two api repositories stay apart.
+	if len(data.Repos) != 3 || len(data.Members) != 2 || data.Commits[0].Date > data.Commits[len(data.Commits)-1].Date {
+		t.Errorf("repos %v, members %v, commits %+v", data.Repos, data.Members, data.Commits)
+	}
+	for _, c := range data.Commits {
+		if c.Subject == "Bump" && (data.Repos[c.Repo] != "api" || data.Members[c.Member] != "bob@x.com" || c.Repo == data.Commits[0].Repo) {
+			t.Errorf("Bump indexed as repo %d member %d", c.Repo, c.Member)
+		}
+	}
+
+	// One member with metrics is one bar: no metric chart.
+	delete(bundles, "bob@x.com")
+	if data := teamInteractiveHTML(stats, bundles); len(data.Columns) != 0 || len(data.Metrics) != 0 {
+		t.Errorf("one column charted: %v", data.Columns)
+	}
+	// A single report charts its repos only when it has several.
+	single := reportInteractiveHTML(stats.Members["alice@x.com"], bundles["alice@x.com"])
+	if single == nil || len(single.Members) != 1 || single.Metrics != nil || single.Sizes == nil {
+		t.Errorf("single report = %+v", single)
+	}
+	// Without commits, e.g. rendered from JSON, there is nothing to chart.
+	if data := interactiveHTML(stats.Since, stats.Until, []string{"x"}, []git.RepoStats{{}}, nil, nil, nil); data != nil {
+		t.Errorf("charted no commits: %+v", data)
+	}
+}
+
+var ixDataRe = regexp.MustCompile(`(?s)<script type="application/json" id="ix-data">(.*?)</script>`)
+
+func TestInteractiveHTMLEscaping(t *testing.T) {
+	stats, bundles := interactiveTeam()
+	evil := `</script><script>alert("x")</script>&<!--@x.com`
+	m := stats.Members["bob@x.com"]
+	m.Author = evil
+	m.Changes[0].Subject = `</SCRIPT><img src=x onerror=alert(1)> 'quoted' "double" \ ` + "\u2028"
+	delete(stats.Members, "bob@x.com")
+	stats.Members[evil] = m
+	bundles[evil] = bundles["bob@x.com"]
+
+	out := filepath.Join(t.TempDir(), "team.html")
+	if err := TeamHTML(stats, out, HTMLStyle{}, "", metrics.Bundle{}, bundles); err != nil {
+		t.Fatal(err)
+	}
+	page, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, raw := range []string{`<script>alert("x")`, `<img src=x onerror`, "<!--@x.com"} {
+		if strings.Contains(string(page), raw) {
+			t.Errorf("page contains %q unescaped", raw)
+		}
+	}
+
+	// The embedded JSON still decodes to the exact names.
+	match := ixDataRe.FindSubmatch(page)
+	if match == nil {
+		t.Fatal("no interactive data in the page")
+	}
+	var data InteractiveHTMLData
+	if err := json.Unmarshal(match[1], &data); err != nil {
+		t.Fatalf("embedded JSON: %v\n%s", err, html.EscapeString(string(match[1])))
+	}
+	found := false
+	for _, c := range data.Commits {
+		if data.Members[c.Member] == evil && c.Subject == m.Changes[0].Subject {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("member or subject changed on the way through the page: %q", data.Members)
+	}
+}
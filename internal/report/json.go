@@ -12,21 +12,65 @@ import (
 )
 
 type JSONReport struct {
-	Author     string             `json:"author"`
-	Period     PeriodInfo         `json:"period"`
-	Summary    SummaryStats       `json:"summary"`
-	Daily      DailyStats         `json:"daily"`
-	Benchmarks []BenchmarkResult  `json:"benchmarks,omitempty"`
-	Metrics    *MetricsPayload    `json:"metrics,omitempty"`
-	Monthly    []MonthlyJSONStats `json:"monthly,omitempty"`
+	SchemaVersion string               `json:"schema_version"`
+	Author        string               `json:"author"`
+	Repos         []string             `json:"repos,omitempty"`
+	Period        PeriodInfo           `json:"period"`
+	Summary       SummaryStats         `json:"summary"`
+	Daily         DailyStats           `json:"daily"`
+	Benchmarks    []BenchmarkResult    `json:"benchmarks,omitempty"`
+	Metrics       *MetricsPayload      `json:"metrics,omitempty"`
+	Monthly       []MonthlyJSONStats   `json:"monthly,omitempty"`
+	Excluded      []string             `json:"excluded_commits,omitempty"`
+	Outliers      []CommitJSON         `json:"outliers,omitempty"`
+	Explain       *ExplainJSON         `json:"explain,omitempty"`
+	Diagnostics   []metrics.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// CommitJSON is one commit's contribution; Kinds and Reasons are set on
+// outliers only.
+type CommitJSON struct {
+	Repo    string   `json:"repo"`
+	SHA     string   `json:"sha"`
+	Date    string   `json:"date"`
+	Subject string   `json:"subject"`
+	Added   int      `json:"added"`
+	Deleted int      `json:"deleted"`
+	Kinds   []string `json:"kinds,omitempty"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// ExplainJSON breaks an --explain bucket down into its top commits and files.
+type ExplainJSON struct {
+	Bucket  string       `json:"bucket"`
+	Added   int          `json:"added"`
+	Deleted int          `json:"deleted"`
+	Net     int          `json:"net"`
+	Commits int          `json:"commits"`
+	Top     []CommitJSON `json:"top_commits"`
+	Files   []FileJSON   `json:"top_files"`
+}
+
+type FileJSON struct {
+	Repo    string `json:"repo"`
+	Path    string `json:"path"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Commits int    `json:"commits"`
 }
 
 type MetricsPayload struct {
+	Repo       string                          `json:"repo,omitempty"` // set on Repos entries only
 	Baseline   *metrics.Baseline               `json:"baseline,omitempty"`
 	CommitSize *metrics.CommitSizeDistribution `json:"commit_size,omitempty"`
 	Cadence    *metrics.Cadence                `json:"cadence,omitempty"`
 	LeadTime   *metrics.LeadTime               `json:"lead_time,omitempty"`
+	Deploy     *metrics.DeployLeadTime         `json:"deploy,omitempty"`
 	Churn      *metrics.Churn                  `json:"churn,omitempty"`
+	Reverts    *metrics.Reverts                `json:"reverts,omitempty"`
+	Window     *PeriodInfo                     `json:"window,omitempty"` // set on Normal only
+	Normal     *MetricsPayload                 `json:"normal,omitempty"`
+	Repos      []MetricsPayload                `json:"repos,omitempty"`
 }
 
 type PeriodInfo struct {
@@ -64,26 +108,75 @@ type MonthlyJSONStats struct {
 	Commits int    `json:"commits"`
 }
 
+// CompareJSONReport lists every compared period in the order requested. The
+// first period is the reference for multipliers and deltas.
 type CompareJSONReport struct {
-	Before     PeriodStats `json:"before"`
-	After      PeriodStats `json:"after"`
-	Multiplier float64     `json:"productivity_multiplier"`
-	Change     string      `json:"change_description"`
+	SchemaVersion string               `json:"schema_version"`
+	Periods       []ComparePeriodJSON  `json:"periods"`
+	Repos         []CompareRowJSON     `json:"repos,omitempty"`
+	Members       []CompareRowJSON     `json:"members,omitempty"`
+	Diagnostics   []metrics.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// ComparePeriodJSON is one period of a comparison. Fields comparing against
+// the first period are omitted on the first period itself.
+type ComparePeriodJSON struct {
+	Label        string                `json:"label"`
+	Since        string                `json:"since"`
+	Until        string                `json:"until"`
+	Net          int                   `json:"net"`
+	WorkingDays  int                   `json:"working_days"`
+	PerDay       float64               `json:"per_day"`
+	Multiplier   *float64              `json:"multiplier_vs_first,omitempty"`
+	Change       string                `json:"change_description,omitempty"`
+	Significance *metrics.Significance `json:"significance_vs_first,omitempty"`
+	Metrics      []MetricValueJSON     `json:"metrics,omitempty"`
 }
 
-type PeriodStats struct {
-	Label       string  `json:"label"`
-	Net         int     `json:"net"`
-	WorkingDays int     `json:"working_days"`
-	PerDay      float64 `json:"per_day"`
+// MetricValueJSON is one opt-in metric in one period. Value is null when the
+// period had too little data; Delta is null on the first period or when
+// either side is missing.
+type MetricValueJSON struct {
+	Metric string   `json:"metric"`
+	Unit   string   `json:"unit"`
+	Value  *float64 `json:"value"`
+	Delta  *float64 `json:"delta_vs_first"`
+}
+
+// CompareRowJSON is a per-repo or per-member breakdown of a comparison.
+type CompareRowJSON struct {
+	Name    string              `json:"name"`
+	Periods []ComparePeriodJSON `json:"periods"`
 }
 
 func JSON(stats git.RepoStats, filename string, breakdown string, bundle metrics.Bundle) error {
+	data, err := json.MarshalIndent(NewJSONReport(stats, breakdown, bundle), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if filename != "" {
+		err = os.WriteFile(filename, data, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		fmt.Printf("✓ Report saved to %s\n", filename)
+	} else {
+		fmt.Println(string(data))
+	}
+
+	return nil
+}
+
+// NewJSONReport builds the report --output json writes.
+func NewJSONReport(stats git.RepoStats, breakdown string, bundle metrics.Bundle) JSONReport {
 	workingDays := git.WorkingDays(stats.Since, stats.Until)
 	locPerDay := float64(stats.Net) / float64(workingDays)
 
 	report := JSONReport{
-		Author: stats.Author,
+		SchemaVersion: SchemaVersion,
+		Author:        stats.Author,
+		Repos:         stats.Repos,
 		Period: PeriodInfo{
 			Since: stats.Since.Format("2006-01-02"),
 			Until: stats.Until.Format("2006-01-02"),
@@ -101,6 +194,14 @@ func JSON(stats git.RepoStats, filename string, breakdown string, bundle metrics
 			Deleted: float64(stats.Deleted) / float64(workingDays),
 			Net:     locPerDay,
 		},
+		Excluded:    stats.Excluded,
+		Explain:     explainJSON(bundle.Explain),
+		Diagnostics: bundle.Diagnostics,
+	}
+	for _, o := range bundle.Outliers {
+		c := commitJSON(o.Commit)
+		c.Kinds, c.Reasons = o.Kinds, o.Reasons
+		report.Outliers = append(report.Outliers, c)
 	}
 
 	// Legacy benchmarks only when explicitly requested
@@ -116,13 +217,17 @@ func JSON(stats git.RepoStats, filename string, breakdown string, bundle metrics
 	}
 
 	// New metrics payload
-	if bundle.Baseline != nil || bundle.CommitSize != nil || bundle.Cadence != nil || bundle.LeadTime != nil || bundle.Churn != nil {
+	if bundle.Baseline != nil || hasAnyMetric(bundle) {
 		report.Metrics = &MetricsPayload{
 			Baseline:   bundle.Baseline,
 			CommitSize: bundle.CommitSize,
 			Cadence:    bundle.Cadence,
 			LeadTime:   bundle.LeadTime,
+			Deploy:     bundle.Deploy,
 			Churn:      bundle.Churn,
+			Reverts:    bundle.Reverts,
+			Normal:     normalPayload(bundle),
+			Repos:      repoPayloads(bundle),
 		}
 	}
 
@@ -147,29 +252,18 @@ func JSON(stats git.RepoStats, filename string, breakdown string, bundle metrics
 		}
 	}
 
-	data, err := json.MarshalIndent(report, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-
-	if filename != "" {
-		err = os.WriteFile(filename, data, 0644)
-		if err != nil {
-			return fmt.Errorf("failed to write file: %w", err)
-		}
-		fmt.Printf("✓ Report saved to %s\n", filename)
-	} else {
-		fmt.Println(string(data))
-	}
-
-	return nil
+	return report
 }
 
 type TeamJSONReport struct {
-	Period  PeriodInfo         `json:"period"`
-	Totals  TeamTotals         `json:"totals"`
-	Members []MemberStats      `json:"members"`
-	Monthly []MonthlyJSONStats `json:"monthly,omitempty"`
+	SchemaVersion string               `json:"schema_version"`
+	Period        PeriodInfo           `json:"period"`
+	Totals        TeamTotals           `json:"totals"`
+	Benchmarks    []BenchmarkResult    `json:"benchmarks,omitempty"` // average member, with --legacy-benchmark
+	Metrics       *MetricsPayload      `json:"metrics,omitempty"`
+	Members       []MemberStats        `json:"members"`
+	Monthly       []MonthlyJSONStats   `json:"monthly,omitempty"`
+	Diagnostics   []metrics.Diagnostic `json:"diagnostics,omitempty"` // members' too, by author
 }
 
 type TeamTotals struct {
@@ -190,10 +284,31 @@ type MemberStats struct {
 	Metrics *MetricsPayload `json:"metrics,omitempty"`
 }
 
-func TeamJSON(stats git.TeamStats, filename string, breakdown string, bundles map[string]metrics.Bundle) error {
+func TeamJSON(stats git.TeamStats, filename string, breakdown string, team metrics.Bundle, bundles map[string]metrics.Bundle) error {
+	data, err := json.MarshalIndent(NewTeamJSONReport(stats, breakdown, team, bundles), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if filename != "" {
+		err = os.WriteFile(filename, data, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		fmt.Printf("✓ Report saved to %s\n", filename)
+	} else {
+		fmt.Println(string(data))
+	}
+
+	return nil
+}
+
+// NewTeamJSONReport builds the report --team --output json writes.
+func NewTeamJSONReport(stats git.TeamStats, breakdown string, team metrics.Bundle, bundles map[string]metrics.Bundle) TeamJSONReport {
 	workingDays := git.WorkingDays(stats.Since, stats.Until)
 
 	report := TeamJSONReport{
+		SchemaVersion: SchemaVersion,
 		Period: PeriodInfo{
 			Since: stats.Since.Format("2006-01-02"),
 			Until: stats.Until.Format("2006-01-02"),
@@ -207,6 +322,19 @@ func TeamJSON(stats git.TeamStats, filename string, breakdown string, bundles ma
 			PerDay:  float64(stats.TotalNet) / float64(workingDays),
 		},
 	}
+	if team.LegacyBenchmark && len(stats.Members) > 0 {
+		for _, c := range benchmark.Compare(report.Totals.PerDay / float64(len(stats.Members))) {
+			report.Benchmarks = append(report.Benchmarks, BenchmarkResult{
+				Label:      c.Label,
+				Benchmark:  c.Benchmark,
+				Multiplier: c.Multiplier,
+			})
+		}
+	}
+	if team.Baseline != nil || team.Deploy != nil {
+		report.Metrics = &MetricsPayload{Baseline: team.Baseline, Deploy: team.Deploy}
+	}
+	report.Diagnostics = team.Diagnostics
 
 	// Add member stats sorted by net lines
 	type memberEntry struct {
@@ -231,12 +359,17 @@ func TeamJSON(stats git.TeamStats, filename string, breakdown string, bundles ma
 			PerDay:  float64(m.stats.Net) / float64(workingDays),
 		}
 		if b, ok := bundles[m.email]; ok {
-			if b.CommitSize != nil || b.Cadence != nil || b.LeadTime != nil || b.Churn != nil {
+			if b.Baseline != nil || hasAnyMetric(b) {
 				ms.Metrics = &MetricsPayload{
+					Baseline:   b.Baseline,
 					CommitSize: b.CommitSize,
 					Cadence:    b.Cadence,
 					LeadTime:   b.LeadTime,
+					Deploy:     b.Deploy,
 					Churn:      b.Churn,
+					Reverts:    b.Reverts,
+					Normal:     normalPayload(b),
+					Repos:      repoPayloads(b),
 				}
 			}
 		}
@@ -263,6 +396,68 @@ func TeamJSON(stats git.TeamStats, filename string, breakdown string, bundles ma
 		}
 	}
 
+	return report
+}
+
+// normalPayload returns the bundle's baseline-window metrics, or nil when
+// none were computed.
+func normalPayload(b metrics.Bundle) *MetricsPayload {
+	n := b.Normal
+	if n == nil || !hasAnyMetric(*n) {
+		return nil
+	}
+	return &MetricsPayload{
+		CommitSize: n.CommitSize,
+		Cadence:    n.Cadence,
+		LeadTime:   n.LeadTime,
+		Deploy:     n.Deploy,
+		Churn:      n.Churn,
+		Reverts:    n.Reverts,
+		Window: &PeriodInfo{
+			Since: n.Since.Format("2006-01-02"),
+			Until: n.Until.Format("2006-01-02"),
+			Days:  git.WorkingDays(n.Since, n.Until),
+		},
+	}
+}
+
+// repoPayloads returns the bundle's per-repo metrics (--per-repo).
+func repoPayloads(b metrics.Bundle) []MetricsPayload {
+	var out []MetricsPayload
+	for _, r := range b.Repos {
+		out = append(out, MetricsPayload{
+			Repo:       r.Path,
+			CommitSize: r.Bundle.CommitSize,
+			Cadence:    r.Bundle.Cadence,
+			LeadTime:   r.Bundle.LeadTime,
+			Deploy:     r.Bundle.Deploy,
+			Churn:      r.Bundle.Churn,
+			Reverts:    r.Bundle.Reverts,
+		})
+	}
+	return out
+}
+
+func CompareJSON(comparison git.CompareStats, filename string, details CompareDetails) error {
+	report := CompareJSONReport{
+		SchemaVersion: SchemaVersion,
+		Periods:       comparePeriodsJSON(comparison.Periods, details.Metrics, &details),
+		Diagnostics:   details.Diagnostics,
+	}
+	labels := periodLabels(comparison.Periods)
+	for _, r := range details.Repos {
+		report.Repos = append(report.Repos, CompareRowJSON{
+			Name:    r.Path,
+			Periods: comparePeriodsJSON(relabel(labels, r.Periods), nil, nil),
+		})
+	}
+	for _, m := range details.Members {
+		report.Members = append(report.Members, CompareRowJSON{
+			Name:    m.Email,
+			Periods: comparePeriodsJSON(relabel(labels, m.Periods), m.Metrics, nil),
+		})
+	}
+
 	data, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
@@ -281,30 +476,107 @@ func TeamJSON(stats git.TeamStats, filename string, breakdown string, bundles ma
 	return nil
 }
 
-func CompareJSON(comparison git.CompareStats, filename string) error {
-	beforeDays := git.WorkingDays(comparison.Before.Since, comparison.Before.Until)
-	afterDays := git.WorkingDays(comparison.After.Since, comparison.After.Until)
+// comparePeriodsJSON converts labeled periods with optional per-period metric
+// bundles. Significance is only attached when details is non-nil, since
+// per-repo and per-member rows are not tested separately.
+func comparePeriodsJSON(periods []git.LabeledStats, bundles []metrics.Bundle, details *CompareDetails) []ComparePeriodJSON {
+	stats := periodStats(periods)
+	series := metricTrend(bundles)
+	out := make([]ComparePeriodJSON, len(periods))
+	for i, p := range periods {
+		pj := ComparePeriodJSON{
+			Label:       p.Label,
+			Since:       p.Stats.Since.Format("2006-01-02"),
+			Until:       p.Stats.Until.Format("2006-01-02"),
+			Net:         p.Stats.Net,
+			WorkingDays: git.WorkingDays(p.Stats.Since, p.Stats.Until),
+			PerDay:      perDay(p.Stats),
+		}
+		if i > 0 {
+			m := multiplierVsFirst(stats, i)
+			pj.Multiplier = &m
+			if details != nil {
+				sig := details.SignificanceAt(i)
+				pj.Significance = &sig
+				pj.Change = describeChange(changeVsFirst(stats, sig, i), sig)
+			}
+		}
+		for _, ms := range series {
+			v := MetricValueJSON{Metric: ms.Key, Unit: ms.Unit}
+			if ms.Has[i] {
+				val := ms.Values[i]
+				v.Value = &val
+			}
+			if d, ok := ms.Delta(i); ok && i > 0 {
+				v.Delta = &d
+			}
+			pj.Metrics = append(pj.Metrics, v)
+		}
+		out[i] = pj
+	}
+	return out
+}
 
-	beforePerDay := float64(comparison.Before.Net) / float64(beforeDays)
-	afterPerDay := float64(comparison.After.Net) / float64(afterDays)
+// relabel pairs per-period stats with the comparison's period labels.
+func relabel(labels []string, stats []git.RepoStats) []git.LabeledStats {
+	out := make([]git.LabeledStats, len(stats))
+	for i, s := range stats {
+		out[i] = git.LabeledStats{Label: labels[i], Stats: s}
+	}
+	return out
+}
 
-	multiplier := benchmark.CalculateMultiplier(beforePerDay, afterPerDay)
+// describeChange summarises the multiplier together with its uncertainty so
+// the headline number is never quoted without context.
+func describeChange(multiplier float64, sig metrics.Significance) string {
+	desc := fmt.Sprintf("%.1fx lines/day", multiplier)
+	if sig.RatioLow != 0 || sig.RatioHigh != 0 {
+		desc += fmt.Sprintf(" (%.0f%% CI %.1fx-%.1fx)", sig.Confidence*100, sig.RatioLow, sig.RatioHigh)
+	}
+	switch {
+	case sig.Insufficient:
+		desc += "; not enough weekly samples for a significance test"
+	case sig.Significant():
+		desc += fmt.Sprintf("; significant (p=%.3f, %s effect)", sig.PValue, sig.Effect)
+	default:
+		desc += fmt.Sprintf("; not significant (p=%.3f)", sig.PValue)
+	}
+	return desc
+}
 
-	report := CompareJSONReport{
-		Before: PeriodStats{
-			Label:       comparison.BeforeLabel,
-			Net:         comparison.Before.Net,
-			WorkingDays: beforeDays,
-			PerDay:      beforePerDay,
-		},
-		After: PeriodStats{
-			Label:       comparison.AfterLabel,
-			Net:         comparison.After.Net,
-			WorkingDays: afterDays,
-			PerDay:      afterPerDay,
+// DORAJSONReport is the dora command's JSON output. Repos is set when more
+// than one repository was analyzed.
+type DORAJSONReport struct {
+	SchemaVersion string               `json:"schema_version"`
+	Period        PeriodInfo           `json:"period"`
+	Authors       []string             `json:"authors,omitempty"`
+	DORA          metrics.DORA         `json:"dora"`
+	Repos         []DORARepoJSON       `json:"repos,omitempty"`
+	Diagnostics   []metrics.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// DORARepoJSON is one repository's DORA metrics.
+type DORARepoJSON struct {
+	Repo string       `json:"repo"`
+	DORA metrics.DORA `json:"dora"`
+}
+
+func DORAJSON(details DORADetails, filename string) error {
+	report := DORAJSONReport{
+		SchemaVersion: SchemaVersion,
+		Period: PeriodInfo{
+			Since: details.Since.Format("2006-01-02"),
+			Until: details.Until.Format("2006-01-02"),
+			Days:  git.WorkingDays(details.Since, details.Until),
 		},
-		Multiplier: multiplier,
-		Change:     fmt.Sprintf("%.1fx productivity change", multiplier),
+		Authors:     details.Authors,
+		DORA:        details.DORA,
+		Diagnostics: details.Diagnostics,
+	}
+	if len(details.Repo) > 1 {
+		for _, repo := range details.sortedRepos() {
+			report.Repos = append(report.Repos, DORARepoJSON{Repo: repo, DORA: details.Repo[repo]})
+		}
 	}
 
 	data, err := json.MarshalIndent(report, "", "  ")
@@ -324,3 +596,37 @@ func CompareJSON(comparison git.CompareStats, filename string) error {
 
 	return nil
 }
+
+func commitJSON(c git.CommitChange) CommitJSON {
+	return CommitJSON{
+		Repo:    c.Repo,
+		SHA:     c.Hash,
+		Date:    c.Date.Format("2006-01-02"),
+		Subject: c.Subject,
+		Added:   c.Added,
+		Deleted: c.Deleted,
+	}
+}
+
+// explainJSON converts an --explain breakdown; nil when there is none.
+func explainJSON(ex *git.Explanation) *ExplainJSON {
+	if ex == nil {
+		return nil
+	}
+	out := &ExplainJSON{
+		Bucket:  ex.Bucket,
+		Added:   ex.Added,
+		Deleted: ex.Deleted,
+		Net:     ex.Net,
+		Commits: ex.Commits,
+		Top:     []CommitJSON{},
+		Files:   []FileJSON{},
+	}
+	for _, c := range ex.Top {
+		out.Top = append(out.Top, commitJSON(c))
+	}
+	for _, f := range ex.Files {
+		out.Files = append(out.Files, FileJSON{Repo: f.Repo, Path: f.Path, Added: f.Added, Deleted: f.Deleted, Commits: f.Commits})
+	}
+	return out
+}
@@ -0,0 +1,628 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// Saved is a JSON report read back by Load into the structures the
+// renderers take. Kind, one of the names Schema takes, says which are set.
+type Saved struct {
+	Kind      string
+	Breakdown string // "monthly" when the report had a monthly breakdown
+
+	Stats   git.RepoStats             // report
+	Bundle  metrics.Bundle            // report; the team-wide bundle for team
+	Team    git.TeamStats             // team
+	Members map[string]metrics.Bundle // team
+	Compare git.CompareStats          // compare
+	Details CompareDetails            // compare
+	DORA    DORADetails               // dora
+}
+
+// Load reads a JSON report written by any version with the same major
+// schema_version. name, usually the file it came from, stands in for the
+// repository path in reports older than 1.1, which do not record their
+// repositories. What JSON leaves out
+// stays empty: weekly breakdowns, per-repo totals, files changed per member
+// and, in comparisons, lines added and deleted.
+func Load(data []byte, name string) (Saved, error) {
+	var head map[string]json.RawMessage
+	if err := json.Unmarshal(data, &head); err != nil {
+		return Saved{}, fmt.Errorf("%s: not a JSON report: %w", name, err)
+	}
+	if v, ok := head["schema_version"]; ok {
+		var version string
+		if err := json.Unmarshal(v, &version); err != nil {
+			return Saved{}, fmt.Errorf("%s: invalid schema_version: %w", name, err)
+		}
+		if major(version) != major(SchemaVersion) {
+			return Saved{}, fmt.Errorf("%s: schema_version %s is not supported (this gitrespect reads %s.x)", name, version, major(SchemaVersion))
+		}
+	}
+
+	var s Saved
+	var err error
+	switch {
+	case head["periods"] != nil:
+		s, err = loadCompare(data)
+	case head["dora"] != nil:
+		s, err = loadDORA(data, name)
+	case head["members"] != nil:
+		s, err = loadTeam(data)
+	case head["author"] != nil:
+		s, err = loadReport(data, name)
+	default:
+		return Saved{}, fmt.Errorf("%s: not a gitrespect JSON report", name)
+	}
+	if err != nil {
+		return Saved{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return s, nil
+}
+
+// LoadFile reads a JSON report from a file; see Load.
+func LoadFile(filename string) (Saved, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Saved{}, err
+	}
+	return Load(data, filepath.Base(filename))
+}
+
+func major(version string) string {
+	m, _, _ := strings.Cut(version, ".")
+	return m
+}
+
+func loadReport(data []byte, name string) (Saved, error) {
+	var r JSONReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Saved{}, err
+	}
+	since, until, err := loadPeriod(r.Period)
+	if err != nil {
+		return Saved{}, err
+	}
+	s := Saved{Kind: "report"}
+	path := name
+	if len(r.Repos) > 0 {
+		path = reposLabel(r.Repos)
+	}
+	s.Stats = git.RepoStats{
+		Path:         path,
+		Repos:        r.Repos,
+		Author:       r.Author,
+		Since:        since,
+		Until:        until,
+		Added:        r.Summary.Added,
+		Deleted:      r.Summary.Deleted,
+		Net:          r.Summary.Net,
+		Commits:      r.Summary.Commits,
+		FilesChanged: r.Summary.FilesChanged,
+		Weekly:       make(map[string]git.WeekStats),
+		Excluded:     r.Excluded,
+	}
+	if s.Stats.Monthly, err = loadMonthly(r.Monthly); err != nil {
+		return Saved{}, err
+	}
+	if len(r.Monthly) > 0 {
+		s.Breakdown = "monthly"
+	}
+
+	s.Bundle = payloadBundle(r.Metrics, since, until)
+	s.Bundle.LegacyBenchmark = len(r.Benchmarks) > 0
+	s.Bundle.Diagnostics = r.Diagnostics
+	for _, o := range r.Outliers {
+		c, err := loadCommit(o)
+		if err != nil {
+			return Saved{}, err
+		}
+		s.Bundle.Outliers = append(s.Bundle.Outliers, git.Outlier{Commit: c, Kinds: o.Kinds, Reasons: o.Reasons})
+	}
+	if ex := r.Explain; ex != nil {
+		s.Bundle.Explain = &git.Explanation{Bucket: ex.Bucket, Added: ex.Added, Deleted: ex.Deleted, Net: ex.Net, Commits: ex.Commits}
+		for _, cj := range ex.Top {
+			c, err := loadCommit(cj)
+			if err != nil {
+				return Saved{}, err
+			}
+			s.Bundle.Explain.Top = append(s.Bundle.Explain.Top, c)
+		}
+		for _, f := range ex.Files {
+			s.Bundle.Explain.Files = append(s.Bundle.Explain.Files, git.FileContribution{Repo: f.Repo, Path: f.Path, Added: f.Added, Deleted: f.Deleted, Commits: f.Commits})
+		}
+	}
+	return s, nil
+}
+
+func loadTeam(data []byte) (Saved, error) {
+	var r TeamJSONReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Saved{}, err
+	}
+	since, until, err := loadPeriod(r.Period)
+	if err != nil {
+		return Saved{}, err
+	}
+	s := Saved{Kind: "team", Members: make(map[string]metrics.Bundle)}
+	s.Team = git.TeamStats{
+		Since:        since,
+		Until:        until,
+		Members:      make(map[string]git.RepoStats),
+		TotalAdded:   r.Totals.Added,
+		TotalDeleted: r.Totals.Deleted,
+		TotalNet:     r.Totals.Net,
+		TotalCommits: r.Totals.Commits,
+	}
+	if s.Team.Monthly, err = loadMonthly(r.Monthly); err != nil {
+		return Saved{}, err
+	}
+	if len(r.Monthly) > 0 {
+		s.Breakdown = "monthly"
+	}
+	for _, m := range r.Members {
+		s.Team.Members[m.Email] = git.RepoStats{
+			Author:  m.Email,
+			Since:   since,
+			Until:   until,
+			Added:   m.Added,
+			Deleted: m.Deleted,
+			Net:     m.Net,
+			Commits: m.Commits,
+			Monthly: make(map[string]git.MonthStats),
+			Weekly:  make(map[string]git.WeekStats),
+		}
+		s.Members[m.Email] = payloadBundle(m.Metrics, since, until)
+	}
+	s.Bundle = payloadBundle(r.Metrics, since, until)
+	s.Bundle.LegacyBenchmark = len(r.Benchmarks) > 0
+	s.Bundle.Diagnostics = r.Diagnostics
+	return s, nil
+}
+
+func loadCompare(data []byte) (Saved, error) {
+	var r CompareJSONReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Saved{}, err
+	}
+	if len(r.Periods) == 0 {
+		return Saved{}, fmt.Errorf("no periods to compare")
+	}
+	s := Saved{Kind: "compare"}
+	stats, err := loadComparePeriods(r.Periods)
+	if err != nil {
+		return Saved{}, err
+	}
+	for i, p := range r.Periods {
+		s.Compare.Periods = append(s.Compare.Periods, git.LabeledStats{Label: p.Label, Stats: stats[i]})
+		var sig metrics.Significance
+		if p.Significance != nil {
+			sig = *p.Significance
+		}
+		s.Details.Significance = append(s.Details.Significance, sig)
+	}
+	s.Details.Metrics = trendBundles(r.Periods)
+	s.Details.Diagnostics = r.Diagnostics
+	for _, row := range r.Repos {
+		stats, err := loadComparePeriods(row.Periods)
+		if err != nil {
+			return Saved{}, err
+		}
+		s.Details.Repos = append(s.Details.Repos, RepoComparison{Path: row.Name, Periods: stats})
+	}
+	for _, row := range r.Members {
+		stats, err := loadComparePeriods(row.Periods)
+		if err != nil {
+			return Saved{}, err
+		}
+		s.Details.Members = append(s.Details.Members, MemberComparison{Email: row.Name, Periods: stats, Metrics: trendBundles(row.Periods)})
+	}
+	return s, nil
+}
+
+func loadComparePeriods(periods []ComparePeriodJSON) ([]git.RepoStats, error) {
+	out := make([]git.RepoStats, len(periods))
+	for i, p := range periods {
+		since, until, err := loadPeriod(PeriodInfo{Since: p.Since, Until: p.Until})
+		if err != nil {
+			return nil, err
+		}
+		out[i] = git.RepoStats{
+			Since:   since,
+			Until:   until,
+			Net:     p.Net,
+			Monthly: make(map[string]git.MonthStats),
+			Weekly:  make(map[string]git.WeekStats),
+		}
+	}
+	return out, nil
+}
+
+func loadDORA(data []byte, name string) (Saved, error) {
+	var r DORAJSONReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Saved{}, err
+	}
+	since, until, err := loadPeriod(r.Period)
+	if err != nil {
+		return Saved{}, err
+	}
+	s := Saved{Kind: "dora"}
+	s.DORA = DORADetails{
+		Authors:     r.Authors,
+		Since:       since,
+		Until:       until,
+		DORA:        r.DORA,
+		Repo:        make(map[string]metrics.DORA),
+		Diagnostics: r.Diagnostics,
+	}
+	for _, repo := range r.Repos {
+		s.DORA.Repos = append(s.DORA.Repos, repo.Repo)
+		s.DORA.Repo[repo.Repo] = repo.DORA
+	}
+	if len(s.DORA.Repos) == 0 {
+		s.DORA.Repos = []string{name}
+		s.DORA.Repo[name] = r.DORA
+	}
+	return s, nil
+}
+
+// loadPeriod parses a report's dates. Until is read as midday so that
+// git.WorkingDays counts the same whole days the report did, whatever the
+// time of day it ran and across daylight saving changes.
+func loadPeriod(p PeriodInfo) (since, until time.Time, err error) {
+	if since, err = time.ParseInLocation("2006-01-02", p.Since, time.Local); err != nil {
+		return since, until, fmt.Errorf("invalid period: %w", err)
+	}
+	if until, err = time.ParseInLocation("2006-01-02", p.Until, time.Local); err != nil {
+		return since, until, fmt.Errorf("invalid period: %w", err)
+	}
+	return since, until.Add(12 * time.Hour), nil
+}
+
+// loadMonthly rebuilds the monthly breakdown, keyed by YYYY-MM as
+// git.Analyze keys it.
+func loadMonthly(months []MonthlyJSONStats) (map[string]git.MonthStats, error) {
+	out := make(map[string]git.MonthStats)
+	for _, m := range months {
+		t, err := time.Parse("Jan", m.Month)
+		if err != nil {
+			return nil, fmt.Errorf("invalid month %q", m.Month)
+		}
+		out[fmt.Sprintf("%04d-%02d", m.Year, t.Month())] = git.MonthStats{
+			Year:    m.Year,
+			Month:   int(t.Month()),
+			Added:   m.Added,
+			Deleted: m.Deleted,
+			Net:     m.Net,
+			Commits: m.Commits,
+		}
+	}
+	return out, nil
+}
+
+func loadCommit(c CommitJSON) (git.CommitChange, error) {
+	date, err := time.ParseInLocation("2006-01-02", c.Date, time.Local)
+	if err != nil {
+		return git.CommitChange{}, fmt.Errorf("invalid date of commit %s: %w", c.SHA, err)
+	}
+	return git.CommitChange{Repo: c.Repo, Hash: c.SHA, Date: date, Subject: c.Subject, Added: c.Added, Deleted: c.Deleted}, nil
+}
+
+// payloadBundle turns a metrics payload back into a bundle covering
+// [since, until]; an empty one when p is nil.
+func payloadBundle(p *MetricsPayload, since, until time.Time) metrics.Bundle {
+	b := metrics.Bundle{Since: since, Until: until}
+	if p == nil {
+		return b
+	}
+	b.Baseline = p.Baseline
+	b.CommitSize = p.CommitSize
+	b.Cadence = p.Cadence
+	b.LeadTime = p.LeadTime
+	b.Deploy = p.Deploy
+	b.Churn = p.Churn
+	b.Reverts = p.Reverts
+	if n := p.Normal; n != nil && n.Window != nil {
+		nSince, nUntil, err := loadPeriod(*n.Window)
+		if err == nil {
+			normal := payloadBundle(n, nSince, nUntil)
+			b.Normal = &normal
+		}
+	}
+	for i := range p.Repos {
+		b.Repos = append(b.Repos, metrics.RepoBundle{Path: p.Repos[i].Repo, Bundle: payloadBundle(&p.Repos[i], since, until)})
+	}
+	return b
+}
+
+// trendBundles rebuilds one bundle per compared period from the metric
+// values the comparison recorded. Only those values survive in JSON, so the
+// bundles hold just enough for metricTrend to read them back: a sample
+// count of one marks a median present, and commit size buckets become
+// counts out of bucketScale.
+func trendBundles(periods []ComparePeriodJSON) []metrics.Bundle {
+	found := false
+	for _, p := range periods {
+		found = found || len(p.Metrics) > 0
+	}
+	if !found {
+		return nil
+	}
+	out := make([]metrics.Bundle, len(periods))
+	for i, p := range periods {
+		b := &out[i]
+		var buckets []float64
+		var keys []string
+		for _, m := range p.Metrics {
+			if m.Value == nil {
+				continue
+			}
+			v := *m.Value
+			switch m.Metric {
+			case keyCommitP50:
+				commitSize(b).P50 = int(v)
+			case keyCommitP90:
+				commitSize(b).P90 = int(v)
+			case keyCadence:
+				b.Cadence = &metrics.Cadence{MedianDaysBetween: v, Samples: 1}
+			case keyLeadTime:
+				b.LeadTime = &metrics.LeadTime{MedianDays: v, Samples: 1}
+			case keyDeployLead:
+				deploy(b).MedianDays, deploy(b).Samples = v, 1
+			case keyDeployFreq:
+				deploy(b).PerWeek, deploy(b).Weeks = v, 1
+			case keyChurn:
+				b.Churn = &metrics.Churn{Ratio: v / 100, AddedLines: 1}
+			case keyRevertRate:
+				reverts(b).RevertRate = v / 100
+			case keyFixRate:
+				reverts(b).FixRate = v / 100
+			default:
+				if strings.HasPrefix(m.Metric, "commit_size_") && strings.HasSuffix(m.Metric, "_pct") {
+					keys = append(keys, m.Metric)
+					buckets = append(buckets, v)
+				}
+			}
+		}
+		if len(buckets) > 0 {
+			d := commitSize(b)
+			d.Edges = bucketEdges(keys)
+			d.Counts = make([]int, len(buckets))
+			for j, pct := range buckets {
+				d.Counts[j] = int(math.Round(pct * bucketScale / 100))
+			}
+		}
+	}
+	return out
+}
+
+// bucketScale is the commit total bucket percentages are rebuilt against,
+// large enough to keep them to well past the precision any report shows.
+const bucketScale = 1_000_000_000
+
+func commitSize(b *metrics.Bundle) *metrics.CommitSizeDistribution {
+	if b.CommitSize == nil {
+		b.CommitSize = &metrics.CommitSizeDistribution{Total: bucketScale}
+	}
+	return b.CommitSize
+}
+
+func deploy(b *metrics.Bundle) *metrics.DeployLeadTime {
+	if b.Deploy == nil {
+		b.Deploy = &metrics.DeployLeadTime{}
+	}
+	return b.Deploy
+}
+
+func reverts(b *metrics.Bundle) *metrics.Reverts {
+	if b.Reverts == nil {
+		b.Reverts = &metrics.Reverts{Commits: 1}
+	}
+	return b.Reverts
+}
+
+// bucketEdges recovers commit size bucket edges from the buckets' keys (see
+// sizeBucketKey), e.g. commit_size_lt16_pct, commit_size_16_63_pct and
+// commit_size_64plus_pct give 16 and 64.
+func bucketEdges(keys []string) []int {
+	if slices.Equal(keys, defaultBucketKeys) {
+		return metrics.DefaultSizeEdges
+	}
+	var edges []int
+	for _, k := range keys {
+		label := strings.TrimSuffix(strings.TrimPrefix(k, "commit_size_"), "_pct")
+		label = strings.TrimPrefix(label, "lt")
+		label = strings.TrimSuffix(label, "plus")
+		low, _, _ := strings.Cut(label, "_")
+		if n, err := strconv.Atoi(low); err == nil && !slices.Contains(edges, n) {
+			edges = append(edges, n)
+		}
+	}
+	sort.Ints(edges)
+	return edges
+}
+
+// Merge combines reports of the same kind and author over periods that
+// neither overlap nor share a day, e.g. monthly archives into a quarter:
+// totals, monthly breakdowns, excluded commits, outliers, diagnostics and
+// repositories add up. Baselines, opt-in metrics and --explain describe one
+// period and are left out, each with a diagnostic saying so. Only report
+// and team JSON can be merged.
+func Merge(saved []Saved) (Saved, error) {
+	if len(saved) == 0 {
+		return Saved{}, fmt.Errorf("no reports to merge")
+	}
+	if len(saved) == 1 {
+		return saved[0], nil
+	}
+	kind := saved[0].Kind
+	for _, s := range saved {
+		if s.Kind != kind {
+			return Saved{}, fmt.Errorf("cannot merge %s and %s reports", kind, s.Kind)
+		}
+	}
+	if kind != "report" && kind != "team" {
+		return Saved{}, fmt.Errorf("cannot merge %s reports; only report and team JSON can be merged", kind)
+	}
+
+	sorted := slices.Clone(saved)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].since().Before(sorted[j].since()) })
+	for i := 1; i < len(sorted); i++ {
+		// Periods are whole days, so one ending on the day the next starts
+		// would count that day twice.
+		prev, cur := sorted[i-1], sorted[i]
+		if cur.since().Format("2006-01-02") <= prev.until().Format("2006-01-02") {
+			return Saved{}, fmt.Errorf("cannot merge overlapping periods %s and %s", prev.period(), cur.period())
+		}
+	}
+
+	out := Saved{Kind: kind, Breakdown: "monthly"}
+	out.Bundle.LegacyBenchmark = true
+	var unmerged []metrics.Diagnostic
+	seen := make(map[metrics.Diagnostic]bool)
+	leftOut := func(b metrics.Bundle, author string) {
+		for _, d := range singlePeriodDiagnostics(b, author) {
+			if !seen[d] {
+				seen[d] = true
+				unmerged = append(unmerged, d)
+			}
+		}
+	}
+	for _, s := range sorted {
+		if s.Breakdown != "monthly" {
+			out.Breakdown = ""
+		}
+		out.Bundle.LegacyBenchmark = out.Bundle.LegacyBenchmark && s.Bundle.LegacyBenchmark
+		out.Bundle.Outliers = append(out.Bundle.Outliers, s.Bundle.Outliers...)
+		out.Bundle.Diagnostics = append(out.Bundle.Diagnostics, s.Bundle.Diagnostics...)
+		leftOut(s.Bundle, "")
+		for _, email := range slices.Sorted(maps.Keys(s.Members)) {
+			leftOut(s.Members[email], email)
+		}
+	}
+	out.Bundle.Diagnostics = append(out.Bundle.Diagnostics, unmerged...)
+	sort.SliceStable(out.Bundle.Outliers, func(i, j int) bool {
+		return out.Bundle.Outliers[i].Commit.Lines() > out.Bundle.Outliers[j].Commit.Lines()
+	})
+	since, until := sorted[0].since(), sorted[len(sorted)-1].until()
+	out.Bundle.Since, out.Bundle.Until = since, until
+
+	if kind == "report" {
+		var stats []git.RepoStats
+		for _, s := range sorted {
+			if s.Stats.Author != sorted[0].Stats.Author {
+				return Saved{}, fmt.Errorf("cannot merge reports for %s and %s", sorted[0].Stats.Author, s.Stats.Author)
+			}
+			stats = append(stats, s.Stats)
+		}
+		out.Stats = git.CombineStats(stats)
+		out.Stats.Path = reposLabel(out.Stats.Repos)
+		for _, s := range sorted {
+			if len(s.Stats.Repos) == 0 {
+				// Older reports don't say which repositories they cover.
+				out.Stats.Path = fmt.Sprintf("%d reports", len(sorted))
+				out.Stats.Repos = nil
+				break
+			}
+		}
+		out.Stats.Since, out.Stats.Until = since, until
+	} else {
+		out.Team = git.TeamStats{Since: since, Until: until, Members: make(map[string]git.RepoStats)}
+		out.Members = make(map[string]metrics.Bundle)
+		member := make(map[string][]git.RepoStats)
+		var teamMonthly []git.RepoStats
+		for _, s := range sorted {
+			out.Team.TotalAdded += s.Team.TotalAdded
+			out.Team.TotalDeleted += s.Team.TotalDeleted
+			out.Team.TotalNet += s.Team.TotalNet
+			out.Team.TotalCommits += s.Team.TotalCommits
+			teamMonthly = append(teamMonthly, git.RepoStats{Monthly: s.Team.Monthly})
+			for email, ms := range s.Team.Members {
+				member[email] = append(member[email], ms)
+			}
+		}
+		out.Team.Monthly = git.CombineStats(teamMonthly).Monthly
+		for email, stats := range member {
+			ms := git.CombineStats(stats)
+			ms.Since, ms.Until = since, until
+			out.Team.Members[email] = ms
+			out.Members[email] = metrics.Bundle{Since: since, Until: until}
+		}
+	}
+
+	return out, nil
+}
+
+// MergedScope is the Scope of the diagnostics Merge adds for what it leaves
+// out.
+const MergedScope = "merged reports"
+
+// singlePeriodDiagnostics lists what of b Merge leaves out: its baseline,
+// opt-in metrics and --explain, which describe one period.
+func singlePeriodDiagnostics(b metrics.Bundle, author string) []metrics.Diagnostic {
+	var names []string
+	for _, m := range []struct {
+		name    string
+		present bool
+	}{
+		{metrics.MetricBaseline, b.Baseline != nil},
+		{metrics.MetricCommitSize, b.CommitSize != nil},
+		{metrics.MetricCadence, b.Cadence != nil},
+		{metrics.MetricLeadTime, b.LeadTime != nil},
+		{metrics.MetricDeploy, b.Deploy != nil},
+		{metrics.MetricChurn, b.Churn != nil},
+		{metrics.MetricReverts, b.Reverts != nil},
+		{metrics.MetricExplain, b.Explain != nil},
+	} {
+		if m.present {
+			names = append(names, m.name)
+		}
+	}
+	diags := make([]metrics.Diagnostic, len(names))
+	for i, name := range names {
+		diags[i] = metrics.Diagnostic{Metric: name, Author: author, Scope: MergedScope, Status: metrics.StatusSkipped,
+			Reason: "covers a single period, so it can't be merged"}
+	}
+	return diags
+}
+
+// reposLabel names a report's repositories as git.CombineStats names its
+// Path: the repository when there is one, else how many there are.
+func reposLabel(repos []string) string {
+	if len(repos) == 1 {
+		return repos[0]
+	}
+	return fmt.Sprintf("%d repositories", len(repos))
+}
+
+func (s Saved) since() time.Time {
+	if s.Kind == "team" {
+		return s.Team.Since
+	}
+	return s.Stats.Since
+}
+
+func (s Saved) until() time.Time {
+	if s.Kind == "team" {
+		return s.Team.Until
+	}
+	return s.Stats.Until
+}
+
+func (s Saved) period() string {
+	return s.since().Format("2006-01-02") + ".." + s.until().Format("2006-01-02")
+}
@@ -0,0 +1,198 @@
+package report
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// savedMonth writes and loads back the report of one month of work on repos.
+func savedMonth(t *testing.T, month time.Month, added int, bundle metrics.Bundle, repos ...string) Saved {
+	t.Helper()
+	since := time.Date(2026, month, 1, 0, 0, 0, 0, time.Local)
+	until := since.AddDate(0, 1, -1).Add(23*time.Hour + 59*time.Minute)
+	var stats []git.RepoStats
+	for _, repo := range repos {
+		key := since.Format("2006-01")
+		stats = append(stats, git.RepoStats{
+			Path: repo, Author: "dev@example.com", Since: since, Until: until,
+			Added: added, Deleted: 10, Net: added - 10, Commits: 2,
+			Monthly: map[string]git.MonthStats{key: {Year: 2026, Month: int(month), Added: added, Deleted: 10, Net: added - 10, Commits: 2}},
+		})
+	}
+	data := savedJSON(t, func(filename string) error {
+		return JSON(git.CombineStats(stats), filename, "monthly", bundle)
+	})
+	s, err := Load(data, since.Format("2006-01")+".json")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return s
+}
+
+func TestLoadRoundTrip(t *testing.T) {
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	until := time.Date(2026, 3, 31, 23, 59, 59, 0, time.Local)
+	stats := git.CombineStats([]git.RepoStats{
+		{Path: "/src/api", Author: "dev@example.com", Since: since, Until: until, Added: 1000, Deleted: 100, Net: 900, Commits: 3, FilesChanged: 5,
+			Monthly: map[string]git.MonthStats{"2026-03": {Year: 2026, Month: 3, Added: 1000, Deleted: 100, Net: 900, Commits: 3}}},
+		{Path: "/src/web", Author: "dev@example.com", Since: since, Until: until, Added: 200, Deleted: 100, Net: 100, Commits: 1, FilesChanged: 2,
+			Monthly: map[string]git.MonthStats{"2026-03": {Year: 2026, Month: 3, Added: 200, Deleted: 100, Net: 100, Commits: 1}}},
+	})
+	stats.Excluded = []string{strings.Repeat("b", 40)}
+	bundle := fullBundle(since, until)
+	bundle.Repos = nil // per-repo metrics aren't read back
+	want := savedJSON(t, func(filename string) error {
+		return JSON(stats, filename, "monthly", bundle)
+	})
+
+	s, err := Load(want, "r.json")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if s.Kind != "report" || s.Breakdown != "monthly" {
+		t.Errorf("Kind=%q Breakdown=%q", s.Kind, s.Breakdown)
+	}
+	if s.Stats.Path != "2 repositories" || !slices.Equal(s.Stats.Repos, []string{"/src/api", "/src/web"}) {
+		t.Errorf("Path=%q Repos=%v, want the recorded repositories", s.Stats.Path, s.Stats.Repos)
+	}
+	got := savedJSON(t, func(filename string) error {
+		return JSON(s.Stats, filename, s.Breakdown, s.Bundle)
+	})
+	if string(got) != string(want) {
+		t.Errorf("report changed on the way through Load:\n--- got\n%s\n--- want\n%s", got, want)
+	}
+
+	// One repository names the report; without repos (before 1.1) the
+	// file name stands in.
+	if s := savedMonth(t, time.March, 100, metrics.Bundle{}, "/src/api"); s.Stats.Path != "/src/api" {
+		t.Errorf("single repo: Path=%q, want /src/api", s.Stats.Path)
+	}
+	old := strings.Replace(string(want), `"schema_version": "1.1"`, `"schema_version": "1.0"`, 1)
+	old = strings.Replace(old, `"repos": [
+    "/src/api",
+    "/src/web"
+  ],`, "", 1)
+	s, err = Load([]byte(old), "r.json")
+	if err != nil {
+		t.Fatalf("Load 1.0: %v", err)
+	}
+	if s.Stats.Path != "r.json" || s.Stats.Repos != nil {
+		t.Errorf("1.0 report: Path=%q Repos=%v, want r.json and none", s.Stats.Path, s.Stats.Repos)
+	}
+
+	if _, err := Load([]byte(`{"schema_version": "2.0", "author": "x"}`), "v2.json"); err == nil {
+		t.Error("Load accepted schema_version 2.0")
+	}
+}
+
+func TestMergeReports(t *testing.T) {
+	withBaseline := metrics.Bundle{Baseline: &metrics.Baseline{LOCPerDay: 50, Weeks: 12}, Cadence: &metrics.Cadence{Samples: 3}}
+	jul := savedMonth(t, time.July, 110, withBaseline, "/src/api")
+	aug := savedMonth(t, time.August, 210, metrics.Bundle{}, "/src/api", "/src/web")
+	sep := savedMonth(t, time.September, 310, withBaseline, "/src/web")
+
+	out, err := Merge([]Saved{sep, jul, aug})
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	s := out.Stats
+	if s.Added != 110+2*210+310 || s.Deleted != 40 || s.Commits != 8 {
+		t.Errorf("Added=%d Deleted=%d Commits=%d", s.Added, s.Deleted, s.Commits)
+	}
+	if s.Since.Format("2006-01-02") != "2026-07-01" || s.Until.Format("2006-01-02") != "2026-09-30" {
+		t.Errorf("period %s..%s, want 2026-07-01..2026-09-30", s.Since.Format("2006-01-02"), s.Until.Format("2006-01-02"))
+	}
+	if len(s.Monthly) != 3 || s.Monthly["2026-08"].Added != 420 || out.Breakdown != "monthly" {
+		t.Errorf("monthly = %+v, breakdown %q", s.Monthly, out.Breakdown)
+	}
+	if s.Path != "2 repositories" || !slices.Equal(s.Repos, []string{"/src/api", "/src/web"}) {
+		t.Errorf("Path=%q Repos=%v", s.Path, s.Repos)
+	}
+
+	// What covers a single period is left out, once each, with a reason.
+	if out.Bundle.Baseline != nil || out.Bundle.Cadence != nil {
+		t.Error("merged a baseline or cadence")
+	}
+	var left []string
+	for _, d := range out.Bundle.Diagnostics {
+		if d.Scope == MergedScope && d.Status == metrics.StatusSkipped {
+			left = append(left, d.Metric)
+		}
+	}
+	if !slices.Equal(left, []string{metrics.MetricBaseline, metrics.MetricCadence}) {
+		t.Errorf("left out %v, want baseline and cadence", left)
+	}
+	if n := len(notableDiagnostics(out.Bundle.Diagnostics)); n != 2 {
+		t.Errorf("%d notable diagnostics, want the 2 left out", n)
+	}
+
+	// Merging one repository's months keeps its name.
+	one, err := Merge([]Saved{jul, savedMonth(t, time.August, 10, metrics.Bundle{}, "/src/api")})
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if one.Stats.Path != "/src/api" {
+		t.Errorf("Path=%q, want /src/api", one.Stats.Path)
+	}
+}
+
+func TestMergeRejects(t *testing.T) {
+	jul := savedMonth(t, time.July, 100, metrics.Bundle{}, "/src/api")
+	touching := jul
+	touching.Stats.Since = jul.Stats.Until
+	touching.Stats.Until = jul.Stats.Until.AddDate(0, 0, 7)
+	overlapping := jul
+	overlapping.Stats.Since = jul.Stats.Since.AddDate(0, 0, 10)
+	overlapping.Stats.Until = jul.Stats.Until.AddDate(0, 0, 10)
+	other := savedMonth(t, time.August, 100, metrics.Bundle{}, "/src/api")
+	other.Stats.Author = "someone@example.com"
+	dora := Saved{Kind: "dora"}
+
+	for name, in := range map[string][]Saved{
+		"touching":      {jul, touching},
+		"overlapping":   {overlapping, jul},
+		"other author":  {jul, other},
+		"mixed kinds":   {jul, {Kind: "team"}},
+		"dora":          {dora, dora},
+		"nothing given": nil,
+	} {
+		if _, err := Merge(in); err == nil {
+			t.Errorf("%s: Merge succeeded", name)
+		}
+	}
+}
+
+func TestMergeTeamReports(t *testing.T) {
+	team := func(month time.Month, net int) Saved {
+		since := time.Date(2026, month, 1, 0, 0, 0, 0, time.Local)
+		until := since.AddDate(0, 1, -1)
+		member := git.RepoStats{Author: "a@x.com", Since: since, Until: until, Added: net, Net: net, Commits: 1}
+		stats := git.TeamStats{Since: since, Until: until, Members: map[string]git.RepoStats{"a@x.com": member},
+			TotalAdded: net, TotalNet: net, TotalCommits: 1}
+		members := map[string]metrics.Bundle{"a@x.com": {Baseline: &metrics.Baseline{Weeks: 4}}}
+		data := savedJSON(t, func(filename string) error {
+			return TeamJSON(stats, filename, "", metrics.Bundle{}, members)
+		})
+		s, err := Load(data, "team.json")
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		return s
+	}
+
+	out, err := Merge([]Saved{team(time.July, 100), team(time.August, 50)})
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if out.Kind != "team" || out.Team.TotalNet != 150 || out.Team.Members["a@x.com"].Net != 150 {
+		t.Errorf("Kind=%q TotalNet=%d member net %d", out.Kind, out.Team.TotalNet, out.Team.Members["a@x.com"].Net)
+	}
+	if len(out.Bundle.Diagnostics) != 1 || out.Bundle.Diagnostics[0].Author != "a@x.com" || out.Bundle.Diagnostics[0].Metric != metrics.MetricBaseline {
+		t.Errorf("diagnostics = %+v, want the member's baseline left out", out.Bundle.Diagnostics)
+	}
+}
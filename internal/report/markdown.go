@@ -0,0 +1,523 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juangracia/gitrespect/internal/benchmark"
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// mdReport builds a GitHub-flavored Markdown report with the same sections
+// as the terminal one, for pasting into PRs and wikis.
+type mdReport struct {
+	strings.Builder
+}
+
+func (r *mdReport) line(format string, args ...any) {
+	fmt.Fprintf(r, format+"\n", args...)
+}
+
+// heading starts a section; level 2 is the report title.
+func (r *mdReport) heading(level int, title string) {
+	r.line("%s %s\n", strings.Repeat("#", level), title)
+}
+
+// table writes a GFM table followed by a blank line.
+func (r *mdReport) table(header []string, rows [][]string) {
+	r.line("| %s |", strings.Join(header, " | "))
+	r.line("|%s", strings.Repeat(" --- |", len(header)))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = mdCell(c)
+		}
+		r.line("| %s |", strings.Join(cells, " | "))
+	}
+	r.line("")
+}
+
+// save writes the report to filename, or stdout when it is empty.
+func (r *mdReport) save(filename string) error {
+	if filename == "" {
+		fmt.Print(r.String())
+		return nil
+	}
+	if err := os.WriteFile(filename, []byte(r.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	fmt.Printf("✓ Report saved to %s\n", filename)
+	return nil
+}
+
+// mdCell escapes a value for a table cell or list item, keeping it on one
+// line.
+func mdCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
+
+// Markdown writes the single-author report as Markdown. repos adds the
+// repository breakdown, as with --per-repo in the terminal.
+func Markdown(stats git.RepoStats, repos []git.RepoStats, filename string, breakdown string, bundle metrics.Bundle) error {
+	workingDays := git.WorkingDays(stats.Since, stats.Until)
+	locPerDay := float64(stats.Net) / float64(workingDays)
+	repoName := filepath.Base(stats.Path)
+	if strings.Contains(stats.Path, "repositories") {
+		repoName = stats.Path
+	}
+
+	var r mdReport
+	r.heading(2, "gitrespect - "+stats.Author)
+	r.line("_%s (%s to %s)_\n", repoName, stats.Since.Format("Jan 2 2006"), stats.Until.Format("Jan 2 2006"))
+	r.table([]string{"Added", "Deleted", "Net", "Commits"},
+		[][]string{{formatNumber(stats.Added), formatNumber(stats.Deleted), formatNumber(stats.Net), fmt.Sprint(stats.Commits)}})
+	r.line("- **Daily avg:** %.0f lines/day (%d working days)", locPerDay, workingDays)
+	if !stats.FirstCommit.IsZero() && !stats.LastCommit.IsZero() {
+		r.line("- **Activity:** %s to %s", stats.FirstCommit.Format("Jan 2 2006"), stats.LastCommit.Format("Jan 2 2006"))
+	}
+	if n := len(stats.Excluded); n > 0 {
+		r.line("- **Excluded:** %d %s (--exclude-commit, %s)", n, plural(n, "commit"), git.ExcludeCommitConfigKey)
+	}
+	r.line("")
+
+	r.outliers(bundle.Outliers)
+	r.explain(bundle.Explain)
+	if bundle.LegacyBenchmark {
+		r.legacyBenchmark("vs Industry", locPerDay, workingDays)
+	} else if bundle.Baseline != nil {
+		r.baseline("Baseline", "Your", bundle.Baseline)
+	}
+	r.metrics(bundle)
+	r.diagnostics(bundle.Diagnostics)
+	r.breakdown(breakdown, stats.Monthly, stats.Weekly)
+
+	if len(repos) > 1 {
+		sorted := append([]git.RepoStats(nil), repos...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Net > sorted[j].Net })
+		var rows [][]string
+		for _, repo := range sorted {
+			if repo.Commits > 0 {
+				rows = append(rows, []string{filepath.Base(repo.Path), formatNumber(repo.Net), fmt.Sprint(repo.Commits)})
+			}
+		}
+		r.heading(3, "Repository Breakdown")
+		r.table([]string{"Repository", "Net", "Commits"}, rows)
+	}
+	return r.save(filename)
+}
+
+// TeamMarkdown writes the team report as Markdown.
+func TeamMarkdown(stats git.TeamStats, filename string, breakdown string, team metrics.Bundle, bundles map[string]metrics.Bundle) error {
+	workingDays := teamWorkingDays(stats)
+	locPerDay := float64(stats.TotalNet) / float64(workingDays)
+
+	var r mdReport
+	r.heading(2, "gitrespect - Team Report")
+	r.line("_%s to %s_\n", stats.Since.Format("Jan 2 2006"), stats.Until.Format("Jan 2 2006"))
+	r.heading(3, "Team Totals")
+	r.table([]string{"Added", "Deleted", "Net", "Commits"},
+		[][]string{{formatNumber(stats.TotalAdded), formatNumber(stats.TotalDeleted), formatNumber(stats.TotalNet), fmt.Sprint(stats.TotalCommits)}})
+	r.line("- **Team daily avg:** %.0f lines/day (%d working days)\n", locPerDay, workingDays)
+
+	if team.LegacyBenchmark && len(stats.Members) > 0 {
+		r.legacyBenchmark("Average member vs Industry", locPerDay/float64(len(stats.Members)), workingDays)
+	} else if team.Baseline != nil {
+		r.baseline("Team baseline", "Team", team.Baseline)
+	}
+	if team.Deploy != nil {
+		r.metrics(team)
+	}
+
+	emails := sortedMembers(stats)
+
+	showBaselines := !team.LegacyBenchmark && hasMemberBaselines(bundles)
+	header := []string{"Contributor", "Net", "Commits", "/day"}
+	if showBaselines {
+		header = append(header, "Normal/day")
+	}
+	var rows [][]string
+	for _, email := range emails {
+		m := stats.Members[email]
+		row := []string{email, formatNumber(m.Net), fmt.Sprint(m.Commits), fmt.Sprintf("%.0f", float64(m.Net)/float64(workingDays))}
+		if showBaselines {
+			row = append(row, mdBaselineCell(bundles[email].Baseline))
+		}
+		rows = append(rows, row)
+	}
+	r.heading(3, "Team Members")
+	r.table(header, rows)
+
+	for _, email := range emails {
+		b, ok := bundles[email]
+		if !ok || !hasAnyMetric(b) {
+			continue
+		}
+		r.heading(3, email)
+		r.metrics(b)
+	}
+	r.diagnostics(team.Diagnostics)
+	r.breakdown(breakdown, stats.Monthly, nil)
+	return r.save(filename)
+}
+
+// CompareMarkdown writes a comparison as Markdown.
+func CompareMarkdown(comparison git.CompareStats, filename string, details CompareDetails) error {
+	periods := periodStats(comparison.Periods)
+	labels := periodLabels(comparison.Periods)
+	if len(periods) == 0 {
+		return fmt.Errorf("no periods to compare")
+	}
+
+	var r mdReport
+	r.heading(2, "gitrespect - Period Comparison")
+	var rows [][]string
+	for i, p := range periods {
+		change := "—"
+		if i > 0 {
+			change = mdMultiplier(perDay(periods[0]), perDay(p))
+		}
+		rows = append(rows, []string{labels[i], formatNumber(p.Net), fmt.Sprint(git.WorkingDays(p.Since, p.Until)), fmt.Sprintf("%.0f", perDay(p)), change})
+	}
+	r.table([]string{"Period", "Net Lines", "Days", "Per Day", "vs " + labels[0]}, rows)
+
+	for i := 1; i < len(periods); i++ {
+		sig := details.SignificanceAt(i)
+		if len(periods) > 2 {
+			r.heading(3, labels[i]+" vs "+labels[0])
+		}
+		multiplier := changeVsFirst(periods, sig, i)
+		change := fmt.Sprintf("%.1fx lines/day", multiplier)
+		if multiplier >= 1 {
+			change = "+" + change
+		}
+		if sig.RatioLow != 0 || sig.RatioHigh != 0 {
+			change += fmt.Sprintf(" (%.0f%% CI %.1fx–%.1fx)", sig.Confidence*100, sig.RatioLow, sig.RatioHigh)
+		}
+		r.line("- **Change:** %s", change)
+		r.line("- **Weekly samples:** %d vs %d", sig.BeforeWeeks, sig.AfterWeeks)
+		if sig.Insufficient {
+			r.line("- ⚠ **Not enough data:** need %d+ weeks in each period for a meaningful test; treat this change as anecdotal.", metrics.MinSignificanceWeeks)
+		} else {
+			verdict := "not significant"
+			if sig.Significant() {
+				verdict = "significant"
+			}
+			r.line("- **Significance:** %s (Mann-Whitney p=%.3f), %s effect (Cliff's δ=%+.2f)", verdict, sig.PValue, sig.Effect, sig.EffectSize)
+		}
+		r.line("")
+	}
+
+	if series := metricTrend(details.Metrics); len(series) > 0 {
+		r.heading(3, "Metrics")
+		r.metricTrend(labels, series)
+	}
+	if len(details.Repos) > 0 {
+		r.heading(3, "Repository Breakdown")
+		r.rowTrend("Repository", labels, details.Repos, nil)
+	}
+	if len(details.Members) > 0 {
+		r.heading(3, "Team Members")
+		r.rowTrend("Contributor", labels, nil, details.Members)
+		for _, m := range details.Members {
+			if series := metricTrend(m.Metrics); len(series) > 0 {
+				r.heading(4, m.Email)
+				r.metricTrend(labels, series)
+			}
+		}
+	}
+	r.diagnostics(details.Diagnostics)
+	return r.save(filename)
+}
+
+// outliers lists the commits that distort the totals.
+func (r *mdReport) outliers(outliers []git.Outlier) {
+	if len(outliers) == 0 {
+		return
+	}
+	r.heading(3, fmt.Sprintf("⚠ Outlier commits (%d)", len(outliers)))
+	r.line("These may distort the totals. Leave one out with `--exclude-commit %s` or `git config --add %s %s`.\n",
+		shortHash(outliers[0].Commit.Hash), git.ExcludeCommitConfigKey, shortHash(outliers[0].Commit.Hash))
+	var rows [][]string
+	for _, o := range outliers {
+		rows = append(rows, append(mdChange(o.Commit), strings.Join(o.Reasons, "; ")))
+	}
+	r.table([]string{"Commit", "Date", "Added", "Deleted", "Subject", "Why"}, rows)
+}
+
+// explain lists the commits and files behind an --explain bucket.
+func (r *mdReport) explain(ex *git.Explanation) {
+	if ex == nil {
+		return
+	}
+	r.heading(3, "Explain "+explainTitle(ex.Bucket))
+	r.line("+%s -%s, net %s over %d %s\n", formatNumber(ex.Added), formatNumber(ex.Deleted), formatNumber(ex.Net), ex.Commits, plural(ex.Commits, "commit"))
+	if ex.Commits == 0 {
+		return
+	}
+	changed := ex.Added + ex.Deleted
+	var rows [][]string
+	for _, c := range ex.Top {
+		rows = append(rows, append(mdChange(c), sharePct(c.Lines(), changed)))
+	}
+	r.table([]string{"Commit", "Date", "Added", "Deleted", "Subject", "Share"}, rows)
+	rows = nil
+	for _, f := range ex.Files {
+		rows = append(rows, []string{"`" + f.Path + "`", formatNumber(f.Added), formatNumber(f.Deleted), fmt.Sprint(f.Commits), sharePct(f.Lines(), changed)})
+	}
+	r.table([]string{"File", "Added", "Deleted", "Commits", "Share"}, rows)
+}
+
+// mdChange is a commit's hash, date, lines and subject as table cells.
+func mdChange(c git.CommitChange) []string {
+	return []string{"`" + shortHash(c.Hash) + "`", c.Date.Format("Jan 2"), "+" + formatNumber(c.Added), "-" + formatNumber(c.Deleted), c.Subject}
+}
+
+func (r *mdReport) legacyBenchmark(title string, locPerDay float64, workingDays int) {
+	r.heading(3, title)
+	if workingDays < 21 {
+		r.line("Pace: %.0f lines/day (industry comparison requires 30+ days of activity)\n", locPerDay)
+		return
+	}
+	for _, c := range benchmark.Compare(locPerDay) {
+		r.line("- %s (%d/day): %.1fx", c.Label, c.Benchmark, c.Multiplier)
+	}
+	r.line("")
+}
+
+// baseline prints a baseline and, when computed, its seasonal band. whose is
+// "Your" or "Team".
+func (r *mdReport) baseline(title, whose string, b *metrics.Baseline) {
+	normalRange := strings.ToLower(whose) + " normal range"
+	r.heading(3, fmt.Sprintf("%s (%dd prior)", title, int(b.WindowEnd.Sub(b.WindowStart).Hours()/24)))
+	if b.InsufficientHistory {
+		r.line("- insufficient prior history")
+	} else {
+		r.line("- %s normal: %.0f lines/day (typical week %.0f–%.0f) → this period: %.0f (%s)",
+			whose, b.LOCPerDay, b.P25, b.P75, b.PeriodLOCPerDay, mdPosition(b.Position, normalRange))
+	}
+	if s := b.Seasonal; s != nil {
+		if s.Insufficient {
+			r.line("- Same period last year: insufficient history")
+		} else {
+			r.line("- Same period last year: %.0f lines/day (typical week %.0f–%.0f) (%s)", s.Median, s.P25, s.P75, mdPosition(s.Position, "last year's range"))
+		}
+	}
+	r.line("")
+}
+
+// mdPosition describes where the period falls relative to a band.
+func mdPosition(position, band string) string {
+	switch position {
+	case metrics.PositionAbove:
+		return "above " + band + " ↑"
+	case metrics.PositionBelow:
+		return "below " + band + " ↓"
+	default:
+		return "within " + band
+	}
+}
+
+// mdBaselineCell summarizes a member's baseline, e.g. "84 ↑ above".
+func mdBaselineCell(b *metrics.Baseline) string {
+	if b == nil || b.InsufficientHistory {
+		return "no history"
+	}
+	switch b.Position {
+	case metrics.PositionAbove:
+		return fmt.Sprintf("%.0f ↑ above", b.LOCPerDay)
+	case metrics.PositionBelow:
+		return fmt.Sprintf("%.0f ↓ below", b.LOCPerDay)
+	default:
+		return fmt.Sprintf("%.0f = within", b.LOCPerDay)
+	}
+}
+
+// mdNormal is the " (your normal: ...)" suffix for a metric, if any.
+func mdNormal(b metrics.Bundle, key string) string {
+	if note := normalNote(b, key); note != "" {
+		return " _(" + note + ")_"
+	}
+	return ""
+}
+
+func (r *mdReport) metrics(b metrics.Bundle) {
+	if d := b.CommitSize; d != nil {
+		r.line("**Commit size distribution**\n")
+		var rows [][]string
+		for i := range d.Counts {
+			row := []string{sizeBucketName(d, i), fmt.Sprint(d.Counts[i]), fmt.Sprintf("%.0f%%", d.Percent(i))}
+			if b.Normal != nil {
+				row = append(row, strings.TrimPrefix(normalNote(b, sizeBucketKey(d, i)), "your normal: "))
+			}
+			rows = append(rows, row)
+		}
+		header := []string{"Size", "Commits", "Share"}
+		if b.Normal != nil {
+			header = append(header, "Your normal")
+		}
+		r.table(header, rows)
+		r.line("- p50 %d · p90 %d · p99 %d lines changed%s", d.P50, d.P90, d.P99, mdNormal(b, keyCommitP50))
+		r.line("- Added p50 %d · p90 %d, deleted p50 %d · p90 %d\n", d.Added.P50, d.Added.P90, d.Deleted.P50, d.Deleted.P90)
+		if len(d.Largest) > 0 {
+			rows = nil
+			for _, c := range d.Largest {
+				rows = append(rows, []string{"`" + shortHash(c.Hash) + "`", fmt.Sprintf("+%d", c.Added), fmt.Sprintf("-%d", c.Deleted), c.Subject})
+			}
+			r.table([]string{"Largest", "Added", "Deleted", "Subject"}, rows)
+		}
+	}
+	if c := b.Cadence; c != nil {
+		switch {
+		case c.MainBranch == "":
+			r.line("**Integration cadence:** no main branch found (see --main-branch)\n")
+		case c.Samples < 1:
+			r.line("**Integration cadence:** insufficient data (need 2+ commits on %s)%s\n", c.MainBranch, mdNormal(b, keyCadence))
+		default:
+			r.line("**Integration cadence:** median %.1f days between commits to %s%s\n", c.MedianDaysBetween, c.MainBranch, mdNormal(b, keyCadence))
+		}
+	}
+	if lt := b.LeadTime; lt != nil {
+		switch {
+		case lt.MainBranch == "":
+			r.line("**Lead time (branch → main):** no main branch found (see --main-branch)\n")
+		case lt.Samples == 0:
+			r.line("**Lead time (branch → main):** no changes into %s with a detectable branch in period%s\n", lt.MainBranch, mdNormal(b, keyLeadTime))
+		default:
+			r.line("**Lead time (branch → main):** median %.1f days (%d changes into %s: %s)%s\n", lt.MedianDays, lt.Samples, lt.MainBranch, leadMethods(lt), mdNormal(b, keyLeadTime))
+		}
+	}
+	if d := b.Deploy; d != nil {
+		r.line("**Lead time to deploy (%s):**\n", d.Source)
+		if d.Samples == 0 {
+			r.line("- no deploys shipping these commits in period%s\n", mdNormal(b, keyDeployLead))
+		} else {
+			r.line("- Median %.1f days from commit to deploy (%d commits)%s", d.MedianDays, d.Samples, mdNormal(b, keyDeployLead))
+			r.line("- %d deploys, %.2f/week%s\n", d.Deploys, d.PerWeek, mdNormal(b, keyDeployFreq))
+		}
+	}
+	if c := b.Churn; c != nil {
+		if c.AddedLines == 0 {
+			r.line("**Churn rate:** no added lines to analyze%s\n", mdNormal(b, keyChurn))
+		} else {
+			r.line("**Churn rate:** %.0f%% of added lines rewritten within %d days%s\n", c.Ratio*100, c.WindowDays, mdNormal(b, keyChurn))
+		}
+	}
+	if rv := b.Reverts; rv != nil {
+		r.line("**Reverts & fixes:**\n")
+		if rv.Commits == 0 {
+			r.line("- no commits in period%s\n", mdNormal(b, keyRevertRate))
+		} else {
+			r.line("- %d of %d commits reverted (%.0f%%)%s%s", rv.Reverted, rv.Commits, rv.RevertRate*100, afterDays(rv.Reverted, rv.MedianDaysToRevert), mdNormal(b, keyRevertRate))
+			r.line("- %d fixed within %d days (%.0f%%)%s%s\n", rv.Fixed, rv.WindowDays, rv.FixRate*100, afterDays(rv.Fixed, rv.MedianDaysToFix), mdNormal(b, keyFixRate))
+		}
+	}
+	if labels, series := repoMetricTrend(b); len(series) > 0 {
+		r.line("**Metrics by repository**\n")
+		rows := make([][]string, len(series))
+		for i, s := range series {
+			rows[i] = []string{s.Label}
+			for j := range labels {
+				rows[i] = append(rows[i], formatMetricValue(s.Values[j], s.Unit, s.Has[j]))
+			}
+		}
+		r.table(append([]string{"Metric"}, labels...), rows)
+	}
+}
+
+// metricTrend prints one row per metric with a column per period; later
+// periods show their change against the first.
+func (r *mdReport) metricTrend(labels []string, series []metricSeries) {
+	rows := make([][]string, len(series))
+	for i, s := range series {
+		rows[i] = []string{s.Label}
+		for j := range labels {
+			cell := formatMetricValue(s.Values[j], s.Unit, s.Has[j])
+			if j > 0 {
+				cell += " (" + formatMetricDelta(s, j) + ")"
+			}
+			rows[i] = append(rows[i], cell)
+		}
+	}
+	r.table(append([]string{"Metric"}, labels...), rows)
+}
+
+// rowTrend prints lines/day per period for each repository or member, with
+// the multiplier against the first period.
+func (r *mdReport) rowTrend(title string, labels []string, repos []RepoComparison, members []MemberComparison) {
+	var rows [][]string
+	add := func(name string, periods []git.RepoStats) {
+		row := []string{name}
+		for i, p := range periods {
+			cell := fmt.Sprintf("%.0f/day", perDay(p))
+			if i > 0 {
+				if m := multiplierVsFirst(periods, i); m != 0 {
+					cell += fmt.Sprintf(" (%.1fx)", m)
+				}
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+	for _, repo := range repos {
+		add(filepath.Base(repo.Path), repo.Periods)
+	}
+	for _, m := range members {
+		add(m.Email, m.Periods)
+	}
+	r.table(append([]string{title}, labels...), rows)
+}
+
+// mdMultiplier renders after/before as "1.8x", or "n/a".
+func mdMultiplier(before, after float64) string {
+	if m := benchmark.CalculateMultiplier(before, after); m != 0 {
+		return fmt.Sprintf("%.1fx", m)
+	}
+	return "n/a"
+}
+
+func (r *mdReport) diagnostics(diags []metrics.Diagnostic) {
+	notable := notableDiagnostics(diags)
+	if len(notable) == 0 {
+		return
+	}
+	r.heading(3, "Diagnostics")
+	for _, d := range notable {
+		r.line("- %s", mdCell(shortDiagnostic(d)))
+	}
+	r.line("")
+}
+
+// breakdown prints the monthly or weekly breakdown, if requested.
+func (r *mdReport) breakdown(breakdown string, monthly map[string]git.MonthStats, weekly map[string]git.WeekStats) {
+	var rows [][]string
+	switch {
+	case breakdown == "monthly" && len(monthly) > 0:
+		keys := make([]string, 0, len(monthly))
+		for k := range monthly {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			m := monthly[k]
+			rows = append(rows, []string{fmt.Sprintf("%s %d", getMonthName(m.Month), m.Year), formatNumber(m.Added), formatNumber(m.Deleted), formatNumber(m.Net), fmt.Sprint(m.Commits)})
+		}
+		r.heading(3, "Monthly Breakdown")
+		r.table([]string{"Month", "Added", "Deleted", "Net", "Commits"}, rows)
+	case breakdown == "weekly" && len(weekly) > 0:
+		keys := make([]string, 0, len(weekly))
+		for k := range weekly {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			w := weekly[k]
+			rows = append(rows, []string{k, formatNumber(w.Added), formatNumber(w.Deleted), formatNumber(w.Net), fmt.Sprint(w.Commits)})
+		}
+		r.heading(3, "Weekly Breakdown")
+		r.table([]string{"Week", "Added", "Deleted", "Net", "Commits"}, rows)
+	}
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// Metric keys shared by the compare trend and the "your normal" notes.
+const (
+	keyCommitMicro  = "commit_size_micro_pct"
+	keyCommitSmall  = "commit_size_small_pct"
+	keyCommitMedium = "commit_size_medium_pct"
+	keyCommitLarge  = "commit_size_large_pct"
+	keyCommitP50    = "commit_size_p50_lines"
+	keyCommitP90    = "commit_size_p90_lines"
+	keyCadence      = "cadence_median_days"
+	keyLeadTime     = "lead_time_median_days"
+	keyDeployLead   = "deploy_lead_time_median_days"
+	keyDeployFreq   = "deploys_per_week"
+	keyChurn        = "churn_pct"
+	keyRevertRate   = "revert_rate_pct"
+	keyFixRate      = "fix_rate_pct"
+)
+
+// normalValue reads the metric with the given trendMetrics key from the
+// bundle's baseline-window metrics.
+func normalValue(b metrics.Bundle, key string) (value float64, unit string, ok bool) {
+	if b.Normal == nil {
+		return 0, "", false
+	}
+	for _, tm := range append(sizeBucketTrends([]metrics.Bundle{*b.Normal}), trendMetrics...) {
+		if tm.key == key {
+			v, has := tm.value(*b.Normal)
+			return v, tm.unit, has
+		}
+	}
+	return 0, "", false
+}
+
+// normalNote formats a metric's baseline-window value for display next to
+// the current one, e.g. "your normal: 0.8 days". Empty when the bundle has no
+// baseline-window metrics.
+func normalNote(b metrics.Bundle, key string) string {
+	if b.Normal == nil {
+		return ""
+	}
+	v, unit, ok := normalValue(b, key)
+	switch {
+	case !ok:
+		return "your normal: no data"
+	case unit == "%":
+		return fmt.Sprintf("your normal: %.0f%%", v)
+	case unit == unitPerWeek:
+		return fmt.Sprintf("your normal: %.2f/week", v)
+	case unit == unitLines:
+		return fmt.Sprintf("your normal: %.0f lines", v)
+	default:
+		return fmt.Sprintf("your normal: %.1f days", v)
+	}
+}
+
+// leadMethods summarizes how a lead time's samples were estimated, e.g.
+// "6 merge, 3 pr-number", noting commits whose branch was not found.
+func leadMethods(lt *metrics.LeadTime) string {
+	counts := lt.MethodCounts()
+	var parts []string
+	for _, m := range []string{metrics.LeadMethodMerge, metrics.LeadMethodTrailer, metrics.LeadMethodPRNumber, metrics.LeadMethodPatchID} {
+		if n := counts[m]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, m))
+		}
+	}
+	if lt.Unmatched > 0 {
+		parts = append(parts, fmt.Sprintf("%d without a branch", lt.Unmatched))
+	}
+	return strings.Join(parts, ", ")
+}
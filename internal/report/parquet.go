@@ -0,0 +1,275 @@
+package report
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/juangracia/gitrespect/internal/git"
+)
+
+// A minimal Parquet writer: one row group, one PLAIN, uncompressed data page
+// per column, all columns required. Enough for DuckDB, pandas and Spark to
+// read an export without pulling in a Parquet library.
+
+// Parquet physical types, converted types and enums used here.
+const (
+	pqBoolean   = 0
+	pqInt64     = 2
+	pqByteArray = 6
+
+	pqUTF8            = 0
+	pqTimestampMillis = 9
+
+	pqDataPage = 0
+	pqPlain    = 0
+	pqRLE      = 3
+)
+
+// pqColumn is one column of the export: its name, types and PLAIN-encoded
+// values.
+type pqColumn struct {
+	name      string
+	typ       int
+	converted int // -1 for none
+	data      bytes.Buffer
+	bits      []bool
+}
+
+func (c *pqColumn) putString(s string) {
+	binary.Write(&c.data, binary.LittleEndian, uint32(len(s)))
+	c.data.WriteString(s)
+}
+
+func (c *pqColumn) putInt(v int64) {
+	binary.Write(&c.data, binary.LittleEndian, v)
+}
+
+// page returns the column's encoded values; booleans are bit-packed.
+func (c *pqColumn) page() []byte {
+	if c.typ != pqBoolean {
+		return c.data.Bytes()
+	}
+	packed := make([]byte, (len(c.bits)+7)/8)
+	for i, b := range c.bits {
+		if b {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return packed
+}
+
+func exportParquet(w io.Writer, records []git.CommitRecord) error {
+	cols := make([]*pqColumn, len(exportColumns))
+	for i, name := range exportColumns {
+		c := &pqColumn{name: name, typ: pqByteArray, converted: pqUTF8}
+		switch name {
+		case "author_date", "committer_date":
+			c.typ, c.converted = pqInt64, pqTimestampMillis
+		case "added", "deleted", "files_changed":
+			c.typ, c.converted = pqInt64, -1
+		case "excluded", "ai_assisted":
+			c.typ, c.converted = pqBoolean, -1
+		}
+		cols[i] = c
+	}
+
+	for _, r := range records {
+		c := commitExportJSON(r)
+		files, err := json.Marshal(c.Files)
+		if err != nil {
+			return err
+		}
+		cols[0].putString(c.Repo)
+		cols[1].putString(c.SHA)
+		cols[2].putString(strings.Join(c.Parents, " "))
+		cols[3].putString(c.AuthorName)
+		cols[4].putString(c.AuthorEmail)
+		cols[5].putInt(r.AuthorDate.UnixMilli())
+		cols[6].putInt(r.CommitDate.UnixMilli())
+		cols[7].putString(c.Subject)
+		cols[8].putInt(int64(c.Added))
+		cols[9].putInt(int64(c.Deleted))
+		cols[10].putInt(int64(c.FilesChanged))
+		cols[11].putString(string(files))
+		cols[12].bits = append(cols[12].bits, c.Excluded)
+		cols[13].bits = append(cols[13].bits, c.AIAssisted)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("PAR1")
+	n := int64(len(records))
+
+	// Column chunks, remembering where each starts and how big it is.
+	offsets := make([]int64, len(cols))
+	sizes := make([]int64, len(cols))
+	if n > 0 {
+		for i, c := range cols {
+			data := c.page()
+			var h thriftWriter
+			h.i32(1, pqDataPage)
+			h.i32(2, int32(len(data)))
+			h.i32(3, int32(len(data)))
+			h.begin(5)
+			h.i32(1, int32(n))
+			h.i32(2, pqPlain)
+			h.i32(3, pqRLE)
+			h.i32(4, pqRLE)
+			h.close()
+			h.stop()
+
+			offsets[i] = int64(out.Len())
+			out.Write(h.Bytes())
+			out.Write(data)
+			sizes[i] = int64(out.Len()) - offsets[i]
+		}
+	}
+
+	var m thriftWriter
+	m.i32(1, 1)
+	m.list(2, thriftStruct, len(cols)+1)
+	m.open()
+	m.binary(4, "schema")
+	m.i32(5, int32(len(cols)))
+	m.close()
+	for _, c := range cols {
+		m.open()
+		m.i32(1, int32(c.typ))
+		m.i32(3, 0) // REQUIRED
+		m.binary(4, c.name)
+		if c.converted >= 0 {
+			m.i32(6, int32(c.converted))
+		}
+		m.close()
+	}
+	m.i64(3, n)
+	if n > 0 {
+		var total int64
+		for _, s := range sizes {
+			total += s
+		}
+		m.list(4, thriftStruct, 1)
+		m.open()
+		m.list(1, thriftStruct, len(cols))
+		for i, c := range cols {
+			m.open()
+			m.i64(2, offsets[i])
+			m.begin(3)
+			m.i32(1, int32(c.typ))
+			m.list(2, thriftI32, 1)
+			m.varint(pqPlain)
+			m.list(3, thriftBinary, 1)
+			m.str(c.name)
+			m.i32(4, 0) // UNCOMPRESSED
+			m.i64(5, n)
+			m.i64(6, sizes[i])
+			m.i64(7, sizes[i])
+			m.i64(9, offsets[i])
+			m.close()
+			m.close()
+		}
+		m.i64(2, total)
+		m.i64(3, n)
+		m.close()
+	} else {
+		m.list(4, thriftStruct, 0)
+	}
+	m.binary(6, "gitrespect")
+	m.stop()
+
+	out.Write(m.Bytes())
+	binary.Write(&out, binary.LittleEndian, uint32(m.Len()))
+	out.WriteString("PAR1")
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// Thrift compact protocol types.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the Thrift compact protocol structs Parquet's
+// metadata is made of. Fields must be written in increasing id order.
+type thriftWriter struct {
+	bytes.Buffer
+	last  int
+	stack []int
+}
+
+func (t *thriftWriter) varint(v int64) {
+	u := uint64((v << 1) ^ (v >> 63))
+	t.uvarint(u)
+}
+
+func (t *thriftWriter) uvarint(u uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	t.Write(buf[:binary.PutUvarint(buf[:], u)])
+}
+
+func (t *thriftWriter) field(id, typ int) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.WriteByte(byte(delta<<4 | typ))
+	} else {
+		t.WriteByte(byte(typ))
+		t.varint(int64(id))
+	}
+	t.last = id
+}
+
+func (t *thriftWriter) i32(id int, v int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int, v int64) {
+	t.field(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) str(s string) {
+	t.uvarint(uint64(len(s)))
+	t.WriteString(s)
+}
+
+func (t *thriftWriter) binary(id int, s string) {
+	t.field(id, thriftBinary)
+	t.str(s)
+}
+
+// list starts a list field; its n elements are written next.
+func (t *thriftWriter) list(id, elem, n int) {
+	t.field(id, thriftList)
+	if n < 15 {
+		t.WriteByte(byte(n<<4 | elem))
+	} else {
+		t.WriteByte(byte(0xf0 | elem))
+		t.uvarint(uint64(n))
+	}
+}
+
+// begin starts a struct field; close ends it.
+func (t *thriftWriter) begin(id int) {
+	t.field(id, thriftStruct)
+	t.open()
+}
+
+// open starts a struct, e.g. a list element; close ends it.
+func (t *thriftWriter) open() {
+	t.stack = append(t.stack, t.last)
+	t.last = 0
+}
+
+func (t *thriftWriter) close() {
+	t.stop()
+	t.last = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+func (t *thriftWriter) stop() { t.WriteByte(0) }
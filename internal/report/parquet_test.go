@@ -0,0 +1,354 @@
+package report
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+)
+
+// thriftReader decodes the Thrift compact protocol into generic values:
+// structs as map[int]any keyed by field id, lists as []any, integers as
+// int64 and binaries as string.
+type thriftReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *thriftReader) byte() byte {
+	c := r.b[r.pos]
+	r.pos++
+	return c
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	u, n := binary.Uvarint(r.b[r.pos:])
+	if n <= 0 {
+		panic(fmt.Sprintf("bad varint at %d", r.pos))
+	}
+	r.pos += n
+	return u
+}
+
+func (r *thriftReader) zigzag() int64 {
+	u := r.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case 1, 2:
+		return typ == 1
+	case 3:
+		return int64(int8(r.byte()))
+	case 4, 5, 6:
+		return r.zigzag()
+	case thriftBinary:
+		n := int(r.uvarint())
+		s := string(r.b[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case thriftList:
+		h := r.byte()
+		n, elem := int(h>>4), h&0x0f
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(elem)
+		}
+		return list
+	case thriftStruct:
+		return r.structure()
+	}
+	panic(fmt.Sprintf("unexpected thrift type %d at %d", typ, r.pos))
+}
+
+func (r *thriftReader) structure() map[int]any {
+	fields := make(map[int]any)
+	last := 0
+	for {
+		h := r.byte()
+		if h == 0 {
+			return fields
+		}
+		id := last + int(h>>4)
+		if h>>4 == 0 {
+			id = int(r.zigzag())
+		}
+		fields[id] = r.value(h & 0x0f)
+		last = id
+	}
+}
+
+func parquetRecords() []git.CommitRecord {
+	base := time.Date(2026, 2, 3, 10, 30, 0, 0, time.UTC)
+	var records []git.CommitRecord
+	for i := 0; i < 9; i++ {
+		records = append(records, git.CommitRecord{
+			Repo:        "/src/api",
+			Hash:        fmt.Sprintf("%040x", i+1),
+			Parents:     []string{fmt.Sprintf("%040x", i)},
+			AuthorName:  "Dev Ü",
+			AuthorEmail: "dev@example.com",
+			AuthorDate:  base.Add(time.Duration(i) * time.Hour),
+			CommitDate:  base.Add(time.Duration(i)*time.Hour + time.Minute),
+			Subject:     fmt.Sprintf("change %d, \"quoted\"", i),
+			Added:       10 * i,
+			Deleted:     i,
+			Files:       []git.FileRecord{{Path: "a.go", Added: 10 * i, Deleted: i}, {Path: "logo.png", Binary: true}},
+			Excluded:    i == 8,
+			AIAssisted:  i%3 == 0,
+		})
+	}
+	return records
+}
+
+// parquetRow is what each column of an exported record should hold,
+// timestamps as Unix milliseconds.
+func parquetRow(r git.CommitRecord) map[string]any {
+	files, _ := json.Marshal(commitExportJSON(r).Files)
+	return map[string]any{
+		"repo":           r.Repo,
+		"sha":            r.Hash,
+		"parents":        strings.Join(r.Parents, " "),
+		"author_name":    r.AuthorName,
+		"author_email":   r.AuthorEmail,
+		"author_date":    r.AuthorDate.UnixMilli(),
+		"committer_date": r.CommitDate.UnixMilli(),
+		"subject":        r.Subject,
+		"added":          int64(r.Added),
+		"deleted":        int64(r.Deleted),
+		"files_changed":  int64(len(r.Files)),
+		"files":          string(files),
+		"excluded":       r.Excluded,
+		"ai_assisted":    r.AIAssisted,
+	}
+}
+
+// readParquet checks the file's framing and returns its FileMetaData.
+func readParquet(t *testing.T, data []byte) map[int]any {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatalf("missing PAR1 magic")
+	}
+	n := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	start := len(data) - 8 - n
+	if start < 4 {
+		t.Fatalf("footer length %d overruns the file (%d bytes)", n, len(data))
+	}
+	r := &thriftReader{b: data[start : len(data)-8]}
+	meta := r.structure()
+	if r.pos != n {
+		t.Fatalf("footer decoded %d of %d bytes", r.pos, n)
+	}
+	return meta
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	records := parquetRecords()
+	var buf bytes.Buffer
+	if err := exportParquet(&buf, records); err != nil {
+		t.Fatalf("exportParquet: %v", err)
+	}
+	data := buf.Bytes()
+	meta := readParquet(t, data)
+
+	if meta[1] != int64(1) || meta[3] != int64(len(records)) || meta[6] != "gitrespect" {
+		t.Fatalf("version=%v num_rows=%v created_by=%v", meta[1], meta[3], meta[6])
+	}
+	schema := meta[2].([]any)
+	if len(schema) != len(exportColumns)+1 {
+		t.Fatalf("schema has %d elements, want %d", len(schema), len(exportColumns)+1)
+	}
+	if root := schema[0].(map[int]any); root[4] != "schema" || root[5] != int64(len(exportColumns)) {
+		t.Errorf("root schema element = %v", root)
+	}
+	for i, name := range exportColumns {
+		if el := schema[i+1].(map[int]any); el[4] != name || el[3] != int64(0) {
+			t.Errorf("schema[%d] = %v, want required %s", i+1, el, name)
+		}
+	}
+
+	groups := meta[4].([]any)
+	if len(groups) != 1 {
+		t.Fatalf("%d row groups, want 1", len(groups))
+	}
+	group := groups[0].(map[int]any)
+	chunks := group[1].([]any)
+	if len(chunks) != len(exportColumns) || group[3] != int64(len(records)) {
+		t.Fatalf("row group has %d columns and %v rows", len(chunks), group[3])
+	}
+
+	var total int64
+	columns := make(map[string][]any)
+	for i, chunk := range chunks {
+		cc := chunk.(map[int]any)
+		cm := cc[3].(map[int]any)
+		name := exportColumns[i]
+		if path := cm[3].([]any); len(path) != 1 || path[0] != name {
+			t.Errorf("column %d path = %v, want %s", i, path, name)
+		}
+		if cm[4] != int64(0) || cm[5] != int64(len(records)) {
+			t.Errorf("%s: codec=%v num_values=%v", name, cm[4], cm[5])
+		}
+		offset := cm[9].(int64)
+		if cc[2] != offset {
+			t.Errorf("%s: file_offset %v, data_page_offset %d", name, cc[2], offset)
+		}
+		size := cm[7].(int64)
+		total += size
+
+		r := &thriftReader{b: data[offset : offset+size]}
+		page := r.structure()
+		header := page[5].(map[int]any)
+		if page[1] != int64(0) || header[1] != int64(len(records)) || header[2] != int64(0) {
+			t.Errorf("%s: page header = %v", name, page)
+		}
+		values := data[offset+int64(r.pos):]
+		if n := page[3].(int64); int64(r.pos)+n != size {
+			t.Errorf("%s: page of %d bytes after a %d byte header, chunk is %d", name, n, r.pos, size)
+		}
+		typ := cm[1].(int64)
+		for row := range records {
+			switch typ {
+			case pqByteArray:
+				n := binary.LittleEndian.Uint32(values)
+				columns[name] = append(columns[name], string(values[4:4+n]))
+				values = values[4+n:]
+			case pqInt64:
+				columns[name] = append(columns[name], int64(binary.LittleEndian.Uint64(values)))
+				values = values[8:]
+			case pqBoolean:
+				columns[name] = append(columns[name], values[row/8]&(1<<(row%8)) != 0)
+			}
+		}
+	}
+	if group[2] != total {
+		t.Errorf("total_byte_size = %v, chunks add up to %d", group[2], total)
+	}
+
+	for i, r := range records {
+		want := parquetRow(r)
+		for _, name := range exportColumns {
+			if got := columns[name][i]; got != want[name] {
+				t.Errorf("row %d %s = %v, want %v", i, name, got, want[name])
+			}
+		}
+	}
+}
+
+func TestParquetEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportParquet(&buf, nil); err != nil {
+		t.Fatalf("exportParquet: %v", err)
+	}
+	meta := readParquet(t, buf.Bytes())
+	if meta[3] != int64(0) || len(meta[4].([]any)) != 0 {
+		t.Errorf("num_rows=%v row_groups=%v, want none", meta[3], meta[4])
+	}
+	if len(meta[2].([]any)) != len(exportColumns)+1 {
+		t.Errorf("schema has %d elements, want %d", len(meta[2].([]any)), len(exportColumns)+1)
+	}
+}
+
+// parquetGolden is the export of parquetRecords, checked in so real Parquet
+// readers can vouch for it: TestParquetGolden keeps the writer's output
+// byte for byte equal to it, and TestParquetReaders reads it back with
+// pyarrow or DuckDB. After a deliberate change to the format, rerun both
+// with -update where one of them is installed.
+var parquetGolden = filepath.Join("testdata", "commits.parquet")
+
+func TestParquetGolden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportParquet(&buf, parquetRecords()); err != nil {
+		t.Fatalf("exportParquet: %v", err)
+	}
+	got := buf.Bytes()
+	if *update {
+		if err := os.WriteFile(parquetGolden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(parquetGolden)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		i := 0
+		for i < min(len(got), len(want)) && got[i] == want[i] {
+			i++
+		}
+		t.Errorf("export differs from %s at byte %d (%d bytes, want %d)", parquetGolden, i, len(got), len(want))
+	}
+}
+
+func TestParquetReaders(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+	cmd := exec.Command(python, filepath.Join("testdata", "read_parquet.py"), parquetGolden)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if exit := (*exec.ExitError)(nil); errors.As(err, &exit) && exit.ExitCode() == 3 {
+		t.Skip("neither pyarrow nor duckdb is installed")
+	}
+	if err != nil {
+		t.Fatalf("read_parquet.py: %v\n%s", err, stderr.Bytes())
+	}
+	var read struct {
+		Reader  string
+		Kinds   map[string]string
+		Columns []string
+		Rows    [][]any
+	}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+	if err := dec.Decode(&read); err != nil {
+		t.Fatalf("read_parquet.py: %v\n%s", err, out)
+	}
+	t.Logf("read with %s", read.Reader)
+
+	records := parquetRecords()
+	if strings.Join(read.Columns, ",") != strings.Join(exportColumns, ",") || len(read.Rows) != len(records) {
+		t.Fatalf("%s read columns %v and %d rows, want %v and %d", read.Reader, read.Columns, len(read.Rows), exportColumns, len(records))
+	}
+	for name, v := range parquetRow(records[0]) {
+		var want string
+		switch v.(type) {
+		case string:
+			want = "str"
+		case bool:
+			want = "bool"
+		case int64:
+			want = "int"
+			if strings.HasSuffix(name, "_date") {
+				want = "timestamp"
+			}
+		}
+		if read.Kinds[name] != want {
+			t.Errorf("%s reads %s as %s, want %s", read.Reader, name, read.Kinds[name], want)
+		}
+	}
+	for i, r := range records {
+		want := parquetRow(r)
+		for j, name := range exportColumns {
+			if got := fmt.Sprint(read.Rows[i][j]); got != fmt.Sprint(want[name]) {
+				t.Errorf("%s: row %d %s = %s, want %v", read.Reader, i, name, got, want[name])
+			}
+		}
+	}
+}
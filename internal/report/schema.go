@@ -0,0 +1,203 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON report format, carried by every
+// JSON report as schema_version. Within a major version fields are only
+// added; removing, renaming or retyping a field, or changing its meaning,
+// bumps the major version. New fields bump the minor version.
+const SchemaVersion = "1.1"
+
+// schemaID is where the generated schema is published (docs/report.schema.json).
+const schemaID = "https://raw.githubusercontent.com/juangracia/gitrespect/main/docs/report.schema.json"
+
+// schemaReports are the JSON reports the schema covers, by the name
+// `gitrespect schema` takes.
+var schemaReports = []struct {
+	name string
+	typ  reflect.Type
+}{
+	{"report", reflect.TypeFor[JSONReport]()},
+	{"team", reflect.TypeFor[TeamJSONReport]()},
+	{"compare", reflect.TypeFor[CompareJSONReport]()},
+	{"dora", reflect.TypeFor[DORAJSONReport]()},
+}
+
+// SchemaNames lists the reports Schema accepts.
+func SchemaNames() []string {
+	names := make([]string, len(schemaReports))
+	for i, r := range schemaReports {
+		names[i] = r.name
+	}
+	return names
+}
+
+// schemaDocs describes the schema's types ("Type") and fields
+// ("Type.json_name").
+var schemaDocs = map[string]string{
+	"JSONReport":                            "gitrespect --output json: one author's totals over a period.",
+	"TeamJSONReport":                        "gitrespect --team ... --output json: team totals and one entry per member.",
+	"CompareJSONReport":                     "gitrespect compare --output json: every compared period, in the order requested. The first period is the reference.",
+	"DORAJSONReport":                        "gitrespect dora --output json: the four DORA metrics.",
+	"JSONReport.benchmarks":                 "Deprecated Senior/Avg/Junior comparison, only with --legacy-benchmark.",
+	"JSONReport.metrics":                    "Personal baseline and the opt-in metrics selected by --metrics.",
+	"JSONReport.monthly":                    "Monthly breakdown, with --breakdown monthly.",
+	"JSONReport.repos":                      "Paths of the repositories analyzed. Since 1.1.",
+	"JSONReport.excluded_commits":           "Commits left out by --exclude-commit or gitrespect.excludeCommit.",
+	"JSONReport.outliers":                   "Commits that may distort the totals, biggest first.",
+	"JSONReport.explain":                    "Top commits and files behind a total, month or week, with --explain.",
+	"JSONReport.diagnostics":                "Metrics that failed or lacked data.",
+	"PeriodInfo":                            "A date range; dates are YYYY-MM-DD.",
+	"PeriodInfo.working_days":               "Weekdays in the range, the denominator of every per-day rate.",
+	"SummaryStats":                          "Lines and commits over the period, excluded files and commits left out.",
+	"DailyStats":                            "Lines per working day.",
+	"MetricsPayload":                        "Opt-in metrics; each is present only when selected.",
+	"MetricsPayload.repo":                   "Repository path, on entries of repos only.",
+	"MetricsPayload.window":                 "The baseline window, on normal only.",
+	"MetricsPayload.normal":                 "The same metrics over the baseline window (--baseline-window).",
+	"MetricsPayload.repos":                  "Each repository's metrics, with --per-repo.",
+	"CommitJSON":                            "One commit's contribution to the totals. kinds and reasons are set on outliers only.",
+	"CommitJSON.kinds":                      "Outlier kinds: huge, mass-delete, large-file or generated.",
+	"ExplainJSON.bucket":                    "total, a month (YYYY-MM) or an ISO week (YYYY-Www).",
+	"TeamTotals.per_day":                    "Team net lines per working day.",
+	"MemberStats.per_day":                   "Member's net lines per working day.",
+	"ComparePeriodJSON":                     "One compared period. Fields comparing against the first period are absent on the first period.",
+	"ComparePeriodJSON.multiplier_vs_first": "Net lines/day relative to the first period; 0 when the first period's rate is not positive.",
+	"ComparePeriodJSON.change_description":  "The change in weekly lines/day with its confidence interval and significance, in words. Quotes the ratio the interval surrounds, which can differ slightly from multiplier_vs_first.",
+	"MetricValueJSON":                       "One opt-in metric in one period.",
+	"MetricValueJSON.value":                 "Null when the period had too little data.",
+	"MetricValueJSON.delta_vs_first":        "Null on the first period or when either side has no data.",
+	"CompareRowJSON":                        "A repository (--per-repo) or member (--team) across the compared periods.",
+	"Baseline":                              "The period's net lines/day against the author's or team's own history.",
+	"Baseline.position":                     "above, within or below the p25-p75 band.",
+	"Band":                                  "The same period last year, with --seasonal.",
+	"Diagnostic":                            "How a metric went for a repository, author or period.",
+	"Diagnostic.status":                     "ok, insufficient_data, skipped or error.",
+	"Significance":                          "Bootstrap interval and Mann-Whitney test of weekly lines/day against the first period.",
+	"CommitSizeDistribution":                "Commits bucketed by lines changed, with percentiles.",
+	"DORA":                                  "Deployment frequency, lead time for changes, change failure rate and time to restore.",
+}
+
+// Schema returns the JSON Schema (draft 2020-12) of the JSON reports,
+// generated from their Go types: of the named report, or of any of them
+// when name is empty.
+func Schema(name string) ([]byte, error) {
+	g := schemaGen{defs: make(map[string]any)}
+	doc := map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     schemaID,
+		"title":   "gitrespect JSON reports, schema version " + SchemaVersion,
+	}
+	var refs []any
+	for _, r := range schemaReports {
+		if name == "" || name == r.name {
+			refs = append(refs, g.ref(r.typ))
+		}
+	}
+	switch {
+	case len(refs) == 0:
+		return nil, fmt.Errorf("unknown report %q (use %s)", name, strings.Join(SchemaNames(), ", "))
+	case name == "":
+		doc["description"] = "Any of gitrespect's JSON reports. Within a major schema_version fields are only added, so validators should allow unknown properties."
+		doc["anyOf"] = refs
+	default:
+		doc["$ref"] = refs[0].(map[string]any)["$ref"]
+	}
+	doc["$defs"] = g.defs
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// schemaGen collects the definitions of the struct types a schema refers to.
+type schemaGen struct {
+	defs map[string]any
+}
+
+// ref returns a reference to t's definition, adding it first if needed.
+func (g *schemaGen) ref(t reflect.Type) map[string]any {
+	name := t.Name()
+	ref := map[string]any{"$ref": "#/$defs/" + name}
+	if _, ok := g.defs[name]; ok {
+		return ref
+	}
+	def := map[string]any{"type": "object"}
+	g.defs[name] = def // before the fields, for recursive types
+	if doc, ok := schemaDocs[name]; ok {
+		def["description"] = doc
+	}
+	props := make(map[string]any)
+	var required []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		key, opts, _ := strings.Cut(tag, ",")
+		if key == "" {
+			key = f.Name
+		}
+		omitempty := strings.Contains(opts, "omitempty")
+		prop := g.schema(f.Type, !omitempty)
+		if doc, ok := schemaDocs[name+"."+key]; ok {
+			prop["description"] = doc
+		}
+		if key == "schema_version" {
+			prop["description"] = "Report format version, MAJOR.MINOR. Fields are only added within a major version."
+			prop["pattern"] = `^` + strings.Split(SchemaVersion, ".")[0] + `\.[0-9]+$`
+		}
+		props[key] = prop
+		if !omitempty {
+			required = append(required, key)
+		}
+	}
+	def["properties"] = props
+	if len(required) > 0 {
+		sort.Strings(required)
+		def["required"] = required
+	}
+	return ref
+}
+
+// schema describes a value of type t. Nil pointers, slices and maps
+// encode as null unless the field is omitted when empty.
+func (g *schemaGen) schema(t reflect.Type, nullable bool) map[string]any {
+	var s map[string]any
+	switch t.Kind() {
+	case reflect.Pointer:
+		s = g.schema(t.Elem(), false)
+	case reflect.Slice:
+		s = map[string]any{"type": "array", "items": g.schema(t.Elem(), false)}
+	case reflect.Map:
+		s = map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem(), false)}
+	case reflect.Struct:
+		if t == reflect.TypeFor[time.Time]() {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+		s = g.ref(t)
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		panic("report: no JSON schema for " + t.String())
+	}
+	if !nullable || (t.Kind() != reflect.Pointer && t.Kind() != reflect.Slice && t.Kind() != reflect.Map) {
+		return s
+	}
+	return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+}
@@ -0,0 +1,249 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+const schemaDoc = "../../docs/report.schema.json"
+
+func TestSchemaMatchesDocs(t *testing.T) {
+	data, err := Schema("")
+	if err != nil {
+		t.Fatalf("Schema: %v", err)
+	}
+	want, err := os.ReadFile(schemaDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data)+"\n" != string(want) {
+		t.Errorf("%s is out of date; regenerate it with: go run ./cmd/gitrespect schema -f docs/report.schema.json", schemaDoc)
+	}
+}
+
+// schemaValidator checks a decoded JSON value against the subset of JSON
+// Schema that Schema generates. It is stricter than the schema in one way:
+// an object may only have the properties its definition lists, so a field
+// missing from the schema is caught too.
+type schemaValidator struct {
+	defs map[string]any
+	errs []string
+}
+
+func (v *schemaValidator) errorf(path, format string, args ...any) {
+	v.errs = append(v.errs, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *schemaValidator) validate(path string, value any, schema map[string]any) {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, ok := v.defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		if !ok {
+			v.errorf(path, "unknown $ref %s", ref)
+			return
+		}
+		v.validate(path, value, def)
+		return
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		for _, alt := range anyOf {
+			sub := schemaValidator{defs: v.defs}
+			sub.validate(path, value, alt.(map[string]any))
+			if len(sub.errs) == 0 {
+				return
+			}
+		}
+		v.errorf(path, "matches none of anyOf")
+		return
+	}
+	switch typ := schema["type"]; typ {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			v.errorf(path, "want an object, got %T", value)
+			return
+		}
+		props, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, key := range required {
+			if _, ok := obj[key.(string)]; !ok {
+				v.errorf(path, "missing required %s", key)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if extra, ok := schema["additionalProperties"].(map[string]any); ok {
+				v.validate(path+"."+k, obj[k], extra)
+			} else if prop, ok := props[k].(map[string]any); ok {
+				v.validate(path+"."+k, obj[k], prop)
+			} else {
+				v.errorf(path, "property %s is not in the schema", k)
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			v.errorf(path, "want an array, got %T", value)
+			return
+		}
+		for i, item := range arr {
+			v.validate(fmt.Sprintf("%s[%d]", path, i), item, schema["items"].(map[string]any))
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			v.errorf(path, "want a string, got %T", value)
+			return
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			v.errorf(path, "%q does not match %s", s, pattern)
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				v.errorf(path, "%q is not a date-time", s)
+			}
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			v.errorf(path, "want an integer, got %v", value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			v.errorf(path, "want a number, got %T", value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.errorf(path, "want a boolean, got %T", value)
+		}
+	case "null":
+		if value != nil {
+			v.errorf(path, "want null, got %v", value)
+		}
+	default:
+		v.errorf(path, "unexpected schema type %v", typ)
+	}
+}
+
+// validateReport validates data against the published schema's definition
+// of the named report type.
+func validateReport(t *testing.T, data []byte, def string) {
+	t.Helper()
+	raw, err := os.ReadFile(schemaDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	v := schemaValidator{defs: doc["$defs"].(map[string]any)}
+	v.validate("$", value, map[string]any{"$ref": "#/$defs/" + def})
+	for _, e := range v.errs {
+		t.Error(e)
+	}
+}
+
+// fullBundle has every opt-in metric, per-repo values, a baseline with a
+// seasonal band, outliers, an explanation and diagnostics.
+func fullBundle(since, until time.Time) metrics.Bundle {
+	change := git.CommitChange{Repo: "/src/api", Hash: strings.Repeat("a", 40), Date: since.Add(24 * time.Hour), Subject: "Add API", Added: 900, Deleted: 3,
+		Files: []git.FileChange{{Path: "api.go", Added: 900, Deleted: 3}}}
+	opt := metrics.Bundle{
+		CommitSize: &metrics.CommitSizeDistribution{Edges: []int{10, 100, 500}, Counts: []int{1, 2, 0, 1}, Total: 4, P50: 40, P90: 900, P99: 900,
+			Largest: []metrics.SizedCommit{{Hash: change.Hash, Subject: change.Subject, Added: 900, Deleted: 3}}},
+		Cadence:  &metrics.Cadence{MedianDaysBetween: 1.5, Samples: 3, MainBranch: "main"},
+		LeadTime: &metrics.LeadTime{MedianDays: 2, Samples: 1, MainBranch: "main", Unmatched: 1, Changes: []metrics.LeadTimeSample{{Commit: change.Hash, Days: 2, Method: metrics.LeadMethodMerge}}},
+		Deploy:   &metrics.DeployLeadTime{Source: "tags v*", MedianDays: 1, Samples: 4, Deploys: 2, Weeks: 2, PerWeek: 1},
+		Churn:    &metrics.Churn{WindowDays: 30, AddedLines: 900, ChurnedLines: 90, Ratio: 0.1},
+		Reverts:  &metrics.Reverts{Commits: 4, Reverted: 1, RevertRate: 0.25, MedianDaysToRevert: 3, WindowDays: 30},
+	}
+	b := opt
+	b.Selection = metrics.Selection{CommitSize: true, Cadence: true, LeadTime: true, Deploy: true, Churn: true, Reverts: true}
+	b.Since, b.Until = since, until
+	b.Baseline = &metrics.Baseline{WindowStart: since.AddDate(0, -3, 0), WindowEnd: since, WorkingDays: 60, Weeks: 12, LOCPerDay: 80, P25: 50, P75: 120,
+		PeriodLOCPerDay: 90, PercentDelta: 12.5, Position: "within",
+		Seasonal: &metrics.Band{WindowStart: since.AddDate(-1, 0, 0), WindowEnd: until.AddDate(-1, 0, 0), Weeks: 2, Median: 70, P25: 60, P75: 85, Position: "above"}}
+	normal := opt
+	normal.Since, normal.Until = b.Baseline.WindowStart, b.Baseline.WindowEnd
+	b.Normal = &normal
+	b.Repos = []metrics.RepoBundle{{Path: "/src/api", Bundle: opt}}
+	b.Outliers = []git.Outlier{{Commit: change, Kinds: []string{"huge"}, Reasons: []string{"10× the usual size"}}}
+	b.Explain = &git.Explanation{Bucket: "2026-03", Added: 900, Deleted: 3, Net: 897, Commits: 1, Top: []git.CommitChange{change},
+		Files: []git.FileContribution{{Repo: "/src/api", Path: "api.go", Added: 900, Deleted: 3, Commits: 1}}}
+	b.Diagnostics = []metrics.Diagnostic{
+		{Metric: metrics.MetricCadence, Repo: "/src/api", Status: metrics.StatusOK},
+		{Metric: metrics.MetricLeadTime, Repo: "/src/web", Status: metrics.StatusError, Reason: "git log: exit status 128"},
+	}
+	return b
+}
+
+func TestJSONMatchesSchema(t *testing.T) {
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 3, 31, 23, 59, 59, 0, time.UTC)
+	stats := git.RepoStats{
+		Path: "/src/api", Author: "dev@example.com", Since: since, Until: until,
+		Added: 1200, Deleted: 200, Net: 1000, Commits: 4, FilesChanged: 7,
+		FirstCommit: since.Add(24 * time.Hour), LastCommit: until.Add(-24 * time.Hour),
+		Monthly:  map[string]git.MonthStats{"2026-03": {Year: 2026, Month: 3, Added: 1200, Deleted: 200, Net: 1000, Commits: 4}},
+		Excluded: []string{strings.Repeat("b", 40)},
+	}
+	data := savedJSON(t, func(filename string) error {
+		return JSON(stats, filename, "monthly", fullBundle(since, until))
+	})
+	validateReport(t, data, "JSONReport")
+
+	// A bare report, where empty slices and nil pointers come out as null or
+	// are left out.
+	data = savedJSON(t, func(filename string) error {
+		return JSON(git.RepoStats{Since: since, Until: until}, filename, "", metrics.Bundle{})
+	})
+	validateReport(t, data, "JSONReport")
+}
+
+func TestTeamJSONMatchesSchema(t *testing.T) {
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 3, 31, 23, 59, 59, 0, time.UTC)
+	member := git.RepoStats{Author: "a@x.com", Since: since, Until: until, Added: 100, Deleted: 10, Net: 90, Commits: 2}
+	stats := git.TeamStats{
+		Since: since, Until: until,
+		Members:    map[string]git.RepoStats{"a@x.com": member},
+		TotalAdded: 100, TotalDeleted: 10, TotalNet: 90, TotalCommits: 2,
+		Monthly: map[string]git.MonthStats{"2026-03": {Year: 2026, Month: 3, Added: 100, Deleted: 10, Net: 90, Commits: 2}},
+	}
+	team := fullBundle(since, until)
+	data := savedJSON(t, func(filename string) error {
+		return TeamJSON(stats, filename, "monthly", team, map[string]metrics.Bundle{"a@x.com": team})
+	})
+	validateReport(t, data, "TeamJSONReport")
+}
+
+// savedJSON returns the report write saves to a temporary file.
+func savedJSON(t *testing.T, write func(filename string) error) []byte {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "report.json")
+	if err := write(filename); err != nil {
+		t.Fatalf("writing %s: %v", filepath.Base(filename), err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
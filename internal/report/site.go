@@ -0,0 +1,99 @@
+package report
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// siteAssets are the stylesheet and script every page of a site shares.
+//
+//go:embed assets/*
+var siteAssets embed.FS
+
+// SitePage places a page in a gitrespect site: the shared assets, and a
+// navigation bar with the search box and links to related pages.
+type SitePage struct {
+	Title   string     // the site's, linking to its index
+	Section string     // what the page shows, e.g. "Team platform"
+	Root    string     // the path from the page to the site's root: "" or "../"
+	Links   []SiteLink // related pages
+}
+
+// SiteLink is a link of the navigation bar. Href is relative to the site's
+// root.
+type SiteLink struct {
+	Label   string
+	Href    string
+	Current bool
+}
+
+// SiteHTMLData feeds the site.html template, a site's index.
+type SiteHTMLData struct {
+	Title   string
+	Since   string
+	Until   string
+	Teams   []SiteEntryHTMLData
+	Members []SiteEntryHTMLData
+	Repos   []SiteEntryHTMLData
+	HTMLPage
+}
+
+// SiteEntryHTMLData is one team, member or repository of a site's index.
+type SiteEntryHTMLData struct {
+	Name        string
+	Href        string
+	Note        string // the team's members, or the member's teams
+	Commits     int
+	Net         int
+	PerDay      float64
+	CompareHref string // the team's comparison, if any
+}
+
+// SiteSearchEntry is a page the search box finds. Href is relative to the
+// site's root.
+type SiteSearchEntry struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"` // team, member, repository or comparison
+	Href string `json:"href"`
+}
+
+// SiteIndex writes the site's index.html into dir.
+func SiteIndex(dir string, style HTMLStyle, data SiteHTMLData) error {
+	page, err := style.page()
+	if err != nil {
+		return err
+	}
+	data.HTMLPage = page
+	return style.write("site.html", filepath.Join(dir, "index.html"), data)
+}
+
+// SiteAssets writes the shared assets into dir/assets: the stylesheet, the
+// script, and the search index as a script, which unlike JSON loads from
+// the filesystem too.
+func SiteAssets(dir string, search []SiteSearchEntry) error {
+	out := filepath.Join(dir, "assets")
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", out, err)
+	}
+	for _, name := range []string{"site.css", "site.js"} {
+		data, err := siteAssets.ReadFile("assets/" + name)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(out, name), data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	index, err := json.Marshal(search)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+	script := "window.gitrespectSearch = " + string(index) + ";\n"
+	if err := os.WriteFile(filepath.Join(out, "search.js"), []byte(script), 0644); err != nil {
+		return fmt.Errorf("failed to write search.js: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// templateFS holds the built-in HTML templates: a page per report
+// (report.html, team.html, compare.html, dora.html) and a site's index
+// (site.html), and the partials they share (theme.html, interactive.html,
+// nav.html).
+//
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// HTMLStyle is how the HTML reports look.
+type HTMLStyle struct {
+	Theme       string    // "dark" (the default) or "light"
+	ThemeFile   string    // CSS overriding the theme's variables, e.g. :root { --accent: #e4007c; }
+	TemplateDir string    // *.html files replacing the built-in templates of the same name
+	Site        *SitePage // set for the pages of gitrespect site
+}
+
+// HTMLPage is the part of every page's data the shared templates draw:
+// "theme" its colors, and "site-head" and "site-nav" its place in a site.
+type HTMLPage struct {
+	Theme    string
+	IsDark   bool
+	ThemeCSS template.CSS // the --theme-file, inlined after the built-in colors
+	Site     *SitePage    // nil outside a site
+}
+
+func (s HTMLStyle) page() (HTMLPage, error) {
+	p := HTMLPage{Theme: s.Theme, IsDark: s.Theme != "light", Site: s.Site}
+	if s.ThemeFile == "" {
+		return p, nil
+	}
+	css, err := os.ReadFile(s.ThemeFile)
+	if err != nil {
+		return p, fmt.Errorf("failed to read theme file: %w", err)
+	}
+	// The file is inlined in the page's <style>, which it must not close.
+	if strings.Contains(strings.ToLower(string(css)), "</style") {
+		return p, fmt.Errorf("theme file %s must be plain CSS", s.ThemeFile)
+	}
+	p.ThemeCSS = template.CSS(css)
+	return p, nil
+}
+
+// templates parses the built-in templates, then the user's over them, so a
+// template directory only needs the files it changes.
+func (s HTMLStyle) templates() (*template.Template, error) {
+	tmpl, err := template.ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	if s.TemplateDir == "" {
+		return tmpl, nil
+	}
+	pattern := filepath.Join(s.TemplateDir, "*.html")
+	if matches, _ := filepath.Glob(pattern); len(matches) == 0 {
+		return nil, fmt.Errorf("no *.html templates in %s", s.TemplateDir)
+	}
+	if tmpl, err = tmpl.ParseGlob(pattern); err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// write executes the page template (e.g. "report.html") with data into
+// filename. Nothing is written if the template fails.
+func (s HTMLStyle) write(page, filename string, data any) error {
+	tmpl, err := s.templates()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, page, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	if err := os.WriteFile(filename, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	// A site reports once, when every page is written.
+	if s.Site == nil {
+		fmt.Printf("✓ Report saved to %s\n", filename)
+	}
+	return nil
+}
+
+// WriteTemplates copies the built-in templates into dir, creating it, for
+// customizing and passing back with --template. It overwrites nothing: if
+// any of the files exists, none is written.
+func WriteTemplates(dir string) ([]string, error) {
+	entries, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("%s already exists", path)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for i, e := range entries {
+		data, err := templateFS.ReadFile("templates/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(paths[i], data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", paths[i], err)
+		}
+	}
+	return paths, nil
+}
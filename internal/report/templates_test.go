@@ -0,0 +1,158 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// renderHTML writes a small report with style and returns the page.
+func renderHTML(t *testing.T, style HTMLStyle) (string, error) {
+	t.Helper()
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	stats := git.RepoStats{Author: "dev@example.com", Since: since, Until: since.AddDate(0, 0, 27), Added: 120, Net: 100, Commits: 3}
+	out := filepath.Join(t.TempDir(), "report.html")
+	if err := HTML(stats, out, "", style, metrics.Bundle{}); err != nil {
+		if _, statErr := os.Stat(out); statErr == nil {
+			t.Errorf("a failed render left %s behind", out)
+		}
+		return "", err
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data), nil
+}
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTemplateDirOverlay(t *testing.T) {
+	builtin, err := renderHTML(t, HTMLStyle{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A partial replaces just its part of the built-in page.
+	partial := t.TempDir()
+	writeTemplate(t, partial, "theme.html", `{{define "theme"}}:root { --accent: #e4007c; }{{end}}`)
+	page, err := renderHTML(t, HTMLStyle{TemplateDir: partial})
+	if err != nil {
+		t.Fatalf("theme.html overlay: %v", err)
+	}
+	if !strings.Contains(page, "--accent: #e4007c;") || strings.Contains(page, "--bg-primary: #0d1117") {
+		t.Error("theme.html overlay didn't replace the built-in colors")
+	}
+	if !strings.Contains(page, "dev@example.com") || !strings.Contains(builtin, "--bg-primary: #0d1117") {
+		t.Error("theme.html overlay lost the rest of the page")
+	}
+
+	// A page replaces the whole report, and still sees its data.
+	whole := t.TempDir()
+	writeTemplate(t, whole, "report.html", `<p>{{.Author}} wrote {{.Net}}</p>`)
+	if page, err = renderHTML(t, HTMLStyle{TemplateDir: whole}); err != nil {
+		t.Fatalf("report.html overlay: %v", err)
+	}
+	if page != "<p>dev@example.com wrote 100</p>" {
+		t.Errorf("report.html overlay rendered %q", page)
+	}
+
+	// Broken or missing templates fail before anything is written.
+	broken := t.TempDir()
+	writeTemplate(t, broken, "report.html", `<p>{{.Author</p>`)
+	if _, err := renderHTML(t, HTMLStyle{TemplateDir: broken}); err == nil {
+		t.Error("rendered with a template that doesn't parse")
+	}
+	if _, err := renderHTML(t, HTMLStyle{TemplateDir: t.TempDir()}); err == nil || !strings.Contains(err.Error(), "no *.html templates") {
+		t.Errorf("empty template dir: err = %v", err)
+	}
+	unknown := t.TempDir()
+	writeTemplate(t, unknown, "report.html", `<p>{{.NoSuchField}}</p>`)
+	if _, err := renderHTML(t, HTMLStyle{TemplateDir: unknown}); err == nil {
+		t.Error("rendered a template reading a field the page doesn't have")
+	}
+}
+
+func TestThemeFile(t *testing.T) {
+	dir := t.TempDir()
+	css := filepath.Join(dir, "brand.css")
+	writeTemplate(t, dir, "brand.css", ":root { --accent: #e4007c; }\n")
+	page, err := renderHTML(t, HTMLStyle{ThemeFile: css})
+	if err != nil {
+		t.Fatalf("theme file: %v", err)
+	}
+	if !strings.Contains(page, ":root { --accent: #e4007c; }") {
+		t.Error("theme file wasn't inlined")
+	}
+
+	// The file lands inside <style>; closing it would let it inject markup.
+	for _, evil := range []string{
+		"</style><script>alert(1)</script>",
+		":root {} </STYLE ><img src=x onerror=alert(1)>",
+	} {
+		writeTemplate(t, dir, "brand.css", evil)
+		if _, err := renderHTML(t, HTMLStyle{ThemeFile: css}); err == nil || !strings.Contains(err.Error(), "must be plain CSS") {
+			t.Errorf("%q: err = %v, want it rejected", evil, err)
+		}
+	}
+
+	if _, err := renderHTML(t, HTMLStyle{ThemeFile: filepath.Join(dir, "missing.css")}); err == nil {
+		t.Error("rendered with a missing theme file")
+	}
+}
+
+func TestWriteTemplates(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my", "templates")
+	paths, err := WriteTemplates(dir)
+	if err != nil {
+		t.Fatalf("WriteTemplates: %v", err)
+	}
+	entries, _ := templateFS.ReadDir("templates")
+	if len(paths) != len(entries) || len(paths) == 0 {
+		t.Fatalf("wrote %d templates, want all %d", len(paths), len(entries))
+	}
+
+	// The copies render the same page as the built-in templates.
+	builtin, err := renderHTML(t, HTMLStyle{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied, err := renderHTML(t, HTMLStyle{TemplateDir: dir})
+	if err != nil {
+		t.Fatalf("rendering with the copies: %v", err)
+	}
+	if copied != builtin {
+		t.Error("the copied templates render differently from the built-in ones")
+	}
+
+	// An edited copy is never overwritten, and nothing else is written.
+	writeTemplate(t, dir, "theme.html", "edited")
+	if _, err := WriteTemplates(dir); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second WriteTemplates: err = %v, want it to refuse", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "theme.html")); string(data) != "edited" {
+		t.Error("WriteTemplates overwrote an edited template")
+	}
+
+	partial := t.TempDir()
+	writeTemplate(t, partial, "nav.html", "mine")
+	if _, err := WriteTemplates(partial); err == nil {
+		t.Error("WriteTemplates wrote into a directory with one of its files")
+	}
+	if files, _ := os.ReadDir(partial); len(files) != 1 {
+		t.Errorf("WriteTemplates left %d files, want only nav.html", len(files))
+	}
+	if data, _ := os.ReadFile(filepath.Join(partial, "nav.html")); string(data) != "mine" {
+		t.Error("WriteTemplates overwrote nav.html")
+	}
+}
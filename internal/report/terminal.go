@@ -61,49 +61,25 @@ func Terminal(stats git.RepoStats, breakdown string, bundle metrics.Bundle) erro
 		fmt.Printf("  %sDaily avg:%s %.0f lines/day (%d working days)\n",
 			colorDim, colorReset, locPerDay, workingDays)
 	}
+	if n := len(stats.Excluded); n > 0 {
+		fmt.Printf("  %sExcluded:%s  %d %s (--exclude-commit, %s)\n", colorDim, colorReset, n, plural(n, "commit"), git.ExcludeCommitConfigKey)
+	}
 	fmt.Println()
 
+	renderOutliers(bundle.Outliers)
+	renderExplain(bundle.Explain)
+
 	// Baseline comparison (default) or legacy Senior/Avg/Junior (opt-in)
 	if bundle.LegacyBenchmark {
-		if workingDays >= 21 {
-			comparisons := benchmark.Compare(locPerDay)
-			fmt.Printf("  %svs Industry:%s\n", colorDim, colorReset)
-			for i, c := range comparisons {
-				prefix := "├──"
-				if i == len(comparisons)-1 {
-					prefix = "└──"
-				}
-				bar := renderBar(c.Multiplier, 20)
-				fmt.Printf("  %s %s (%d/day): %s%.1fx%s %s\n",
-					prefix, c.Label, c.Benchmark, colorYellow, c.Multiplier, colorReset, bar)
-			}
-		} else {
-			fmt.Printf("  %sPace:%s %.0f lines/day\n", colorDim, colorReset, locPerDay)
-			fmt.Printf("  %s(Industry comparison requires 30+ days of activity)%s\n", colorDim, colorReset)
-		}
+		renderLegacyBenchmark("vs Industry", locPerDay, workingDays)
 	} else if bundle.Baseline != nil {
-		b := bundle.Baseline
-		windowDays := int(b.WindowEnd.Sub(b.WindowStart).Hours() / 24)
-		fmt.Printf("  %sBaseline (%dd prior):%s\n", colorDim, windowDays, colorReset)
-		if b.InsufficientHistory {
-			fmt.Printf("  └── %sinsufficient prior history%s\n", colorDim, colorReset)
-		} else {
-			sign := "+"
-			arrow := "↑"
-			color := colorGreen
-			if b.PercentDelta < 0 {
-				sign = ""
-				arrow = "↓"
-				color = colorYellow
-			}
-			fmt.Printf("  └── Your normal: %.0f lines/day → this period: %.0f (%s%s%.0f%%%s %s)\n",
-				b.LOCPerDay, b.PeriodLOCPerDay, color, sign, b.PercentDelta, colorReset, arrow)
-		}
+		renderBaseline("Baseline", "Your", bundle.Baseline)
 	}
 	fmt.Println()
 
 	// Opt-in metrics
 	renderMetrics(bundle)
+	renderDiagnostics(bundle.Diagnostics)
 
 	// Monthly breakdown if requested
 	if breakdown == "monthly" && len(stats.Monthly) > 0 {
@@ -113,27 +89,159 @@ func Terminal(stats git.RepoStats, breakdown string, bundle metrics.Bundle) erro
 	return nil
 }
 
+// maxOutliers is how many outlier commits the terminal lists.
+const maxOutliers = 5
+
+// renderOutliers warns about the commits that distort the totals.
+func renderOutliers(outliers []git.Outlier) {
+	if len(outliers) == 0 {
+		return
+	}
+	fmt.Printf("  %s⚠ Outlier commits (%d):%s %smay distort the totals%s\n", colorYellow, len(outliers), colorReset, colorDim, colorReset)
+	shown := outliers[:min(len(outliers), maxOutliers)]
+	for i, o := range shown {
+		last := i == len(shown)-1 && len(outliers) == len(shown)
+		fmt.Printf("  %s %s\n", treeBranch(last), formatChange(o.Commit))
+		indent := "│  "
+		if last {
+			indent = "   "
+		}
+		fmt.Printf("  %s %s%s%s\n", indent, colorDim, strings.Join(o.Reasons, "; "), colorReset)
+	}
+	if more := len(outliers) - len(shown); more > 0 {
+		fmt.Printf("  └── %s… and %d more (see --output json)%s\n", colorDim, more, colorReset)
+	}
+	fmt.Printf("  %sLeave one out with --exclude-commit %s or git config --add %s %s%s\n",
+		colorDim, shortHash(outliers[0].Commit.Hash), git.ExcludeCommitConfigKey, shortHash(outliers[0].Commit.Hash), colorReset)
+	fmt.Println()
+}
+
+// renderExplain lists the commits and files behind an --explain bucket.
+func renderExplain(ex *git.Explanation) {
+	if ex == nil {
+		return
+	}
+	fmt.Printf("  %sExplain %s:%s %s+%s%s -%s, net %s over %d %s\n", colorBold, explainTitle(ex.Bucket), colorReset,
+		colorGreen, formatNumber(ex.Added), colorReset, formatNumber(ex.Deleted), formatNumber(ex.Net), ex.Commits, plural(ex.Commits, "commit"))
+	if ex.Commits == 0 {
+		fmt.Printf("  └── %sno commits in this bucket%s\n\n", colorDim, colorReset)
+		return
+	}
+	changed := ex.Added + ex.Deleted
+	fmt.Printf("  %sTop commits:%s\n", colorDim, colorReset)
+	for i, c := range ex.Top {
+		fmt.Printf("  %s %s  %s%s%s\n", treeBranch(i == len(ex.Top)-1), formatChange(c), colorDim, sharePct(c.Lines(), changed), colorReset)
+	}
+	fmt.Printf("  %sTop files:%s\n", colorDim, colorReset)
+	for i, f := range ex.Files {
+		fmt.Printf("  %s %s  %s+%s%s -%s  %s%d %s, %s%s\n", treeBranch(i == len(ex.Files)-1), truncate(f.Path, 50),
+			colorGreen, formatNumber(f.Added), colorReset, formatNumber(f.Deleted),
+			colorDim, f.Commits, plural(f.Commits, "commit"), sharePct(f.Lines(), changed), colorReset)
+	}
+	fmt.Println()
+}
+
+// formatChange renders a commit as "3f2a9c1 Mar 3  +1,200 -30  subject".
+func formatChange(c git.CommitChange) string {
+	return fmt.Sprintf("%s%s%s %s  %s+%s%s -%s  %s", colorDim, shortHash(c.Hash), colorReset, c.Date.Format("Jan 2"),
+		colorGreen, formatNumber(c.Added), colorReset, formatNumber(c.Deleted), truncate(c.Subject, 50))
+}
+
+// renderLegacyBenchmark prints the deprecated Senior/Avg/Junior comparison.
+func renderLegacyBenchmark(title string, locPerDay float64, workingDays int) {
+	if workingDays < 21 {
+		fmt.Printf("  %sPace:%s %.0f lines/day\n", colorDim, colorReset, locPerDay)
+		fmt.Printf("  %s(Industry comparison requires 30+ days of activity)%s\n", colorDim, colorReset)
+		return
+	}
+	comparisons := benchmark.Compare(locPerDay)
+	fmt.Printf("  %s%s:%s\n", colorDim, title, colorReset)
+	for i, c := range comparisons {
+		prefix := "├──"
+		if i == len(comparisons)-1 {
+			prefix = "└──"
+		}
+		bar := renderBar(c.Multiplier, 20)
+		fmt.Printf("  %s %s (%d/day): %s%.1fx%s %s\n",
+			prefix, c.Label, c.Benchmark, colorYellow, c.Multiplier, colorReset, bar)
+	}
+}
+
+// renderBaseline prints a baseline and, when computed, its seasonal band.
+// whose is "Your" or "Team".
+func renderBaseline(title, whose string, b *metrics.Baseline) {
+	normalRange := strings.ToLower(whose) + " normal range"
+	windowDays := int(b.WindowEnd.Sub(b.WindowStart).Hours() / 24)
+	fmt.Printf("  %s%s (%dd prior):%s\n", colorDim, title, windowDays, colorReset)
+	last := b.Seasonal == nil
+	if b.InsufficientHistory {
+		fmt.Printf("  %s %sinsufficient prior history%s\n", treeBranch(last), colorDim, colorReset)
+	} else {
+		fmt.Printf("  %s %s normal: %.0f lines/day (typical week %.0f–%.0f) → this period: %.0f %s\n",
+			treeBranch(last), whose, b.LOCPerDay, b.P25, b.P75, b.PeriodLOCPerDay, formatPosition(b.Position, normalRange))
+	}
+	if s := b.Seasonal; s != nil {
+		if s.Insufficient {
+			fmt.Printf("  └── Same period last year: %sinsufficient history%s\n", colorDim, colorReset)
+		} else {
+			fmt.Printf("  └── Same period last year: %.0f lines/day (typical week %.0f–%.0f) %s\n",
+				s.Median, s.P25, s.P75, formatPosition(s.Position, "last year's range"))
+		}
+	}
+}
+
+// formatBaselineCell summarizes a member's baseline for the team table, e.g.
+// "84 → above ↑".
+func formatBaselineCell(b *metrics.Baseline) string {
+	if b == nil || b.InsufficientHistory {
+		return colorDim + "no history" + colorReset
+	}
+	switch b.Position {
+	case metrics.PositionAbove:
+		return fmt.Sprintf("%.0f %s↑ above%s", b.LOCPerDay, colorGreen, colorReset)
+	case metrics.PositionBelow:
+		return fmt.Sprintf("%.0f %s↓ below%s", b.LOCPerDay, colorYellow, colorReset)
+	default:
+		return fmt.Sprintf("%.0f %s= within%s", b.LOCPerDay, colorCyan, colorReset)
+	}
+}
+
+func treeBranch(last bool) string {
+	if last {
+		return "└──"
+	}
+	return "├──"
+}
+
+// formatPosition describes where the period falls relative to a baseline band.
+func formatPosition(position, band string) string {
+	switch position {
+	case metrics.PositionAbove:
+		return fmt.Sprintf("(%sabove %s ↑%s)", colorGreen, band, colorReset)
+	case metrics.PositionBelow:
+		return fmt.Sprintf("(%sbelow %s ↓%s)", colorYellow, band, colorReset)
+	default:
+		return fmt.Sprintf("(%swithin %s%s)", colorCyan, band, colorReset)
+	}
+}
+
 func renderMetrics(b metrics.Bundle) {
 	if b.CommitSize != nil {
 		d := b.CommitSize
 		fmt.Printf("  %sCommit size distribution:%s\n", colorDim, colorReset)
-		rows := []struct {
-			label  string
-			bucket metrics.SizeBucket
-		}{
-			{"Micro (<10)", metrics.BucketMicro},
-			{"Small (10-99)", metrics.BucketSmall},
-			{"Medium (100-499)", metrics.BucketMedium},
-			{"Large (500+)", metrics.BucketLarge},
-		}
-		for i, row := range rows {
-			prefix := "├──"
-			if i == len(rows)-1 {
-				prefix = "└──"
-			}
-			pct := d.Percent(row.bucket)
+		for i := range d.Counts {
+			pct := d.Percent(i)
 			bar := renderBar(pct/10, 20)
-			fmt.Printf("  %s %-18s %3.0f%%  %s\n", prefix, row.label+":", pct, bar)
+			fmt.Printf("  ├── %-18s %3.0f%%  %s%s\n", sizeBucketName(d, i)+":", pct, bar, renderNormal(b, sizeBucketKey(d, i)))
+		}
+		fmt.Printf("  ├── p50 %d · p90 %d · p99 %d lines changed%s\n", d.P50, d.P90, d.P99, renderNormal(b, keyCommitP50))
+		fmt.Printf("  %s Added p50 %d · p90 %d, deleted p50 %d · p90 %d\n", treeBranch(len(d.Largest) == 0), d.Added.P50, d.Added.P90, d.Deleted.P50, d.Deleted.P90)
+		if len(d.Largest) > 0 {
+			fmt.Printf("  └── Largest:\n")
+			for _, c := range d.Largest {
+				fmt.Printf("      %s%s%s %s+%d%s %s-%d%s  %s\n", colorDim, shortHash(c.Hash), colorReset,
+					colorGreen, c.Added, colorReset, colorYellow, c.Deleted, colorReset, truncate(c.Subject, 60))
+			}
 		}
 		fmt.Println()
 	}
@@ -142,11 +250,11 @@ func renderMetrics(b metrics.Bundle) {
 		fmt.Printf("  %sIntegration cadence:%s\n", colorDim, colorReset)
 		switch {
 		case c.MainBranch == "":
-			fmt.Printf("  └── %sno main branch detected%s\n", colorDim, colorReset)
+			fmt.Printf("  └── %sno main branch found (see --main-branch)%s\n", colorDim, colorReset)
 		case c.Samples < 1:
-			fmt.Printf("  └── %sinsufficient data (need 2+ commits on %s)%s\n", colorDim, c.MainBranch, colorReset)
+			fmt.Printf("  └── %sinsufficient data (need 2+ commits on %s)%s%s\n", colorDim, c.MainBranch, colorReset, renderNormal(b, keyCadence))
 		default:
-			fmt.Printf("  └── Median %.1f days between commits to %s\n", c.MedianDaysBetween, c.MainBranch)
+			fmt.Printf("  └── Median %.1f days between commits to %s%s\n", c.MedianDaysBetween, c.MainBranch, renderNormal(b, keyCadence))
 		}
 		fmt.Println()
 	}
@@ -155,11 +263,22 @@ func renderMetrics(b metrics.Bundle) {
 		fmt.Printf("  %sLead time (branch → main):%s\n", colorDim, colorReset)
 		switch {
 		case lt.MainBranch == "":
-			fmt.Printf("  └── %sno main branch detected%s\n", colorDim, colorReset)
+			fmt.Printf("  └── %sno main branch found (see --main-branch)%s\n", colorDim, colorReset)
 		case lt.Samples == 0:
-			fmt.Printf("  └── %sno merges in period%s\n", colorDim, colorReset)
+			fmt.Printf("  └── %sno changes into %s with a detectable branch in period%s%s\n", colorDim, lt.MainBranch, colorReset, renderNormal(b, keyLeadTime))
 		default:
-			fmt.Printf("  └── Median %.1f days (%d merges analyzed)\n", lt.MedianDays, lt.Samples)
+			fmt.Printf("  └── Median %.1f days (%d changes into %s: %s)%s\n", lt.MedianDays, lt.Samples, lt.MainBranch, leadMethods(lt), renderNormal(b, keyLeadTime))
+		}
+		fmt.Println()
+	}
+	if b.Deploy != nil {
+		d := b.Deploy
+		fmt.Printf("  %sLead time to deploy (%s):%s\n", colorDim, d.Source, colorReset)
+		if d.Samples == 0 {
+			fmt.Printf("  └── %sno deploys shipping these commits in period%s%s\n", colorDim, colorReset, renderNormal(b, keyDeployLead))
+		} else {
+			fmt.Printf("  ├── Median %.1f days from commit to deploy (%d commits)%s\n", d.MedianDays, d.Samples, renderNormal(b, keyDeployLead))
+			fmt.Printf("  └── %d deploys, %.2f/week%s\n", d.Deploys, d.PerWeek, renderNormal(b, keyDeployFreq))
 		}
 		fmt.Println()
 	}
@@ -167,12 +286,80 @@ func renderMetrics(b metrics.Bundle) {
 		c := b.Churn
 		fmt.Printf("  %sChurn rate:%s\n", colorDim, colorReset)
 		if c.AddedLines == 0 {
-			fmt.Printf("  └── %sno added lines to analyze%s\n", colorDim, colorReset)
+			fmt.Printf("  └── %sno added lines to analyze%s%s\n", colorDim, colorReset, renderNormal(b, keyChurn))
+		} else {
+			fmt.Printf("  └── %.0f%% of added lines rewritten within %d days%s\n", c.Ratio*100, c.WindowDays, renderNormal(b, keyChurn))
+		}
+		fmt.Println()
+	}
+	if b.Reverts != nil {
+		r := b.Reverts
+		fmt.Printf("  %sReverts & fixes:%s\n", colorDim, colorReset)
+		if r.Commits == 0 {
+			fmt.Printf("  └── %sno commits in period%s%s\n", colorDim, colorReset, renderNormal(b, keyRevertRate))
 		} else {
-			fmt.Printf("  └── %.0f%% of added lines rewritten within %d days\n", c.Ratio*100, c.WindowDays)
+			fmt.Printf("  ├── %d of %d commits reverted (%.0f%%)%s%s\n", r.Reverted, r.Commits, r.RevertRate*100, afterDays(r.Reverted, r.MedianDaysToRevert), renderNormal(b, keyRevertRate))
+			fmt.Printf("  └── %d fixed within %d days (%.0f%%)%s%s\n", r.Fixed, r.WindowDays, r.FixRate*100, afterDays(r.Fixed, r.MedianDaysToFix), renderNormal(b, keyFixRate))
 		}
 		fmt.Println()
 	}
+	renderRepoMetrics(b)
+}
+
+// afterDays describes how long after the commit n reverts or fixes came,
+// e.g. ", median 1.5 days later"; empty when there were none.
+func afterDays(n int, medianDays float64) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", median %.1f days later", medianDays)
+}
+
+// renderRepoMetrics prints each opt-in metric per repository, when the
+// bundle carries per-repo values (--per-repo).
+func renderRepoMetrics(b metrics.Bundle) {
+	labels, series := repoMetricTrend(b)
+	if len(series) == 0 {
+		return
+	}
+	fmt.Printf("  %sMetrics by repository:%s\n", colorDim, colorReset)
+	renderRowHeader("Metric", labels)
+	for _, s := range series {
+		fmt.Printf("  %-28s", s.Label)
+		for i := range labels {
+			fmt.Printf(" %-16s", formatMetricValue(s.Values[i], s.Unit, s.Has[i]))
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// renderDiagnostics lists the metrics that failed or could not be computed
+// (see notableDiagnostics). Errors are highlighted.
+func renderDiagnostics(diags []metrics.Diagnostic) {
+	notable := notableDiagnostics(diags)
+	if len(notable) == 0 {
+		return
+	}
+	fmt.Printf("  %sDiagnostics:%s\n", colorDim, colorReset)
+	for i, d := range notable {
+		color := colorDim
+		if d.Status == metrics.StatusError {
+			color = colorYellow
+		}
+		fmt.Printf("  %s %s%s%s\n", treeBranch(i == len(notable)-1), color, shortDiagnostic(d), colorReset)
+	}
+	fmt.Println()
+}
+
+// renderNormal returns the dimmed "(your normal: ...)" suffix for a metric,
+// or "" when no baseline-window metrics were computed.
+func renderNormal(b metrics.Bundle, key string) string {
+	note := normalNote(b, key)
+	if note == "" {
+		return ""
+	}
+	return fmt.Sprintf("  %s(%s)%s", colorDim, note, colorReset)
 }
 
 func TerminalWithRepos(combined git.RepoStats, repos []git.RepoStats, breakdown string, bundle metrics.Bundle) error {
@@ -217,49 +404,248 @@ func TerminalWithRepos(combined git.RepoStats, repos []git.RepoStats, breakdown
 	return nil
 }
 
-func CompareTerminal(comparison git.CompareStats) error {
-	beforeDays := git.WorkingDays(comparison.Before.Since, comparison.Before.Until)
-	afterDays := git.WorkingDays(comparison.After.Since, comparison.After.Until)
-
-	beforePerDay := float64(comparison.Before.Net) / float64(beforeDays)
-	afterPerDay := float64(comparison.After.Net) / float64(afterDays)
-
-	multiplier := benchmark.CalculateMultiplier(beforePerDay, afterPerDay)
+func CompareTerminal(comparison git.CompareStats, details CompareDetails) error {
+	periods := periodStats(comparison.Periods)
+	labels := periodLabels(comparison.Periods)
+	if len(periods) == 0 {
+		return fmt.Errorf("no periods to compare")
+	}
+	labelWidth := 16
+	for _, l := range labels {
+		if len(l) > labelWidth {
+			labelWidth = len(l)
+		}
+	}
 
 	fmt.Println()
 	fmt.Printf("%s%s gitrespect%s - Period Comparison\n", colorBold, colorCyan, colorReset)
 	fmt.Println(strings.Repeat("─", 50))
 	fmt.Println()
 
-	fmt.Printf("  %sPeriod%s           %sNet Lines%s   %sDays%s    %sPer Day%s\n",
-		colorDim, colorReset, colorDim, colorReset, colorDim, colorReset, colorDim, colorReset)
-	fmt.Println("  " + strings.Repeat("─", 44))
+	fmt.Printf("  %s%-*s%s %sNet Lines%s   %sDays%s    %sPer Day%s   %svs %s%s\n",
+		colorDim, labelWidth, "Period", colorReset, colorDim, colorReset, colorDim, colorReset,
+		colorDim, colorReset, colorDim, labels[0], colorReset)
+	fmt.Println("  " + strings.Repeat("─", labelWidth+44))
+
+	for i, p := range periods {
+		color := colorCyan
+		change := colorDim + "—" + colorReset
+		if i == 0 {
+			color = colorDim
+		} else {
+			change = formatMultiplier(perDay(periods[0]), perDay(p))
+		}
+		fmt.Printf("  %-*s %s%-11s%s %-6d  %s%-9.0f%s %s\n",
+			labelWidth, labels[i],
+			color, formatNumber(p.Net), colorReset,
+			git.WorkingDays(p.Since, p.Until), color, perDay(p), colorReset,
+			change)
+	}
+	fmt.Println()
+
+	if len(periods) > 2 {
+		renderTrendChart(labels, periods, labelWidth)
+	}
+
+	for i := 1; i < len(periods); i++ {
+		sig := details.SignificanceAt(i)
+		multiplier := changeVsFirst(periods, sig, i)
+		changeSign := "+"
+		changeColor := colorGreen
+		if multiplier < 1 {
+			changeSign = ""
+			changeColor = colorYellow
+		}
+		if len(periods) > 2 {
+			fmt.Printf("  %s%s vs %s%s\n", colorBold, labels[i], labels[0], colorReset)
+		}
+		fmt.Printf("  %sChange:%s %s%s%.1fx lines/day%s %s\n",
+			colorDim, colorReset, changeColor, changeSign, multiplier, colorReset,
+			formatRatioCI(sig))
+		renderSignificance(sig)
+		fmt.Println()
+	}
+
+	if series := metricTrend(details.Metrics); len(series) > 0 {
+		renderMetricTrend(labels, series)
+	}
+	if len(details.Repos) > 0 {
+		fmt.Printf("  %sRepository Breakdown:%s\n", colorBold, colorReset)
+		renderRowHeader("Repository", labels)
+		for _, r := range details.Repos {
+			renderRowTrend(filepath.Base(r.Path), labels, r.Periods)
+		}
+		fmt.Println()
+	}
+	if len(details.Members) > 0 {
+		fmt.Printf("  %sTeam Members:%s\n", colorBold, colorReset)
+		renderRowHeader("Contributor", labels)
+		for _, m := range details.Members {
+			renderRowTrend(m.Email, labels, m.Periods)
+		}
+		fmt.Println()
+		for _, m := range details.Members {
+			series := metricTrend(m.Metrics)
+			if len(series) == 0 {
+				continue
+			}
+			fmt.Printf("  %s● %s%s\n", colorBold, m.Email, colorReset)
+			renderMetricTrend(labels, series)
+		}
+	}
+	renderDiagnostics(details.Diagnostics)
+
+	return nil
+}
+
+// renderTrendChart draws one horizontal bar per period, scaled to the
+// highest lines/day.
+func renderTrendChart(labels []string, periods []git.RepoStats, labelWidth int) {
+	const width = 30
+	peak := 0.0
+	for _, p := range periods {
+		if v := perDay(p); v > peak {
+			peak = v
+		}
+	}
+	fmt.Printf("  %sTrend (lines/day):%s\n", colorDim, colorReset)
+	for i, p := range periods {
+		v := perDay(p)
+		filled := 0
+		if peak > 0 && v > 0 {
+			filled = int(v / peak * width)
+		}
+		fmt.Printf("  %-*s %s%s%s%s%s %.0f\n",
+			labelWidth, labels[i],
+			colorCyan, strings.Repeat("█", filled), colorDim, strings.Repeat("░", width-filled), colorReset, v)
+	}
+	fmt.Println()
+}
 
-	fmt.Printf("  %-16s %s%-11s%s %-6d  %s%.0f%s\n",
-		comparison.BeforeLabel,
-		colorDim, formatNumber(comparison.Before.Net), colorReset,
-		beforeDays, colorDim, beforePerDay, colorReset)
+// formatRatioCI renders the bootstrap interval for the per-day ratio, or an
+// empty string when the interval could not be computed.
+func formatRatioCI(sig metrics.Significance) string {
+	if sig.RatioLow == 0 && sig.RatioHigh == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s(%.0f%% CI %.1fx–%.1fx)%s",
+		colorDim, sig.Confidence*100, sig.RatioLow, sig.RatioHigh, colorReset)
+}
 
-	fmt.Printf("  %-16s %s%-11s%s %-6d  %s%.0f%s\n",
-		comparison.AfterLabel,
-		colorCyan, formatNumber(comparison.After.Net), colorReset,
-		afterDays, colorCyan, afterPerDay, colorReset)
+func renderSignificance(sig metrics.Significance) {
+	fmt.Printf("  %sWeekly samples:%s %d vs %d\n",
+		colorDim, colorReset, sig.BeforeWeeks, sig.AfterWeeks)
+	if sig.Insufficient {
+		fmt.Printf("  %s⚠ Not enough data:%s need %d+ weeks in each period for a meaningful test;\n",
+			colorYellow, colorReset, metrics.MinSignificanceWeeks)
+		fmt.Printf("    %streat this change as anecdotal.%s\n", colorDim, colorReset)
+		return
+	}
+	verdict := "not significant"
+	verdictColor := colorYellow
+	if sig.Significant() {
+		verdict = "significant"
+		verdictColor = colorGreen
+	}
+	fmt.Printf("  %sSignificance:%s %s%s%s (Mann-Whitney p=%.3f), %s effect (Cliff's δ=%+.2f)\n",
+		colorDim, colorReset, verdictColor, verdict, colorReset, sig.PValue, sig.Effect, sig.EffectSize)
+}
 
+// renderMetricTrend prints one row per metric with a column per period; later
+// periods show their change against the first in parentheses.
+func renderMetricTrend(labels []string, series []metricSeries) {
+	renderRowHeader("Metric", labels)
+	for _, s := range series {
+		fmt.Printf("  %-28s", s.Label)
+		for i := range labels {
+			cell := formatMetricValue(s.Values[i], s.Unit, s.Has[i])
+			if i > 0 {
+				cell += " (" + formatMetricDelta(s, i) + ")"
+			}
+			fmt.Printf(" %-16s", cell)
+		}
+		fmt.Println()
+	}
 	fmt.Println()
+}
 
-	changeSign := "+"
-	changeColor := colorGreen
-	if multiplier < 1 {
-		changeSign = ""
-		changeColor = colorYellow
+func formatMetricValue(v float64, unit string, ok bool) string {
+	if !ok {
+		return "—"
 	}
+	switch unit {
+	case "%":
+		return fmt.Sprintf("%.0f%%", v)
+	case unitPerWeek:
+		return fmt.Sprintf("%.2f/wk", v)
+	case unitLines:
+		return fmt.Sprintf("%.0f lines", v)
+	}
+	return fmt.Sprintf("%.1fd", v)
+}
 
-	fmt.Printf("  %sChange:%s %s%s%.1fx productivity %s%s\n",
-		colorDim, colorReset, changeColor, changeSign, multiplier,
-		getChangeEmoji(multiplier), colorReset)
+// formatMetricDelta renders the change of period i against the first, e.g.
+// "+12 pts" or "-0.5d", or "n/a" when either side has no data.
+func formatMetricDelta(s metricSeries, i int) string {
+	d, ok := s.Delta(i)
+	if !ok {
+		return "n/a"
+	}
+	switch s.Unit {
+	case "%":
+		return fmt.Sprintf("%+.0f pts", d)
+	case unitPerWeek:
+		return fmt.Sprintf("%+.2f/wk", d)
+	case unitLines:
+		return fmt.Sprintf("%+.0f lines", d)
+	}
+	return fmt.Sprintf("%+.1fd", d)
+}
+
+func renderRowHeader(title string, labels []string) {
+	fmt.Printf("  %s%-28s%s", colorDim, title, colorReset)
+	for _, l := range labels {
+		fmt.Printf(" %s%-16s%s", colorDim, truncate(l, 16), colorReset)
+	}
 	fmt.Println()
+	fmt.Println("  " + strings.Repeat("─", 28+17*len(labels)))
+}
 
-	return nil
+// renderRowTrend prints a per-repo or per-member line: lines/day for each
+// period, with the multiplier against the first period.
+func renderRowTrend(name string, labels []string, periods []git.RepoStats) {
+	fmt.Printf("  %-28s", truncate(name, 28))
+	for i, p := range periods {
+		cell := fmt.Sprintf("%.0f/day", perDay(p))
+		if i > 0 {
+			if m := multiplierVsFirst(periods, i); m != 0 {
+				cell += fmt.Sprintf(" (%.1fx)", m)
+			}
+		}
+		fmt.Printf(" %-16s", cell)
+	}
+	fmt.Println()
+}
+
+// formatMultiplier renders after/before as "1.8x", or "n/a" when the before
+// rate is not positive.
+func formatMultiplier(before, after float64) string {
+	m := benchmark.CalculateMultiplier(before, after)
+	if m == 0 {
+		return colorDim + "n/a" + colorReset
+	}
+	color := colorGreen
+	if m < 1 {
+		color = colorYellow
+	}
+	return fmt.Sprintf("%s%.1fx%s", color, m, colorReset)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
 }
 
 func printMonthlyBreakdown(stats git.RepoStats) {
@@ -325,41 +711,8 @@ func getMonthName(m int) string {
 	return "???"
 }
 
-func getChangeEmoji(multiplier float64) string {
-	if multiplier >= 5 {
-		return " 🚀"
-	}
-	if multiplier >= 2 {
-		return " 📈"
-	}
-	if multiplier >= 1 {
-		return ""
-	}
-	return " 📉"
-}
-
-func TeamTerminal(stats git.TeamStats, breakdown string, bundles map[string]metrics.Bundle) error {
-	// Calculate working days from combined member activity
-	var firstCommit, lastCommit time.Time
-	for _, m := range stats.Members {
-		if !m.FirstCommit.IsZero() {
-			if firstCommit.IsZero() || m.FirstCommit.Before(firstCommit) {
-				firstCommit = m.FirstCommit
-			}
-		}
-		if !m.LastCommit.IsZero() {
-			if lastCommit.IsZero() || m.LastCommit.After(lastCommit) {
-				lastCommit = m.LastCommit
-			}
-		}
-	}
-
-	var workingDays int
-	if !firstCommit.IsZero() && !lastCommit.IsZero() {
-		workingDays = git.WorkingDays(firstCommit, lastCommit)
-	} else {
-		workingDays = git.WorkingDays(stats.Since, stats.Until)
-	}
+func TeamTerminal(stats git.TeamStats, breakdown string, team metrics.Bundle, bundles map[string]metrics.Bundle) error {
+	workingDays := teamWorkingDays(stats)
 	locPerDay := float64(stats.TotalNet) / float64(workingDays)
 
 	dateRange := fmt.Sprintf("%s to %s", stats.Since.Format("Jan 2 2006"), stats.Until.Format("Jan 2 2006"))
@@ -386,6 +739,17 @@ func TeamTerminal(stats git.TeamStats, breakdown string, bundles map[string]metr
 		colorDim, colorReset, locPerDay, workingDays)
 	fmt.Println()
 
+	if team.LegacyBenchmark && len(stats.Members) > 0 {
+		renderLegacyBenchmark("Average member vs Industry", locPerDay/float64(len(stats.Members)), workingDays)
+		fmt.Println()
+	} else if team.Baseline != nil {
+		renderBaseline("Team baseline", "Team", team.Baseline)
+		fmt.Println()
+	}
+	if team.Deploy != nil {
+		renderMetrics(team)
+	}
+
 	// Member breakdown - sort by net lines descending
 	type memberEntry struct {
 		email string
@@ -399,10 +763,17 @@ func TeamTerminal(stats git.TeamStats, breakdown string, bundles map[string]metr
 		return members[i].stats.Net > members[j].stats.Net
 	})
 
+	showBaselines := !team.LegacyBenchmark && hasMemberBaselines(bundles)
 	fmt.Printf("  %sTeam Members%s\n", colorBold, colorReset)
-	fmt.Printf("  %sContributor%s                         %sNet%s       %sCommits%s  %s/day%s\n",
+	fmt.Printf("  %sContributor%s                         %sNet%s       %sCommits%s  %s/day%s",
 		colorDim, colorReset, colorDim, colorReset, colorDim, colorReset, colorDim, colorReset)
-	fmt.Println("  " + strings.Repeat("─", 56))
+	rule := 56
+	if showBaselines {
+		fmt.Printf("    %sNormal/day%s", colorDim, colorReset)
+		rule = 74
+	}
+	fmt.Println()
+	fmt.Println("  " + strings.Repeat("─", rule))
 
 	for _, m := range members {
 		memberDaily := float64(m.stats.Net) / float64(workingDays)
@@ -411,11 +782,16 @@ func TeamTerminal(stats git.TeamStats, breakdown string, bundles map[string]metr
 		if len(email) > 32 {
 			email = email[:29] + "..."
 		}
-		fmt.Printf("  %-34s %s%-10s%s %-8d %.0f\n",
+		fmt.Printf("  %-34s %s%-10s%s %-8d",
 			email,
 			colorCyan, formatNumber(m.stats.Net), colorReset,
-			m.stats.Commits,
-			memberDaily)
+			m.stats.Commits)
+		if showBaselines {
+			fmt.Printf(" %-6.0f  %s", memberDaily, formatBaselineCell(bundles[m.email].Baseline))
+		} else {
+			fmt.Printf(" %.0f", memberDaily)
+		}
+		fmt.Println()
 	}
 	fmt.Println()
 
@@ -428,6 +804,7 @@ func TeamTerminal(stats git.TeamStats, breakdown string, bundles map[string]metr
 		fmt.Printf("  %s● %s%s\n", colorBold, m.email, colorReset)
 		renderMetrics(b)
 	}
+	renderDiagnostics(team.Diagnostics)
 
 	// Team-wide monthly breakdown
 	if breakdown == "monthly" && len(stats.Monthly) > 0 {
@@ -437,7 +814,96 @@ func TeamTerminal(stats git.TeamStats, breakdown string, bundles map[string]metr
 	return nil
 }
 
+// teamWorkingDays counts working days over the members' combined activity,
+// or the whole date range when nobody committed.
+func teamWorkingDays(stats git.TeamStats) int {
+	var firstCommit, lastCommit time.Time
+	for _, m := range stats.Members {
+		if !m.FirstCommit.IsZero() {
+			if firstCommit.IsZero() || m.FirstCommit.Before(firstCommit) {
+				firstCommit = m.FirstCommit
+			}
+		}
+		if !m.LastCommit.IsZero() {
+			if lastCommit.IsZero() || m.LastCommit.After(lastCommit) {
+				lastCommit = m.LastCommit
+			}
+		}
+	}
+	if !firstCommit.IsZero() && !lastCommit.IsZero() {
+		return git.WorkingDays(firstCommit, lastCommit)
+	}
+	return git.WorkingDays(stats.Since, stats.Until)
+}
+
+// sortedMembers returns the members' emails by net lines, highest first.
+func sortedMembers(stats git.TeamStats) []string {
+	emails := make([]string, 0, len(stats.Members))
+	for email := range stats.Members {
+		emails = append(emails, email)
+	}
+	sort.Slice(emails, func(i, j int) bool {
+		if a, b := stats.Members[emails[i]].Net, stats.Members[emails[j]].Net; a != b {
+			return a > b
+		}
+		return emails[i] < emails[j]
+	})
+	return emails
+}
+
+// hasMemberBaselines reports whether any member has a personal baseline.
+func hasMemberBaselines(bundles map[string]metrics.Bundle) bool {
+	for _, b := range bundles {
+		if b.Baseline != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // hasAnyMetric reports whether the bundle carries at least one opt-in metric.
 func hasAnyMetric(b metrics.Bundle) bool {
-	return b.CommitSize != nil || b.Cadence != nil || b.LeadTime != nil || b.Churn != nil
+	return b.CommitSize != nil || b.Cadence != nil || b.LeadTime != nil || b.Deploy != nil || b.Churn != nil || b.Reverts != nil
+}
+
+// DORATerminal prints the four DORA metrics, the failures behind them and,
+// for several repositories, each one's metrics.
+func DORATerminal(d DORADetails) error {
+	fmt.Println()
+	fmt.Printf("%s%s gitrespect%s - DORA metrics\n", colorBold, colorCyan, colorReset)
+	fmt.Printf("%s%s (%s to %s), deploys from %s, lead time for %s%s\n", colorDim, d.repoNames(),
+		d.Since.Format("Jan 2 2006"), d.Until.Format("Jan 2 2006"), d.DORA.Source, d.who(), colorReset)
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Println()
+
+	for _, row := range doraRows(d.DORA) {
+		fmt.Printf("  %-24s %s%-12s%s %s(%s)%s\n", row.Label, colorCyan, row.Value, colorReset, colorDim, row.Note, colorReset)
+	}
+	fmt.Println()
+
+	if n := len(d.DORA.Failures); n > 0 {
+		fmt.Printf("  %sFailures:%s\n", colorDim, colorReset)
+		for i, f := range d.DORA.Failures {
+			name := describeFailure(f)
+			if len(d.Repos) > 1 {
+				name = filepath.Base(f.Repo) + " " + name
+			}
+			fmt.Printf("  %s %s\n", treeBranch(i == n-1), name)
+		}
+		fmt.Println()
+	}
+
+	if len(d.Repo) > 1 {
+		fmt.Printf("  %sBy repository:%s\n", colorDim, colorReset)
+		fmt.Printf("  %s%-24s %-12s %-12s %-12s %-12s%s\n", colorDim, "Repository", "Deploys/wk", "Lead time", "Failure rate", "Restore", colorReset)
+		for _, repo := range d.sortedRepos() {
+			rows := doraRows(d.Repo[repo])
+			fmt.Printf("  %-24s %-12s %-12s %-12s %-12s\n", truncate(filepath.Base(repo), 24),
+				rows[0].Value, rows[1].Value, rows[2].Value, rows[3].Value)
+		}
+		fmt.Println()
+	}
+
+	renderDiagnostics(d.Diagnostics)
+	return nil
 }
@@ -0,0 +1,54 @@
+"""Reads a Parquet file with pyarrow, or DuckDB when pyarrow is missing, and
+prints its rows as JSON for TestParquetReaders: each column's kind (string,
+int, bool or timestamp) and its values, timestamps as Unix milliseconds.
+Exits 3 when neither library is installed."""
+
+import datetime
+import json
+import sys
+
+EPOCH = datetime.datetime(1970, 1, 1, tzinfo=datetime.timezone.utc)
+
+
+def read(path):
+    try:
+        import pyarrow
+        import pyarrow.parquet
+    except ImportError:
+        pass
+    else:
+        table = pyarrow.parquet.read_table(path)
+        return "pyarrow " + pyarrow.__version__, table.column_names, [list(r.values()) for r in table.to_pylist()]
+    try:
+        import duckdb
+    except ImportError:
+        sys.exit(3)
+    rel = duckdb.read_parquet(path)
+    return "duckdb " + duckdb.__version__, rel.columns, [list(r) for r in rel.fetchall()]
+
+
+def kind(v):
+    if isinstance(v, bool):
+        return "bool"
+    if isinstance(v, int):
+        return "int"
+    if isinstance(v, datetime.datetime):
+        return "timestamp"
+    return type(v).__name__
+
+
+def plain(v):
+    if isinstance(v, datetime.datetime):
+        if v.tzinfo is None:
+            v = v.replace(tzinfo=datetime.timezone.utc)
+        return (v - EPOCH) // datetime.timedelta(milliseconds=1)
+    return v
+
+
+reader, names, rows = read(sys.argv[1])
+json.dump({
+    "reader": reader,
+    "kinds": {n: kind(rows[0][i]) for i, n in enumerate(names)} if rows else {},
+    "columns": names,
+    "rows": [[plain(v) for v in r] for r in rows],
+}, sys.stdout)
@@ -0,0 +1,198 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	"github.com/juangracia/gitrespect/internal/metrics"
+)
+
+// TrendAll charts every metric the snapshots have.
+const TrendAll = "all"
+
+// unitPerDay is the unit of lines per working day.
+const unitPerDay = "/day"
+
+// TrendPoint is one stored snapshot: its period and the report taken then.
+type TrendPoint struct {
+	Label string
+	Saved Saved
+}
+
+// trendTotals are the series every snapshot has, ahead of the opt-in
+// metrics.
+var trendTotals = []struct {
+	key   string
+	label string
+	unit  string
+	value func(git.RepoStats, metrics.Bundle) (float64, bool)
+}{
+	{"net_per_day", "Net lines/day", unitPerDay, func(s git.RepoStats, _ metrics.Bundle) (float64, bool) {
+		return perDay(s), true
+	}},
+	{"baseline_per_day", "Your normal (lines/day)", unitPerDay, func(_ git.RepoStats, b metrics.Bundle) (float64, bool) {
+		if b.Baseline == nil || b.Baseline.InsufficientHistory {
+			return 0, false
+		}
+		return b.Baseline.LOCPerDay, true
+	}},
+	{"baseline_delta_pct", "Change vs your normal", "%", func(_ git.RepoStats, b metrics.Bundle) (float64, bool) {
+		if b.Baseline == nil || b.Baseline.InsufficientHistory {
+			return 0, false
+		}
+		return b.Baseline.PercentDelta, true
+	}},
+}
+
+// trendSeries lines up the snapshots' totals, baselines and opt-in metrics,
+// of member when set (team snapshots only). A point lacking a metric, or
+// the member, is marked missing.
+func trendSeries(points []TrendPoint, member string) ([]metricSeries, error) {
+	stats := make([]git.RepoStats, len(points))
+	bundles := make([]metrics.Bundle, len(points))
+	present := make([]bool, len(points))
+	for i, p := range points {
+		s := p.Saved
+		switch {
+		case s.Kind == "report" && member == "":
+			stats[i], bundles[i], present[i] = s.Stats, s.Bundle, true
+		case s.Kind == "team" && member == "":
+			stats[i] = git.RepoStats{Since: s.Team.Since, Until: s.Team.Until, Net: s.Team.TotalNet}
+			bundles[i], present[i] = s.Bundle, true
+		case s.Kind == "team":
+			stats[i], present[i] = s.Team.Members[member]
+			bundles[i] = s.Members[member]
+		case s.Kind == "report":
+			return nil, fmt.Errorf("--member needs team snapshots")
+		default:
+			return nil, fmt.Errorf("cannot chart %s reports", s.Kind)
+		}
+	}
+
+	var out []metricSeries
+	for _, t := range trendTotals {
+		s := metricSeries{Key: t.key, Label: t.label, Unit: t.unit, Values: make([]float64, len(points)), Has: make([]bool, len(points))}
+		found := false
+		for i := range points {
+			if present[i] {
+				s.Values[i], s.Has[i] = t.value(stats[i], bundles[i])
+				found = found || s.Has[i]
+			}
+		}
+		if found {
+			out = append(out, s)
+		}
+	}
+	return append(out, metricTrend(bundles)...), nil
+}
+
+// TrendTerminal charts a metric (TrendAll for every one) across stored
+// snapshots, oldest first, each against the one before it.
+func TrendTerminal(subject string, points []TrendPoint, metric, member string) error {
+	if len(points) == 0 {
+		return fmt.Errorf("no snapshots of %s", subject)
+	}
+	series, err := trendSeries(points, member)
+	if err != nil {
+		return err
+	}
+	var charted []metricSeries
+	for _, s := range series {
+		if metric == TrendAll || s.Key == metric {
+			charted = append(charted, s)
+		}
+	}
+	if len(charted) == 0 {
+		keys := make([]string, len(series))
+		for i, s := range series {
+			keys[i] = s.Key
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("no %q in these snapshots (have %s)", metric, strings.Join(keys, ", "))
+	}
+
+	labelWidth := 16
+	for _, p := range points {
+		labelWidth = max(labelWidth, len(p.Label))
+	}
+
+	fmt.Println()
+	fmt.Printf("%s%s gitrespect%s - Trend\n", colorBold, colorCyan, colorReset)
+	who := subject
+	if member != "" {
+		who = member + " (" + subject + ")"
+	}
+	fmt.Printf("%s%s, %d %s%s\n", colorDim, who, len(points), plural(len(points), "snapshot"), colorReset)
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Println()
+
+	for _, s := range charted {
+		renderTrendSeries(points, s, labelWidth)
+	}
+
+	if metric != TrendAll && len(series) > 1 {
+		var others []string
+		for _, s := range series {
+			if s.Key != metric {
+				others = append(others, s.Key)
+			}
+		}
+		fmt.Printf("  %sAlso stored (--metric):%s %s\n\n", colorDim, colorReset, strings.Join(others, ", "))
+	}
+	return nil
+}
+
+// renderTrendSeries draws one bar per snapshot, scaled to the largest
+// magnitude, with the change from the previous snapshot that had a value.
+func renderTrendSeries(points []TrendPoint, s metricSeries, labelWidth int) {
+	const width = 30
+	peak := 0.0
+	for i, v := range s.Values {
+		if s.Has[i] {
+			peak = math.Max(peak, math.Abs(v))
+		}
+	}
+	fmt.Printf("  %s%s:%s\n", colorDim, s.Label, colorReset)
+	prev := -1
+	for i, p := range points {
+		if !s.Has[i] {
+			fmt.Printf("  %-*s %s%s%s\n", labelWidth, p.Label, colorDim, "no data", colorReset)
+			continue
+		}
+		v := s.Values[i]
+		filled := 0
+		if peak > 0 {
+			filled = int(math.Abs(v) / peak * width)
+		}
+		change := ""
+		if prev >= 0 {
+			d := metricSeries{Unit: s.Unit, Values: []float64{s.Values[prev], v}, Has: []bool{true, true}}
+			change = colorDim + " (" + formatTrendDelta(d) + ")" + colorReset
+		}
+		fmt.Printf("  %-*s %s%s%s%s%s %s%s\n",
+			labelWidth, p.Label,
+			colorCyan, strings.Repeat("█", filled), colorDim, strings.Repeat("░", width-filled), colorReset,
+			formatTrendValue(v, s.Unit), change)
+		prev = i
+	}
+	fmt.Println()
+}
+
+func formatTrendValue(v float64, unit string) string {
+	if unit == unitPerDay {
+		return fmt.Sprintf("%.0f/day", v)
+	}
+	return formatMetricValue(v, unit, true)
+}
+
+// formatTrendDelta renders the change from the first value of d to its
+// second.
+func formatTrendDelta(d metricSeries) string {
+	if d.Unit == unitPerDay {
+		return fmt.Sprintf("%+.0f/day", d.Values[1]-d.Values[0])
+	}
+	return formatMetricDelta(d, 1)
+}
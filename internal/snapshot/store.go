@@ -0,0 +1,148 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/juangracia/gitrespect/internal/git"
+	bolt "go.etcd.io/bbolt"
+)
+
+// StoreEnv overrides where snapshots are kept.
+const StoreEnv = "GITRESPECT_STORE"
+
+// Snapshot is one run's JSON report, kept under its subject and period.
+type Snapshot struct {
+	Subject string          `json:"subject"`
+	Since   string          `json:"since"` // YYYY-MM-DD
+	Until   string          `json:"until"`
+	TakenAt time.Time       `json:"taken_at"`
+	Report  json.RawMessage `json:"report"`
+}
+
+// Period names the snapshot's date range, e.g. 2026-07-01..2026-09-30.
+func (s Snapshot) Period() string {
+	return s.Since + ".." + s.Until
+}
+
+// Subject identifies what a report covers: an author, or a team, over a set
+// of repositories, each named by git.RepoID so that moving or re-cloning it
+// keeps its snapshots. Order doesn't matter.
+func Subject(authors []string, team bool, repoPaths []string) string {
+	authors = slices.Sorted(slices.Values(authors))
+	var repos []string
+	for _, p := range repoPaths {
+		repos = append(repos, git.RepoID(p))
+	}
+	slices.Sort(repos)
+	who := strings.Join(authors, ", ")
+	if team {
+		who = "team " + who
+	}
+	return who + " in " + strings.Join(repos, ", ")
+}
+
+// DefaultPath returns $GITRESPECT_STORE, else snapshots.db in the user's
+// config directory.
+func DefaultPath() (string, error) {
+	if p := os.Getenv(StoreEnv); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no snapshot store location (set %s): %w", StoreEnv, err)
+	}
+	return filepath.Join(dir, "gitrespect", "snapshots.db"), nil
+}
+
+// Store is a bbolt database with a bucket per subject, holding its
+// snapshots keyed by period.
+type Store struct {
+	db *bolt.DB
+}
+
+// ErrNoStore is returned by OpenReadOnly when no snapshot was ever taken.
+var ErrNoStore = errors.New("no snapshot store")
+
+// Open opens the store at path for writing, creating it if needed.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot store: %w", err)
+	}
+	return open(path, false)
+}
+
+// OpenReadOnly opens an existing store, sharing it with other readers.
+func OpenReadOnly(path string) (*Store, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w at %s", ErrNoStore, path)
+	}
+	return open(path, true)
+}
+
+func open(path string, readOnly bool) (*Store, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: readOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot store %s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put stores a snapshot, replacing any earlier one of the same subject and
+// period.
+func (s *Store) Put(snap Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(snap.Subject))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(snap.Period()), data)
+	})
+}
+
+// List returns the subject's snapshots, oldest period first.
+func (s *Store) List(subject string) ([]Snapshot, error) {
+	var out []Snapshot
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(subject))
+		if b == nil {
+			return nil
+		}
+		// Periods are keyed since..until, so keys sort by date.
+		return b.ForEach(func(k, v []byte) error {
+			var snap Snapshot
+			if err := json.Unmarshal(v, &snap); err != nil {
+				return fmt.Errorf("snapshot %s: %w", k, err)
+			}
+			out = append(out, snap)
+			return nil
+		})
+	})
+	return out, err
+}
+
+// Subjects returns every subject with snapshots, sorted.
+func (s *Store) Subjects() ([]string, error) {
+	var out []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			out = append(out, string(name))
+			return nil
+		})
+	})
+	return out, err
+}
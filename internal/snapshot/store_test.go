@@ -0,0 +1,94 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSubject(t *testing.T) {
+	a := Subject([]string{"b@x.com", "a@x.com"}, true, []string{"/src/web", "/src/api"})
+	b := Subject([]string{"a@x.com", "b@x.com"}, true, []string{"/src/api", "/src/web"})
+	if a != b {
+		t.Errorf("order matters: %q != %q", a, b)
+	}
+	if want := "team a@x.com, b@x.com in /src/api, /src/web"; a != want {
+		t.Errorf("Subject = %q, want %q", a, want)
+	}
+	if s := Subject([]string{"a@x.com"}, false, []string{"/src/api"}); s != "a@x.com in /src/api" {
+		t.Errorf("Subject = %q", s)
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "snapshots.db")
+	if _, err := OpenReadOnly(path); !errors.Is(err, ErrNoStore) {
+		t.Fatalf("OpenReadOnly of a missing store: %v, want ErrNoStore", err)
+	}
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	taken := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	put := func(subject, since, until string, net int) {
+		t.Helper()
+		report, _ := json.Marshal(map[string]int{"net": net})
+		if err := s.Put(Snapshot{Subject: subject, Since: since, Until: until, TakenAt: taken, Report: report}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("a", "2026-07-01", "2026-09-30", 3)
+	put("a", "2026-01-01", "2026-03-31", 1)
+	put("a", "2026-04-01", "2026-06-30", 0)
+	put("a", "2026-04-01", "2026-06-30", 2) // retaken
+	put("b", "2026-01-01", "2026-03-31", 9)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	subjects, err := s.Subjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(subjects, []string{"a", "b"}) {
+		t.Errorf("Subjects = %v", subjects)
+	}
+
+	snaps, err := s.List("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var periods []string
+	var nets []int
+	for _, snap := range snaps {
+		var r map[string]int
+		if err := json.Unmarshal(snap.Report, &r); err != nil {
+			t.Fatal(err)
+		}
+		periods = append(periods, snap.Period())
+		nets = append(nets, r["net"])
+		if !snap.TakenAt.Equal(taken) {
+			t.Errorf("%s: TakenAt = %v", snap.Period(), snap.TakenAt)
+		}
+	}
+	if want := []string{"2026-01-01..2026-03-31", "2026-04-01..2026-06-30", "2026-07-01..2026-09-30"}; !slices.Equal(periods, want) {
+		t.Errorf("periods = %v, want %v", periods, want)
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(nets, want) {
+		t.Errorf("nets = %v, want %v", nets, want)
+	}
+
+	if snaps, err := s.List("nobody"); err != nil || len(snaps) != 0 {
+		t.Errorf("List of an unknown subject = %v, %v", snaps, err)
+	}
+}